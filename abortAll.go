@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"fmt"
+)
+
+// AbortAll returns the connection to a clean state, e.g. in
+// error-recovery paths where the state of the connection is unknown:
+//
+//  1. The remaining result sets of the last command are cancelled
+//     through an attention.
+//  2. All open transactions are rolled back, including nested
+//     transactions, leaving @@trancount at zero.
+//  3. A row limit set by ExecWithRowLimit is reset, the chained mode
+//     and the arithmetic options of the properties are applied again,
+//     as in ResetSession.
+//
+// Transactions started through the driver are rolled back as well and
+// must not be committed or rolled back afterwards.
+//
+// AbortAll must not be called while a command is executing on another
+// goroutine, use Cancel instead.
+func (c *Conn) AbortAll(ctx context.Context) error {
+	if c.activeRows != nil && !c.activeRows.finished {
+		c.activeRows.finished = true
+		if err := c.sendAttention(ctx); err != nil {
+			return fmt.Errorf("go-ase: error cancelling active command: %w", err)
+		}
+	}
+
+	if err := c.execNoRows(ctx, "if @@trancount > 0 rollback transaction"); err != nil {
+		return fmt.Errorf("go-ase: error rolling back transactions: %w", err)
+	}
+	c.resetTxTracking()
+
+	if err := c.resetRowLimit(ctx); err != nil {
+		return fmt.Errorf("go-ase: error resetting row limit: %w", err)
+	}
+
+	if err := c.resetChained(ctx); err != nil {
+		return fmt.Errorf("go-ase: error restoring chained mode: %w", err)
+	}
+
+	if err := c.setArithOptions(ctx); err != nil {
+		return fmt.Errorf("go-ase: error applying arithmetic options: %w", err)
+	}
+
+	return nil
+}
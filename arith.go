@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// checkArithOption returns an error if value is not empty and not one
+// of the values accepted for the property name.
+func checkArithOption(name, value string) error {
+	switch value {
+	case "", "on", "off":
+		return nil
+	default:
+		return fmt.Errorf("go-ase: invalid %s %q, expected on or off", name, value)
+	}
+}
+
+// arithOptionsQuery returns the batch setting the arithabort and
+// arithignore options for arithmetic overflows and divisions by zero
+// of info, or an empty string if neither is set.
+func arithOptionsQuery(info *Info) string {
+	stmts := []string{}
+
+	if info.ArithAbort != "" {
+		stmts = append(stmts, "set arithabort arith_overflow "+info.ArithAbort)
+	}
+
+	if info.ArithIgnore != "" {
+		stmts = append(stmts, "set arithignore arith_overflow "+info.ArithIgnore)
+	}
+
+	return strings.Join(stmts, " ")
+}
+
+// setArithOptions sets the arithabort and arithignore options of the
+// session if the respective properties are set.
+//
+// The options are set when connecting and again before the connection
+// is reused, as they may have been changed by the previous user.
+func (c *Conn) setArithOptions(ctx context.Context) error {
+	query := arithOptionsQuery(c.Info)
+	if query == "" {
+		return nil
+	}
+
+	return c.execNoRows(ctx, query)
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import "testing"
+
+func TestArithOptionsQuery(t *testing.T) {
+	cases := map[string]struct {
+		abort, ignore string
+		expect        string
+	}{
+		"unset":  {"", "", ""},
+		"abort":  {"off", "", "set arithabort arith_overflow off"},
+		"ignore": {"", "on", "set arithignore arith_overflow on"},
+		"both":   {"off", "on", "set arithabort arith_overflow off set arithignore arith_overflow on"},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			query := arithOptionsQuery(&Info{ArithAbort: cas.abort, ArithIgnore: cas.ignore})
+			if query != cas.expect {
+				t.Errorf("Expected %q, received %q", cas.expect, query)
+			}
+		})
+	}
+}
+
+func TestCheckArithOption(t *testing.T) {
+	cases := map[string]struct {
+		value string
+		valid bool
+	}{
+		"empty":   {"", true},
+		"on":      {"on", true},
+		"off":     {"off", true},
+		"invalid": {"true", false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			err := checkArithOption("arithabort", cas.value)
+			if (err == nil) != cas.valid {
+				t.Errorf("Expected valid %t, received %v", cas.valid, err)
+			}
+		})
+	}
+}
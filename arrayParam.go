@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+// arrayParamCounter is used to generate unique names for the
+// temporary tables created by WithArrayParam.
+var arrayParamCounter uint64
+
+// WithArrayParam creates a temporary table with the single column
+// `value`, inserts the passed values and returns the name of the table
+// and a function dropping the table.
+//
+// ASE does not support array parameters. Instead of generating
+// statements with large IN lists the temporary table can be joined:
+//
+//	name, cleanup, err := c.WithArrayParam(ctx, ids)
+//	if err != nil {
+//		return err
+//	}
+//	defer cleanup()
+//
+//	rows, _, err := c.DirectExec(ctx, "select t.* from tab t join "+name+" a on t.id = a.value")
+//
+// The values are inserted one by one through a prepared statement, as
+// the bulk-copy protocol is not implemented - this requires a round
+// trip per value.
+//
+// All values must be of the same type, which determines the type of the
+// column. NULL values are allowed.
+// The table only exists on the connection it was created on.
+//
+// If inserting the values fails the table is dropped and an error of
+// dropping it is appended to the returned error.
+func (c *Conn) WithArrayParam(ctx context.Context, values []driver.Value) (string, func() error, error) {
+	converted := make([]driver.Value, len(values))
+	for i, value := range values {
+		var err error
+		converted[i], err = asetypes.DefaultValueConverter.ConvertValue(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("go-ase: error converting value %d: %w", i, err)
+		}
+	}
+
+	columnType, err := arrayColumnType(converted)
+	if err != nil {
+		return "", nil, fmt.Errorf("go-ase: error determining array type: %w", err)
+	}
+
+	name := fmt.Sprintf("#go_ase_array_%d", atomic.AddUint64(&arrayParamCounter, 1))
+
+	if _, err := c.ExecContext(ctx, fmt.Sprintf("create table %s (value %s null)", name, columnType), nil); err != nil {
+		return "", nil, fmt.Errorf("go-ase: error creating temporary table %s: %w", name, err)
+	}
+
+	cleanup := func() error {
+		if _, err := c.ExecContext(context.Background(), "drop table "+name, nil); err != nil {
+			return fmt.Errorf("go-ase: error dropping temporary table %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if len(converted) == 0 {
+		return name, cleanup, nil
+	}
+
+	if err := c.insertArray(ctx, name, converted); err != nil {
+		if cleanupErr := cleanup(); cleanupErr != nil {
+			return "", nil, fmt.Errorf("%w (%v)", err, cleanupErr)
+		}
+		return "", nil, err
+	}
+
+	return name, cleanup, nil
+}
+
+// insertArray inserts the values into the temporary table.
+func (c *Conn) insertArray(ctx context.Context, name string, values []driver.Value) error {
+	stmt, err := c.NewStmt(ctx, "", fmt.Sprintf("insert into %s (value) values (?)", name), true)
+	if err != nil {
+		return fmt.Errorf("go-ase: error preparing insert into %s: %w", name, err)
+	}
+	defer stmt.Close()
+
+	for i, value := range values {
+		if _, err := stmt.ExecContext(ctx, []driver.NamedValue{{Ordinal: 1, Value: value}}); err != nil {
+			return fmt.Errorf("go-ase: error inserting value %d into %s: %w", i, name, err)
+		}
+	}
+
+	return nil
+}
+
+// arrayColumnType returns the column type to store the passed values.
+func arrayColumnType(values []driver.Value) (string, error) {
+	var first driver.Value
+	maxLength := 1
+
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+
+		if first == nil {
+			first = value
+		} else if reflect.TypeOf(value) != reflect.TypeOf(first) {
+			return "", fmt.Errorf("value %d is of type %T, expected %T", i, value, first)
+		}
+
+		switch typed := value.(type) {
+		case string:
+			if len(typed) > maxLength {
+				maxLength = len(typed)
+			}
+		case []byte:
+			if len(typed) > maxLength {
+				maxLength = len(typed)
+			}
+		}
+	}
+
+	switch typed := first.(type) {
+	case nil:
+		// Only NULL values or no values at all.
+		return "int", nil
+	case int8, int16, int32, int64, uint8, uint16, uint32:
+		return "bigint", nil
+	case uint64:
+		return "unsigned bigint", nil
+	case float32, float64:
+		return "float", nil
+	case bool:
+		return "bit", nil
+	case string:
+		return fmt.Sprintf("varchar(%d)", maxLength), nil
+	case []byte:
+		return fmt.Sprintf("varbinary(%d)", maxLength), nil
+	case time.Time:
+		return "bigdatetime", nil
+	case *asetypes.Decimal:
+		return fmt.Sprintf("numeric(%d, %d)", typed.Precision, typed.Scale), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %T", first)
+}
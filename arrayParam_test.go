@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestArrayColumnType(t *testing.T) {
+	cases := map[string]struct {
+		values []driver.Value
+		expect string
+		err    bool
+	}{
+		"empty":       {nil, "int", false},
+		"only null":   {[]driver.Value{nil, nil}, "int", false},
+		"int":         {[]driver.Value{int64(1), nil, int64(3)}, "bigint", false},
+		"uint64":      {[]driver.Value{uint64(1)}, "unsigned bigint", false},
+		"float":       {[]driver.Value{0.5}, "float", false},
+		"string":      {[]driver.Value{"a", "abc", nil}, "varchar(3)", false},
+		"bytes":       {[]driver.Value{[]byte{1, 2}}, "varbinary(2)", false},
+		"time":        {[]driver.Value{time.Now()}, "bigdatetime", false},
+		"mixed types": {[]driver.Value{int64(1), "a"}, "", true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := arrayColumnType(cas.values)
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error, received %q", recv)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %q, received %q", cas.expect, recv)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// Cancel aborts the command currently executing on the connection by
+// sending an attention to the server. It may be called from another
+// goroutine than the one executing the command.
+//
+// The aborted command returns ErrCancelled, or if the command already
+// finished before the attention was processed the acknowledgement is
+// consumed by the next command. The connection stays usable in both
+// cases.
+//
+// Cancel is a no-op if no command is executing or if the command is
+// still being sent. Commands executed through cursors cannot be
+// cancelled.
+func (c *Conn) Cancel() error {
+	c.cancelLock.Lock()
+	defer c.cancelLock.Unlock()
+
+	if c.attention {
+		return nil
+	}
+
+	switch {
+	case atomic.LoadInt32(&c.inUse) == connReceiving:
+		// The command is waiting for its response - release blocks
+		// until the attention is sent.
+	case atomic.CompareAndSwapInt32(&c.inUse, connIdle, connSending):
+		// No command is executing, only the remaining result sets of
+		// a previous command can be aborted.
+		defer atomic.StoreInt32(&c.inUse, connIdle)
+
+		if c.activeRows == nil || c.activeRows.finished {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	if err := c.signalAttention(); err != nil {
+		return fmt.Errorf("go-ase: error cancelling command: %w", err)
+	}
+
+	return nil
+}
+
+// signalAttention sends an attention without waiting for the
+// acknowledgement, unless an attention is already pending. The caller
+// must hold cancelLock.
+func (c *Conn) signalAttention() error {
+	if c.attention {
+		return nil
+	}
+
+	if err := c.sendAttentionPacket(); err != nil {
+		return err
+	}
+	c.attention = true
+
+	return nil
+}
+
+// attentionAcknowledged reports if pkg acknowledges an attention sent
+// by Cancel, in which case the attention is no longer pending.
+func (c *Conn) attentionAcknowledged(pkg tds.Package) bool {
+	done, ok := pkg.(*tds.DonePackage)
+	if !ok || done.Status&tds.TDS_DONE_ATTN != tds.TDS_DONE_ATTN {
+		return false
+	}
+
+	c.cancelLock.Lock()
+	defer c.cancelLock.Unlock()
+	c.attention = false
+
+	return true
+}
+
+// recvPendingAttention consumes the acknowledgement of an attention
+// sent by Cancel that was not received by the aborted command.
+func (c *Conn) recvPendingAttention() error {
+	c.cancelLock.Lock()
+	pending := c.attention
+	c.cancelLock.Unlock()
+
+	if !pending {
+		return nil
+	}
+
+	if err := c.recvAttentionAck(context.Background()); err != nil {
+		return fmt.Errorf("go-ase: error receiving pending attention acknowledgement: %w", err)
+	}
+
+	c.cancelLock.Lock()
+	c.attention = false
+	c.cancelLock.Unlock()
+
+	if c.activeRows != nil {
+		c.activeRows.finished = true
+	}
+
+	return nil
+}
+
+// sendAttention sends an attention to the server, signaling it to
+// abort the current command, and consumes all packages until the
+// server acknowledges the attention.
+func (c *Conn) sendAttention(ctx context.Context) error {
+	c.cancelLock.Lock()
+	// If Cancel already sent an attention only its acknowledgement
+	// must be received.
+	var err error
+	if !c.attention {
+		err = c.sendAttentionPacket()
+	}
+	c.attention = false
+	c.cancelLock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return c.recvAttentionAck(ctx)
+}
+
+// abortOnContext aborts the current command with an attention if err
+// was caused by the end of ctx, e.g. when its deadline expired while
+// the server executes `waitfor delay`, and returns an error wrapping
+// the error of ctx. Other errors are returned as-is.
+//
+// The packages of the command are not consumed when reading fails
+// due to the context, hence the command must be aborted for the
+// connection to stay usable.
+func (c *Conn) abortOnContext(ctx context.Context, err error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil || !errors.Is(err, ctxErr) {
+		return err
+	}
+
+	// The attention is sent and acknowledged independently of the
+	// ended context.
+	if attnErr := c.sendAttention(context.Background()); attnErr != nil {
+		atomic.StoreInt32(&c.broken, 1)
+		return fmt.Errorf("go-ase: error aborting command after the context ended (%v): %w", attnErr, ctxErr)
+	}
+
+	return fmt.Errorf("go-ase: command aborted after the context ended: %w", ctxErr)
+}
+
+// attentionHeader is the header-only packet signaling an attention.
+var attentionHeader = tds.PacketHeader{
+	MsgType: tds.TDS_BUF_ATTN,
+	Status:  tds.TDS_BUFSTAT_EOM,
+	Length:  tds.PacketHeaderSize,
+}
+
+// sendAttentionPacket sends an attention without waiting for the
+// acknowledgement.
+//
+// The attention is written as header-only packet directly to the
+// network connection, as tds.Channel can only send packets with data.
+// sendLock prevents the packet from being written in between the
+// packets sent through the channel.
+func (c *Conn) sendAttentionPacket() error {
+	c.sendLock.Lock()
+	defer c.sendLock.Unlock()
+
+	c.packages.add(true, tds.HeaderOnlyPackage{Header: attentionHeader})
+	if _, err := attentionHeader.WriteTo(c.netConn); err != nil {
+		return c.checkConnError(fmt.Errorf("error sending attention: %w", err))
+	}
+
+	return nil
+}
+
+// netConnOf returns the network connection of conn.
+//
+// tds.Conn does not expose its network connection, hence the
+// unexported field is read through reflection. The field is only set
+// by tds.NewConn.
+func netConnOf(conn *tds.Conn) (io.Writer, error) {
+	field := reflect.ValueOf(conn).Elem().FieldByName("conn")
+	if !field.IsValid() || field.Kind() != reflect.Interface {
+		return nil, errors.New("tds.Conn has no network connection field")
+	}
+
+	if field.IsNil() {
+		return nil, errors.New("network connection of tds.Conn is not set")
+	}
+
+	w, ok := reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Interface().(io.Writer)
+	if !ok {
+		return nil, fmt.Errorf("network connection of tds.Conn is not an io.Writer: %s", field.Type())
+	}
+
+	return w, nil
+}
+
+// recvAttentionAck consumes all packages until the server acknowledges
+// an attention with a DonePackage with the status TDS_DONE_ATTN.
+func (c *Conn) recvAttentionAck(ctx context.Context) error {
+	_, err := c.nextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
+		done, ok := pkg.(*tds.DonePackage)
+		if !ok {
+			return false, nil
+		}
+
+		return done.Status&tds.TDS_DONE_ATTN == tds.TDS_DONE_ATTN, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error receiving attention acknowledgement: %w", err)
+	}
+
+	// Verify that no packages of the aborted command are left over,
+	// otherwise they would be read by the next command.
+	pkg, err := c.channel.NextPackage(ctx, false)
+	if err == nil {
+		return fmt.Errorf("received package after attention acknowledgement: %v", pkg)
+	}
+
+	if !errors.Is(err, tds.ErrNoPackageReady) {
+		return fmt.Errorf("error verifying channel state after attention: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestCancel(t *testing.T) {
+	integration.TestForEachDB("TestCancel", t, testCancel)
+}
+
+func testCancel(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		if err := c.Cancel(); err != nil {
+			return fmt.Errorf("error cancelling idle connection: %w", err)
+		}
+
+		go func() {
+			time.Sleep(time.Second)
+			if err := c.Cancel(); err != nil {
+				t.Errorf("Error cancelling command: %v", err)
+			}
+		}()
+
+		start := time.Now()
+		_, _, err := c.DirectExec(context.Background(), "waitfor delay '00:00:30'")
+		if !errors.Is(err, ErrCancelled) {
+			return fmt.Errorf("expected ErrCancelled, received %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 20*time.Second {
+			return fmt.Errorf("command was not cancelled, returned after %v", elapsed)
+		}
+
+		rows, _, err := c.DirectExec(context.Background(), "select 1")
+		if err != nil {
+			return fmt.Errorf("error executing command after cancelling: %w", err)
+		}
+		return rows.Close()
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+// TestCancelRace cancels commands at varying points of their
+// execution. Run with -race to detect unsynchronized access between
+// Cancel and the goroutine executing the command.
+func TestCancelRace(t *testing.T) {
+	integration.TestForEachDB("TestCancelRace", t, testCancelRace)
+}
+
+func testCancelRace(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		for i := 0; i < 20; i++ {
+			done := make(chan struct{})
+			go func(delay time.Duration) {
+				defer close(done)
+				time.Sleep(delay)
+				if err := c.Cancel(); err != nil {
+					t.Errorf("Error cancelling command: %v", err)
+				}
+			}(time.Duration(i) * 10 * time.Millisecond)
+
+			rows, _, err := c.DirectExec(context.Background(), "waitfor delay '00:00:00.100' select 1")
+			if err == nil {
+				_, err = rows.(*Rows).ReadAll()
+				rows.Close()
+			}
+			<-done
+
+			if err != nil && !errors.Is(err, ErrCancelled) {
+				return fmt.Errorf("expected ErrCancelled or no error, received %v", err)
+			}
+
+			rows, _, err = c.DirectExec(context.Background(), "select 1")
+			if err != nil {
+				return fmt.Errorf("error executing command after cancelling: %w", err)
+			}
+			if err := rows.Close(); err != nil {
+				return fmt.Errorf("error closing rows after cancelling: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestWaitforDeadline(t *testing.T) {
+	integration.TestForEachDB("TestWaitforDeadline", t, testWaitforDeadline)
+}
+
+func testWaitforDeadline(t *testing.T, db *sql.DB, tableName string) {
+	cases := map[string]struct {
+		query string
+		read  bool
+	}{
+		// The deadline expires before the first token is received.
+		"waitfor delay": {"waitfor delay '00:00:30'", false},
+		// The deadline expires while reading the result sets.
+		"waitfor between result sets": {"select 1 waitfor delay '00:00:30' select 2", true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			conn, err := db.Conn(context.Background())
+			if err != nil {
+				t.Errorf("Error getting connection: %v", err)
+				return
+			}
+			defer conn.Close()
+
+			err = conn.Raw(func(driverConn interface{}) error {
+				c := driverConn.(*Conn)
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				start := time.Now()
+				rows, _, err := c.DirectExec(ctx, cas.query)
+				if cas.read && err == nil {
+					for err == nil {
+						_, err = rows.(*Rows).ReadAll()
+						if err == nil {
+							err = rows.(*Rows).NextResultSet()
+						}
+					}
+				}
+
+				if !errors.Is(err, context.DeadlineExceeded) {
+					return fmt.Errorf("expected context.DeadlineExceeded, received %v", err)
+				}
+
+				if elapsed := time.Since(start); elapsed > 20*time.Second {
+					return fmt.Errorf("command was not aborted, returned after %v", elapsed)
+				}
+
+				if rows != nil {
+					rows.Close()
+				}
+
+				rows, _, err = c.DirectExec(context.Background(), "select 1")
+				if err != nil {
+					return fmt.Errorf("error executing command after the deadline: %w", err)
+				}
+				return rows.Close()
+			})
+			if err != nil {
+				t.Errorf("%v", err)
+			}
+		})
+	}
+
+	// database/sql closes the rows once the context ends.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := db.ExecContext(ctx, "waitfor delay '00:00:30'"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, received %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 20*time.Second {
+		t.Errorf("Command was not aborted, returned after %v", elapsed)
+	}
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+// Interface satisfaction checks.
+var (
+	_ sql.Scanner = (*ratScanner)(nil)
+)
+
+// maxDecimalDigits is the maximum precision of numeric and decimal
+// values.
+const maxDecimalDigits = 38
+
+// AsRat returns a sql.Scanner scanning numeric, decimal and money
+// values exactly into r:
+//
+//	r := new(big.Rat)
+//	db.QueryRow("select amount from orders where id = ?", id).Scan(ase.AsRat(r))
+//
+// Values returned as strings, see the property numericasstring, and
+// integers are scanned as well. Scanning NULL into r returns an error.
+func AsRat(r *big.Rat) sql.Scanner {
+	return &ratScanner{r: r}
+}
+
+type ratScanner struct {
+	r *big.Rat
+}
+
+// Scan implements the sql.Scanner interface.
+func (scanner *ratScanner) Scan(src interface{}) error {
+	var s string
+
+	switch typed := src.(type) {
+	case *asetypes.Decimal:
+		if typed == nil {
+			return fmt.Errorf("go-ase: cannot scan NULL into *big.Rat")
+		}
+		s = typed.String()
+	case string:
+		s = typed
+	case int64, int32, int16, uint8, uint16, uint32, uint64:
+		s = fmt.Sprint(typed)
+	default:
+		return fmt.Errorf("go-ase: cannot scan %T into *big.Rat", src)
+	}
+
+	if _, ok := scanner.r.SetString(s); !ok {
+		return fmt.Errorf("go-ase: cannot scan %q into *big.Rat", s)
+	}
+
+	return nil
+}
+
+// isBigNumber reports if value is a *big.Int or *big.Rat.
+func isBigNumber(value interface{}) bool {
+	switch value.(type) {
+	case *big.Int, *big.Rat:
+		return true
+	default:
+		return false
+	}
+}
+
+// bigRat returns a *big.Int or *big.Rat as *big.Rat.
+func bigRat(value interface{}) (*big.Rat, error) {
+	switch typed := value.(type) {
+	case *big.Int:
+		if typed == nil {
+			return nil, fmt.Errorf("nil *big.Int")
+		}
+		return new(big.Rat).SetInt(typed), nil
+	case *big.Rat:
+		if typed == nil {
+			return nil, fmt.Errorf("nil *big.Rat")
+		}
+		return typed, nil
+	default:
+		return nil, fmt.Errorf("%T is not a *big.Int or *big.Rat", value)
+	}
+}
+
+// bigDecimal converts a *big.Int or *big.Rat to a decimal with the
+// passed precision and scale.
+//
+// An error is returned if the value has more fractional digits than
+// scale or more integral digits than precision-scale.
+func bigDecimal(value interface{}, precision, scale int) (*asetypes.Decimal, error) {
+	r, err := bigRat(value)
+	if err != nil {
+		return nil, err
+	}
+
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+	if !scaled.IsInt() {
+		return nil, fmt.Errorf("%s cannot be converted to decimal(%d, %d) without truncation", r.RatString(), precision, scale)
+	}
+
+	n := scaled.Num()
+	if new(big.Int).Abs(n).Cmp(pow10(precision)) >= 0 {
+		return nil, fmt.Errorf("%s exceeds the precision of decimal(%d, %d)", r.RatString(), precision, scale)
+	}
+
+	return asetypes.NewDecimalString(precision, scale, scaledString(n, scale))
+}
+
+// bigDecimalExact converts a *big.Int or *big.Rat to a decimal with
+// the smallest scale and precision representing it exactly.
+func bigDecimalExact(value interface{}) (*asetypes.Decimal, error) {
+	r, err := bigRat(value)
+	if err != nil {
+		return nil, err
+	}
+
+	for scale := 0; scale <= maxDecimalDigits; scale++ {
+		scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+		if !scaled.IsInt() {
+			continue
+		}
+
+		precision := len(new(big.Int).Abs(scaled.Num()).String())
+		if precision < scale {
+			precision = scale
+		}
+		if precision > maxDecimalDigits {
+			break
+		}
+
+		return bigDecimal(r, precision, scale)
+	}
+
+	return nil, fmt.Errorf("%s cannot be represented as decimal with at most %d digits", r.RatString(), maxDecimalDigits)
+}
+
+// scaledString returns the decimal string of n divided by 10^scale.
+func scaledString(n *big.Int, scale int) string {
+	digits := new(big.Int).Abs(n).String()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	s := digits
+	if scale > 0 {
+		s = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+
+	if n.Sign() < 0 {
+		s = "-" + s
+	}
+
+	return s
+}
+
+// pow10 returns 10^n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
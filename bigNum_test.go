@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+func TestBigDecimal(t *testing.T) {
+	cases := map[string]struct {
+		value            interface{}
+		precision, scale int
+		expect           string
+		expectErr        bool
+	}{
+		"int":               {big.NewInt(42), 10, 2, "42.0", false},
+		"negative int":      {big.NewInt(-42), 10, 0, "-42.0", false},
+		"rat":               {big.NewRat(1, 8), 10, 3, "0.125", false},
+		"negative rat":      {big.NewRat(-1, 4), 10, 2, "-0.25", false},
+		"rat padded":        {big.NewRat(1, 2), 10, 4, "0.5", false},
+		"truncation":        {big.NewRat(1, 3), 38, 10, "", true},
+		"scale too small":   {big.NewRat(1, 8), 10, 2, "", true},
+		"exceeds precision": {big.NewInt(1000), 5, 2, "", true},
+		"fills precision":   {big.NewInt(999), 5, 2, "999.0", false},
+		"nil":               {(*big.Int)(nil), 10, 2, "", true},
+		"not a big number":  {int64(1), 10, 2, "", true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			dec, err := bigDecimal(cas.value, cas.precision, cas.scale)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if dec.Precision != cas.precision || dec.Scale != cas.scale {
+				t.Errorf("Expected decimal(%d, %d), received decimal(%d, %d)", cas.precision, cas.scale, dec.Precision, dec.Scale)
+			}
+
+			if dec.String() != cas.expect {
+				t.Errorf("Expected %s, received %s", cas.expect, dec.String())
+			}
+		})
+	}
+}
+
+func TestBigDecimalExact(t *testing.T) {
+	cases := map[string]struct {
+		value            interface{}
+		precision, scale int
+		expectErr        bool
+	}{
+		"int":       {big.NewInt(12345), 5, 0, false},
+		"zero":      {big.NewInt(0), 1, 0, false},
+		"fraction":  {big.NewRat(-314, 100), 3, 2, false},
+		"small":     {big.NewRat(1, 1000), 3, 3, false},
+		"repeating": {big.NewRat(1, 3), 0, 0, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			dec, err := bigDecimalExact(cas.value)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if dec.Precision != cas.precision || dec.Scale != cas.scale {
+				t.Errorf("Expected decimal(%d, %d), received decimal(%d, %d)", cas.precision, cas.scale, dec.Precision, dec.Scale)
+			}
+		})
+	}
+}
+
+func TestAsRat(t *testing.T) {
+	dec, err := asetypes.NewDecimalString(20, 4, "-1234.5678")
+	if err != nil {
+		t.Errorf("Error creating decimal: %v", err)
+		return
+	}
+
+	cases := map[string]struct {
+		src       interface{}
+		expect    *big.Rat
+		expectErr bool
+	}{
+		"decimal":     {dec, big.NewRat(-12345678, 10000), false},
+		"string":      {"0.125", big.NewRat(1, 8), false},
+		"int":         {int64(7), big.NewRat(7, 1), false},
+		"nil decimal": {(*asetypes.Decimal)(nil), nil, true},
+		"null":        {nil, nil, true},
+		"float":       {float64(0.5), nil, true},
+		"invalid":     {"abc", nil, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			r := new(big.Rat)
+			err := AsRat(r).Scan(cas.src)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if err == nil && r.Cmp(cas.expect) != 0 {
+				t.Errorf("Expected %s, received %s", cas.expect.RatString(), r.RatString())
+			}
+		})
+	}
+}
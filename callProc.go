@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+// CallProc invokes the stored procedure proc through SendRPC with the
+// named parameters in params, which must be either
+// a map[string]interface{} or a struct or pointer to a struct.
+//
+// Map entries are passed with the key as parameter name. Entries
+// holding a pointer are passed as output parameters and the returned
+// value is written to the pointer:
+//
+//	var total int
+//	rows, _, err := c.CallProc(ctx, "sp_foo", map[string]interface{}{
+//		"@p1":    1,
+//		"@p2":    "x",
+//		"@total": &total,
+//	})
+//
+// Struct fields are passed with the name in the tag `ase` or the
+// field name. Fields tagged with the option output are passed as output
+// parameters and require a pointer to the struct to be passed:
+//
+//	type fooParams struct {
+//		P1    int    `ase:"@p1"`
+//		P2    string `ase:"@p2"`
+//		Total int    `ase:"@total,output"`
+//	}
+//
+// Fields tagged with `ase:"-"` and unexported fields are skipped.
+//
+// As with SendRPC the output parameters are only written once the rows
+// were consumed or closed.
+func (c *Conn) CallProc(ctx context.Context, proc string, params interface{}) (*Rows, *Result, error) {
+	rpcParams, dests, err := procParams(params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("go-ase: error preparing parameters of %s: %w", proc, err)
+	}
+
+	rows, result, err := c.SendRPC(ctx, proc, rpcParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Without result sets the output parameters were already received.
+	if err := result.setOutputDests(dests); err != nil {
+		rows.Close()
+		return nil, nil, fmt.Errorf("go-ase: error writing output parameters of %s: %w", proc, err)
+	}
+
+	return rows, result, nil
+}
+
+// procParams returns the parameters passed in params as map or struct
+// and the destinations of output parameters by parameter name.
+func procParams(params interface{}) ([]Param, map[string]interface{}, error) {
+	if m, ok := params.(map[string]interface{}); ok {
+		return mapProcParams(m)
+	}
+
+	v := reflect.ValueOf(params)
+	addressable := false
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+		addressable = true
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("expected map[string]interface{} or struct, got %T", params)
+	}
+
+	return structProcParams(v, addressable)
+}
+
+// mapProcParams returns the parameters for the entries of m, sorted by
+// name.
+func mapProcParams(m map[string]interface{}) ([]Param, map[string]interface{}, error) {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]Param, 0, len(m))
+	dests := map[string]interface{}{}
+
+	for _, name := range names {
+		param := Param{Name: procParamName(name), Value: m[name]}
+
+		if isOutputDest(param.Value) {
+			v := reflect.ValueOf(param.Value)
+			if v.IsNil() {
+				return nil, nil, fmt.Errorf("output parameter %s is a nil pointer", name)
+			}
+
+			param.Value = v.Elem().Interface()
+			param.Output = true
+			dests[param.Name] = m[name]
+		}
+
+		params = append(params, param)
+	}
+
+	return params, dests, nil
+}
+
+// structProcParams returns the parameters for the fields of v.
+// The fields of output parameters are only written to if v is
+// addressable.
+func structProcParams(v reflect.Value, addressable bool) ([]Param, map[string]interface{}, error) {
+	params := []Param{}
+	dests := map[string]interface{}{}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("ase")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		param := Param{Name: procParamName(name), Value: v.Field(i).Interface()}
+
+		if opts == "output" {
+			if !addressable {
+				return nil, nil, fmt.Errorf("output parameter %s requires a pointer to %s", param.Name, v.Type())
+			}
+			param.Output = true
+			dests[param.Name] = v.Field(i).Addr().Interface()
+		} else if opts != "" {
+			return nil, nil, fmt.Errorf("invalid option %q for field %s", opts, field.Name)
+		}
+
+		params = append(params, param)
+	}
+
+	return params, dests, nil
+}
+
+// procParamName returns name with a leading @.
+func procParamName(name string) string {
+	if strings.HasPrefix(name, "@") {
+		return name
+	}
+	return "@" + name
+}
+
+// isOutputDest reports if value is a pointer to write an output
+// parameter to rather than a value passed as parameter.
+//
+// Pointers to decimals and arbitrary-precision numbers are values.
+func isOutputDest(value interface{}) bool {
+	switch value.(type) {
+	case *asetypes.Decimal, *big.Int, *big.Rat, driver.Valuer:
+		return false
+	}
+
+	return value != nil && reflect.TypeOf(value).Kind() == reflect.Ptr
+}
+
+// assignOutput writes the value of an output parameter to dest.
+func assignOutput(dest interface{}, value driver.Value) error {
+	dv := reflect.ValueOf(dest).Elem()
+
+	if value == nil {
+		dv.Set(reflect.Zero(dv.Type()))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+
+	// Integers are convertible to strings, but would be converted to
+	// the character with the code point.
+	if dv.Kind() == reflect.String && v.Kind() != reflect.String {
+		return fmt.Errorf("cannot assign %v (type %T) to %s", value, value, dv.Type())
+	}
+
+	if !v.Type().ConvertibleTo(dv.Type()) {
+		return fmt.Errorf("cannot assign %v (type %T) to %s", value, value, dv.Type())
+	}
+
+	dv.Set(v.Convert(dv.Type()))
+	return nil
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type testProcParams struct {
+	A      int    `ase:"@a"`
+	B      string `ase:"b"`
+	C      float64
+	Out    int64  `ase:"@out,output"`
+	Skip   string `ase:"-"`
+	hidden string
+}
+
+func TestProcParams(t *testing.T) {
+	var out int
+
+	structParams := &testProcParams{A: 1, B: "x", C: 0.5, Out: 3}
+
+	cases := map[string]struct {
+		params interface{}
+		expect []Param
+		dests  map[string]interface{}
+		err    bool
+	}{
+		"map": {
+			params: map[string]interface{}{"@p2": "x", "p1": 1, "@out": &out},
+			expect: []Param{
+				{Name: "@out", Value: 0, Output: true},
+				{Name: "@p2", Value: "x"},
+				{Name: "@p1", Value: 1},
+			},
+			dests: map[string]interface{}{"@out": &out},
+		},
+		"struct pointer": {
+			params: structParams,
+			expect: []Param{
+				{Name: "@a", Value: 1},
+				{Name: "@b", Value: "x"},
+				{Name: "@C", Value: 0.5},
+				{Name: "@out", Value: int64(3), Output: true},
+			},
+			dests: map[string]interface{}{"@out": &structParams.Out},
+		},
+		"struct with output": {
+			params: testProcParams{},
+			err:    true,
+		},
+		"big numbers": {
+			params: map[string]interface{}{"@i": big.NewInt(1), "@r": big.NewRat(1, 2)},
+			expect: []Param{
+				{Name: "@i", Value: big.NewInt(1)},
+				{Name: "@r", Value: big.NewRat(1, 2)},
+			},
+			dests: map[string]interface{}{},
+		},
+		"nil output": {
+			params: map[string]interface{}{"@out": (*int)(nil)},
+			err:    true,
+		},
+		"slice": {
+			params: []interface{}{1},
+			err:    true,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			params, dests, err := procParams(cas.params)
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error, received params %v", params)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(params, cas.expect) {
+				t.Errorf("Expected params %v, received %v", cas.expect, params)
+			}
+
+			if !reflect.DeepEqual(dests, cas.dests) {
+				t.Errorf("Expected destinations %v, received %v", cas.dests, dests)
+			}
+		})
+	}
+}
+
+func TestAssignOutput(t *testing.T) {
+	var (
+		i int
+		s string
+		b []byte
+	)
+
+	cases := map[string]struct {
+		dest   interface{}
+		value  driver.Value
+		expect interface{}
+		err    bool
+	}{
+		"int":        {&i, int64(42), 42, false},
+		"nil":        {&i, nil, 0, false},
+		"string":     {&s, "out", "out", false},
+		"bytes":      {&b, []byte("out"), []byte("out"), false},
+		"int to str": {&s, int64(42), nil, true},
+		"str to int": {&i, "42", nil, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			err := assignOutput(cas.dest, cas.value)
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if recv := reflect.ValueOf(cas.dest).Elem().Interface(); !reflect.DeepEqual(recv, cas.expect) {
+				t.Errorf("Expected %v, received %v", cas.expect, recv)
+			}
+		})
+	}
+}
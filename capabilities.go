@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"github.com/SAP/go-dblib/tds"
+)
+
+// HasCapability reports if the request capability was negotiated at
+// login.
+//
+// go-dblib requests its capabilities at login and replaces them with
+// the capabilities acknowledged by the server, which may be a subset,
+// e.g. on older releases of ASE. The login only fails if the server
+// rejects all capabilities of a type.
+func (c *Conn) HasCapability(capability tds.RequestCapability) bool {
+	if c.Conn == nil || c.Conn.Caps == nil {
+		return false
+	}
+
+	return c.Conn.Caps.HasRequestCapability(capability)
+}
+
+// Capabilities returns the request capabilities negotiated at login,
+// see HasCapability.
+func (c *Conn) Capabilities() []tds.RequestCapability {
+	var capabilities []tds.RequestCapability
+	for capability := tds.TDS_REQ_LANG; capability <= tds.TDS_REQ_COMMAND_ENCRYPTION; capability++ {
+		if c.HasCapability(capability) {
+			capabilities = append(capabilities, capability)
+		}
+	}
+
+	return capabilities
+}
+
+// wideTables reports if the wide variants of tokens, e.g. TDS_DYNAMIC2,
+// may be sent to the server.
+func (c *Conn) wideTables() bool {
+	return c.HasCapability(tds.TDS_WIDETABLES)
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestConn_Capabilities(t *testing.T) {
+	cases := map[string]struct {
+		capabilities     []tds.RequestCapability
+		expectWide       bool
+		expectCreateProc bool
+	}{
+		"all":            {[]tds.RequestCapability{tds.TDS_REQ_LANG, tds.TDS_PROTO_DYNPROC, tds.TDS_WIDETABLES}, true, true},
+		"no wide tables": {[]tds.RequestCapability{tds.TDS_REQ_LANG, tds.TDS_PROTO_DYNPROC}, false, true},
+		"no dynproc":     {[]tds.RequestCapability{tds.TDS_REQ_LANG, tds.TDS_WIDETABLES}, true, false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			caps, err := tds.NewCapabilityPackage(cas.capabilities, nil, nil)
+			if err != nil {
+				t.Errorf("Error creating capabilities: %v", err)
+				return
+			}
+
+			c := &Conn{Conn: &tds.Conn{Caps: caps}, Info: &Info{}}
+
+			if recv := c.Capabilities(); !reflect.DeepEqual(recv, cas.capabilities) {
+				t.Errorf("Expected capabilities %v, received %v", cas.capabilities, recv)
+			}
+
+			if recv := c.wideTables(); recv != cas.expectWide {
+				t.Errorf("Expected wide tables %t, received %t", cas.expectWide, recv)
+			}
+
+			if recv := c.createProc(); recv != cas.expectCreateProc {
+				t.Errorf("Expected create proc %t, received %t", cas.expectCreateProc, recv)
+			}
+		})
+	}
+
+	if (&Conn{}).HasCapability(tds.TDS_REQ_LANG) {
+		t.Errorf("Expected no capabilities without a TDS connection")
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrImplicitTransaction is returned when the chained mode is restored
+// while a transaction implicitly begun by a statement in chained mode
+// is still open.
+var ErrImplicitTransaction = errors.New("go-ase: transaction begun in chained mode was neither committed nor rolled back")
+
+// resetChained restores the chained mode if the property chained is
+// set.
+//
+// The chained mode cannot be changed within a transaction. A transaction
+// implicitly begun by a statement executed outside of a database/sql
+// transaction is neither committed nor rolled back on behalf of the
+// application, instead ErrImplicitTransaction is returned.
+func (c *Conn) resetChained(ctx context.Context) error {
+	if !c.Info.Chained {
+		return nil
+	}
+
+	if c.InTransaction() {
+		return ErrImplicitTransaction
+	}
+
+	return c.execNoRows(ctx, "if @@tranchained = 0 set chained on")
+}
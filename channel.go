@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import "github.com/SAP/go-dblib/tds"
+
+// TDSChannel returns the TDS channel of the connection for
+// protocol-level extensions, e.g. to send token sequences the driver
+// does not support. It replaces the deprecated field Channel.
+//
+// The channel is not guarded by the driver - sending packages while
+// a command of the driver is in progress or leaving unread packages
+// behind corrupts the communication and renders the connection
+// unusable. Use Lock and Unlock to prevent the driver from using the
+// connection while the channel is used:
+//
+//	if err := c.Lock(); err != nil {
+//		return err
+//	}
+//	defer c.Unlock()
+//
+//	if err := c.TDSChannel().SendPackage(ctx, pkg); err != nil {
+//		return err
+//	}
+//	// read the response until the final DonePackage
+func (c *Conn) TDSChannel() *tds.Channel {
+	return c.channel
+}
+
+// Lock marks the connection as in use, which makes commands of the
+// driver fail with ErrConcurrentUse until Unlock is called.
+//
+// Lock does not wait for the connection to become available. It
+// returns ErrConcurrentUse if a command is being executed and
+// ErrBusyConnection if the rows of a command have not been consumed or
+// closed.
+func (c *Conn) Lock() error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+
+	if err := c.checkBusy(); err != nil {
+		c.release()
+		return err
+	}
+
+	return nil
+}
+
+// Unlock releases the connection marked as in use by Lock.
+func (c *Conn) Unlock() {
+	c.release()
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build go1.18
+// +build go1.18
+
+package ase
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Collect reads all rows of the current result set, passes each to
+// scan and returns the collected values. The rows are closed
+// afterwards.
+//
+// Collect is only available when building with Go 1.18 or newer.
+//
+// scan reads the values of the current row through Values:
+//
+//	names, err := ase.Collect(rows, func(rows *ase.Rows) (string, error) {
+//		name, ok := rows.Values()[0].(string)
+//		if !ok {
+//			return "", errors.New("name is not a string")
+//		}
+//		return name, nil
+//	})
+func Collect[T any](rows *Rows, scan func(*Rows) (T, error)) ([]T, error) {
+	var collected []T
+
+	values := make([]driver.Value, len(rows.Columns()))
+	for {
+		if err := rows.Next(values); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			rows.Close()
+			return nil, fmt.Errorf("go-ase: error reading row: %w", err)
+		}
+
+		value, err := scan(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		collected = append(collected, value)
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("go-ase: error closing rows: %w", err)
+	}
+
+	return collected, nil
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration && go1.18
+// +build integration,go1.18
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestCollect(t *testing.T) {
+	integration.TestForEachDB("TestCollect", t, testCollect)
+}
+
+func testCollect(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int, b varchar(10))", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (1, 'one') insert into %s values (2, 'two')", tableName, tableName)); err != nil {
+		t.Errorf("Error inserting values: %v", err)
+		return
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	type row struct {
+		a int32
+		b string
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		rows, _, err := c.DirectExec(context.Background(), fmt.Sprintf("select a, b from %s order by a", tableName))
+		if err != nil {
+			return fmt.Errorf("error selecting rows: %w", err)
+		}
+
+		collected, err := Collect(rows.(*Rows), func(rows *Rows) (row, error) {
+			values := rows.Values()
+			return row{values[0].(int32), values[1].(string)}, nil
+		})
+		if err != nil {
+			return fmt.Errorf("error collecting rows: %w", err)
+		}
+
+		expect := []row{{1, "one"}, {2, "two"}}
+		if !reflect.DeepEqual(collected, expect) {
+			return fmt.Errorf("expected %v, received %v", expect, collected)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
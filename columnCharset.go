@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// CharsetDecoder decodes strings of a character set to UTF-8.
+//
+// The decoders of golang.org/x/text/encoding implement CharsetDecoder,
+// e.g. charmap.Windows1252.NewDecoder().
+type CharsetDecoder interface {
+	String(s string) (string, error)
+}
+
+// SetColumnCharset sets the decoder for the values of the column index
+// of the current result set, overriding the character set of the
+// connection for a column storing data in a different encoding, e.g.
+// a text column of a legacy application written in cp1252 while the
+// session uses utf8:
+//
+//	rows.SetColumnCharset(2, charmap.Windows1252.NewDecoder())
+//
+// Supported are char, varchar, text and unitext columns. The bytes
+// received from the server are passed to the decoder - for unitext
+// these are the UTF-16 code units, e.g. for columns written with
+// a different byte order. Decoding errors are returned by Next.
+//
+// The override applies to the rows read after the call until the end
+// of the current result set, a row buffered by Peek is returned as
+// read. Passing nil removes the override.
+func (rows *Rows) SetColumnCharset(index int, dec CharsetDecoder) error {
+	if rows.RowFmt == nil || index < 0 || index >= len(rows.RowFmt.Fmts) {
+		return fmt.Errorf("go-ase: invalid column index %d", index)
+	}
+
+	if dec == nil {
+		delete(rows.columnDecoders, index)
+		return nil
+	}
+
+	if !isDecodableColumn(rows.RowFmt.Fmts[index]) {
+		return fmt.Errorf("go-ase: column %d of type %s has no character set",
+			index, databaseTypeName(rows.RowFmt.Fmts[index]))
+	}
+
+	if rows.columnDecoders == nil {
+		rows.columnDecoders = map[int]CharsetDecoder{}
+	}
+	rows.columnDecoders[index] = dec
+	return nil
+}
+
+// isDecodableColumn reports if the values of fieldFmt can be decoded
+// by SetColumnCharset.
+func isDecodableColumn(fieldFmt tds.FieldFmt) bool {
+	switch fieldFmt.DataType() {
+	case asetypes.CHAR, asetypes.VARCHAR, asetypes.LONGCHAR,
+		asetypes.TEXT, asetypes.UNITEXT:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeColumns decodes the values of the columns with decoders set by
+// SetColumnCharset.
+func (rows *Rows) decodeColumns(dst []driver.Value) error {
+	for index, dec := range rows.columnDecoders {
+		s, ok := dst[index].(string)
+		if !ok {
+			continue
+		}
+
+		raw := s
+		if rows.RowFmt.Fmts[index].DataType() == asetypes.UNITEXT {
+			raw = unitextBytes(s)
+		}
+
+		decoded, err := dec.String(raw)
+		if err != nil {
+			return fmt.Errorf("go-ase: error decoding column %d: %w", index, err)
+		}
+		dst[index] = decoded
+	}
+
+	return nil
+}
+
+// unitextBytes returns the bytes of a unitext value as received from
+// the server.
+//
+// go-dblib decodes unitext by mapping every received byte to a rune
+// and trims trailing NUL bytes, which may include the high byte of
+// the last little-endian code unit. The bytes are restored from the
+// runes and padded to a whole code unit.
+func unitextBytes(s string) string {
+	runes := []rune(s)
+	b := make([]byte, len(runes), len(runes)+1)
+	for i, r := range runes {
+		b[i] = byte(r)
+	}
+
+	if len(b)%2 != 0 {
+		b = append(b, 0)
+	}
+
+	return string(b)
+}
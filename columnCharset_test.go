@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// latin1Decoder decodes ISO 8859-1.
+type latin1Decoder struct{}
+
+func (latin1Decoder) String(s string) (string, error) {
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes), nil
+}
+
+// utf16Decoder decodes little-endian UTF-16.
+type utf16Decoder struct{}
+
+func (utf16Decoder) String(s string) (string, error) {
+	if len(s)%2 != 0 {
+		return "", errors.New("odd length")
+	}
+
+	units := make([]uint16, len(s)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16([]byte(s[2*i:]))
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+type failingDecoder struct{}
+
+func (failingDecoder) String(s string) (string, error) {
+	return "", errors.New("invalid byte")
+}
+
+// dblibUnitext returns s as decoded by go-dblib from the unitext bytes
+// sent by the server.
+func dblibUnitext(s string) string {
+	units := utf16.Encode([]rune(s))
+	runes := make([]rune, 0, 2*len(units))
+	for _, unit := range units {
+		runes = append(runes, rune(unit&0xff), rune(unit>>8))
+	}
+	return strings.TrimRight(string(runes), "\x00")
+}
+
+func newCharsetRows(t *testing.T, dataTypes ...asetypes.DataType) *Rows {
+	rowFmt := &tds.RowFmtPackage{}
+	for _, dataType := range dataTypes {
+		fieldFmt, err := tds.LookupFieldFmt(dataType)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rowFmt.Fmts = append(rowFmt.Fmts, fieldFmt)
+	}
+	return &Rows{RowFmt: rowFmt}
+}
+
+func TestRows_SetColumnCharset(t *testing.T) {
+	rows := newCharsetRows(t, asetypes.VARCHAR, asetypes.INT4, asetypes.UNITEXT)
+
+	if err := rows.SetColumnCharset(1, latin1Decoder{}); err == nil {
+		t.Errorf("Expected error for int column")
+	}
+
+	if err := rows.SetColumnCharset(3, latin1Decoder{}); err == nil {
+		t.Errorf("Expected error for invalid index")
+	}
+
+	if err := rows.SetColumnCharset(0, latin1Decoder{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := rows.SetColumnCharset(2, utf16Decoder{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dst := []driver.Value{"k\xe4se", int32(1), dblibUnitext("k€ä")}
+	if err := rows.decodeColumns(dst); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if dst[0] != "käse" {
+		t.Errorf("Expected %q, received %q", "käse", dst[0])
+	}
+
+	if dst[2] != "k€ä" {
+		t.Errorf("Expected %q, received %q", "k€ä", dst[2])
+	}
+
+	// NULL values are not decoded.
+	dst = []driver.Value{nil, int32(2), nil}
+	if err := rows.decodeColumns(dst); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if dst[0] != nil || dst[2] != nil {
+		t.Errorf("Expected NULL values, received %v", dst)
+	}
+
+	if err := rows.SetColumnCharset(0, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dst = []driver.Value{"k\xe4se", int32(3), nil}
+	if err := rows.decodeColumns(dst); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if dst[0] != "k\xe4se" {
+		t.Errorf("Expected value to be returned unchanged, received %q", dst[0])
+	}
+
+	rows.switchResultSet(rows.RowFmt)
+	if len(rows.columnDecoders) != 0 {
+		t.Errorf("Expected decoders to be reset with the result set")
+	}
+}
+
+func TestRows_SetColumnCharset_Error(t *testing.T) {
+	rows := newCharsetRows(t, asetypes.TEXT)
+
+	if err := rows.SetColumnCharset(0, failingDecoder{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := rows.decodeColumns([]driver.Value{"abc"}); err == nil {
+		t.Errorf("Expected decoding error")
+	}
+}
@@ -8,8 +8,13 @@ package ase
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/SAP/go-dblib/asetypes"
 	"github.com/SAP/go-dblib/tds"
@@ -22,18 +27,157 @@ var (
 	_ driver.ExecerContext      = (*Conn)(nil)
 	_ driver.QueryerContext     = (*Conn)(nil)
 	_ driver.Pinger             = (*Conn)(nil)
+	_ driver.SessionResetter    = (*Conn)(nil)
+
+	// ErrBusyConnection is returned when a command is issued on
+	// a connection while the result set of a previous command has not
+	// been consumed or closed.
+	ErrBusyConnection = errors.New("go-ase: connection is busy with an unfinished result set")
+
+	// ErrConcurrentUse is returned when a command is issued on
+	// a connection while another goroutine is sending a command on the
+	// same connection.
+	ErrConcurrentUse = errors.New("go-ase: connection is used concurrently by multiple goroutines")
+
+	// ErrCancelled is returned by a command that was aborted by
+	// Conn.Cancel.
+	ErrCancelled = errors.New("command was cancelled")
+)
+
+// States of Conn.inUse.
+const (
+	connIdle int32 = iota
+	connSending
+	connReceiving
 )
 
 // Conn implements the driver.Conn interface.
 type Conn struct {
-	Conn    *tds.Conn
+	Conn *tds.Conn
+	// Channel is the TDS channel of the connection.
+	//
+	// Deprecated: The channel is not guarded against use while the
+	// driver executes a command, use TDSChannel with Lock instead.
 	Channel *tds.Channel
+	channel *tds.Channel
 	Info    *Info
 
-	// TODO I don't particularly like locking statements like this
-	stmts map[int]*Stmt
-	// TODO: iirc conns aren't used in multiple threads at the same time
+	// stmts and cursors are the dynamic statements and cursors
+	// allocated on the server by name, see OpenStatements and
+	// OpenCursors. Both are guarded by stmtLock.
+	stmts    map[string]*Stmt
+	cursors  map[string]*Cursor
 	stmtLock *sync.RWMutex
+
+	// stats records the execution statistics of the current command.
+	stats     *ExecStats
+	statsLock *sync.Mutex
+
+	// messages are the messages received since the last DonePackage,
+	// see statementError.
+	messages     []*tds.EEDPackage
+	messagesLock *sync.Mutex
+
+	// sessionLanguage and sessionDateFormat are the settings of the
+	// session, see Language and DateFormat.
+	sessionLanguage   string
+	sessionDateFormat string
+	sessionLock       *sync.Mutex
+	// sessionNoCount is set while `set nocount on` is active, see
+	// NoCount.
+	sessionNoCount bool
+	// sessionCharset is the character set of the session, see
+	// Charset. serverCharset and serverSortOrder are the defaults of
+	// the server, see ServerCharset and ServerSortOrder, which are
+	// selected once serverCharsetSelected is set.
+	sessionCharset        string
+	serverCharset         string
+	serverSortOrder       string
+	serverCharsetSelected bool
+
+	// sessionTraceID is the trace ID last sent as clientapplname, see
+	// propagateTraceID.
+	sessionTraceID string
+
+	// inTransaction is set while a transaction is open, see
+	// InTransaction. responseDone is set after a DonePackage ended
+	// a response with a status other than TDS_DONE_FINAL.
+	inTransaction bool
+	responseDone  bool
+	// txAborted is set when the server rolled back the transaction
+	// after choosing the connection as deadlock victim.
+	txAborted bool
+	// txDepth is the number of open transactions started through
+	// the driver, see TransactionState.
+	txDepth int
+
+	// plan receives the messages of the query plan while ExplainQuery
+	// is running.
+	plan     *strings.Builder
+	planLock *sync.Mutex
+
+	// progress receives the messages of the command while ExecAdmin
+	// is running.
+	progress     func(*tds.EEDPackage)
+	progressLock *sync.Mutex
+
+	// packages retains the last packages if the property
+	// debugpackages is set, see LastPackages.
+	packages *packageRing
+
+	// rowLimit is set while a row limit set by ExecWithRowLimit is
+	// active on the session.
+	rowLimit bool
+
+	// activeRows are the rows of the last command. Until they are
+	// finished no other command can be sent.
+	activeRows *Rows
+
+	// inUse is set while a command is being sent or its response is
+	// being received, see acquire.
+	inUse int32
+	// broken is set to 1 once the network connection failed.
+	broken int32
+	// closed is set to 1 once Close was called.
+	closed int32
+
+	// netConn is the network connection of Conn, to which attentions
+	// are written directly.
+	netConn io.Writer
+	// sendLock serializes writing packets through the channel and
+	// writing attentions to netConn.
+	sendLock sync.Mutex
+
+	// cancelLock serializes sending attentions with the end of
+	// commands.
+	cancelLock sync.Mutex
+	// attention is set while an attention sent by Cancel has not been
+	// acknowledged by the server.
+	attention bool
+
+	// events receives the lifecycle events of the connection if the
+	// connection was opened by a Connector with observers.
+	events *eventDispatcher
+
+	// queryLog receives the executed statements if the connection was
+	// opened by a Connector with a query logger.
+	queryLog *queryLog
+
+	// normalizer is applied to string parameters if the connection was
+	// opened by a Connector with a normalizer.
+	normalizer Normalizer
+
+	// schemaCache caches the columns returned by TableColumns if the
+	// connection was opened by a Connector with EnableSchemaCache.
+	schemaCache *schemaCache
+
+	// retry holds the errors on which statements are retried if the
+	// connection was opened by a Connector with retryable errors.
+	retry *retryConfig
+
+	// doneHook is called with every DonePackage while ExecMulti is
+	// running.
+	doneHook func(*tds.DonePackage)
 }
 
 // NewConn returns a connection with the passed configuration.
@@ -43,10 +187,40 @@ func NewConn(ctx context.Context, dsn *Info) (*Conn, error) {
 
 // NewConnWithHooks returns a connection with the passed configuration.
 func NewConnWithHooks(ctx context.Context, info *Info, envChangeHooks []tds.EnvChangeHook, eedHooks []tds.EEDHook) (*Conn, error) {
+	if err := checkCloseMode(info.CloseMode); err != nil {
+		return nil, err
+	}
+
+	if err := checkDateFormat(info.DateFormat); err != nil {
+		return nil, err
+	}
+
+	if err := checkArithOption("arithabort", info.ArithAbort); err != nil {
+		return nil, err
+	}
+
+	if err := checkArithOption("arithignore", info.ArithIgnore); err != nil {
+		return nil, err
+	}
+
 	conn := &Conn{
 		Info:     info,
-		stmts:    map[int]*Stmt{},
+		stmts:    map[string]*Stmt{},
+		cursors:  map[string]*Cursor{},
 		stmtLock: &sync.RWMutex{},
+
+		stats:     &ExecStats{},
+		statsLock: &sync.Mutex{},
+
+		messagesLock: &sync.Mutex{},
+
+		sessionLock: &sync.Mutex{},
+
+		planLock: &sync.Mutex{},
+
+		progressLock: &sync.Mutex{},
+
+		packages: newPackageRing(info.DebugPackages),
 	}
 
 	// Cannot pass the passed context along here as tds.NewConn creates
@@ -59,32 +233,59 @@ func NewConnWithHooks(ctx context.Context, info *Info, envChangeHooks []tds.EnvC
 		return nil, fmt.Errorf("go-ase: error opening connection to TDS server: %w", err)
 	}
 
-	conn.Channel, err = conn.Conn.NewChannel()
+	conn.netConn, err = netConnOf(conn.Conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("go-ase: error accessing network connection: %w", err)
+	}
+
+	conn.channel, err = conn.Conn.NewChannel()
 	if err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("go-ase: error opening logical channel: %w", err)
 	}
+	conn.Channel = conn.channel
+
+	if err := conn.channel.RegisterEEDHooks(conn.statsEEDHook); err != nil {
+		return nil, fmt.Errorf("go-ase: error registering statistics EEDHook: %w", err)
+	}
+
+	if err := conn.channel.RegisterEEDHooks(conn.planEEDHook); err != nil {
+		return nil, fmt.Errorf("go-ase: error registering query plan EEDHook: %w", err)
+	}
+
+	if err := conn.channel.RegisterEEDHooks(conn.messagesEEDHook); err != nil {
+		return nil, fmt.Errorf("go-ase: error registering messages EEDHook: %w", err)
+	}
+
+	if err := conn.channel.RegisterEEDHooks(conn.progressEEDHook); err != nil {
+		return nil, fmt.Errorf("go-ase: error registering progress EEDHook: %w", err)
+	}
+
+	if err := conn.channel.RegisterEnvChangeHooks(conn.sessionEnvChangeHook); err != nil {
+		return nil, fmt.Errorf("go-ase: error registering session EnvChangeHook: %w", err)
+	}
 
 	if drv.envChangeHooks != nil {
-		if err := conn.Channel.RegisterEnvChangeHooks(drv.envChangeHooks...); err != nil {
+		if err := conn.channel.RegisterEnvChangeHooks(drv.envChangeHooks...); err != nil {
 			return nil, fmt.Errorf("go-ase: error registering driver EnvChangeHooks: %w", err)
 		}
 	}
 
 	if envChangeHooks != nil {
-		if err := conn.Channel.RegisterEnvChangeHooks(envChangeHooks...); err != nil {
+		if err := conn.channel.RegisterEnvChangeHooks(envChangeHooks...); err != nil {
 			return nil, fmt.Errorf("go-ase: error registering argument EnvChangeHooks: %w", err)
 		}
 	}
 
 	if drv.eedHooks != nil {
-		if err := conn.Channel.RegisterEEDHooks(drv.eedHooks...); err != nil {
+		if err := conn.channel.RegisterEEDHooks(drv.eedHooks...); err != nil {
 			return nil, fmt.Errorf("go-ase: error registering driver EEDHooks: %w", err)
 		}
 	}
 
 	if eedHooks != nil {
-		if err := conn.Channel.RegisterEEDHooks(eedHooks...); err != nil {
+		if err := conn.channel.RegisterEEDHooks(eedHooks...); err != nil {
 			return nil, fmt.Errorf("go-ase: error registering argument EEDHooks: %w", err)
 		}
 	}
@@ -96,8 +297,11 @@ func NewConnWithHooks(ctx context.Context, info *Info, envChangeHooks []tds.EnvC
 	}
 
 	loginConfig.AppName = info.AppName
+	if info.Language != "" {
+		loginConfig.Language = info.Language
+	}
 
-	if err := conn.Channel.Login(ctx, loginConfig); err != nil {
+	if err := conn.channel.Login(ctx, loginConfig); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("go-ase: error logging in: %w", err)
 	}
@@ -109,11 +313,130 @@ func NewConnWithHooks(ctx context.Context, info *Info, envChangeHooks []tds.EnvC
 		}
 	}
 
+	if info.DateFormat != "" {
+		if err := conn.setDateFormat(ctx, info.DateFormat); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if info.Chained {
+		if err := conn.execNoRows(ctx, "set chained on"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if err := conn.setArithOptions(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
 	return conn, nil
 }
 
+// ResetSession implements the driver.SessionResetter interface.
+//
+// Connections whose network connection failed are rejected. A row
+// limit still set by ExecWithRowLimit is reset, with the property
+// chained the chained mode is restored and the properties arithabort
+// and arithignore are applied again before the connection is reused.
+//
+// With the property chained connections on which a transaction
+// implicitly begun by a statement is still open are rejected as well,
+// closing them rolls the transaction back.
+func (c *Conn) ResetSession(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+
+	if err := c.resetRowLimit(ctx); err != nil {
+		return driver.ErrBadConn
+	}
+
+	if err := c.resetChained(ctx); err != nil {
+		return driver.ErrBadConn
+	}
+
+	if err := c.setArithOptions(ctx); err != nil {
+		return driver.ErrBadConn
+	}
+
+	return nil
+}
+
+// checkBusy returns ErrBusyConnection if the result set of a previous
+// command is still being received.
+//
+// TDS only allows one active command per channel - sending a command
+// before the previous response is consumed would corrupt the
+// communication. Cursors are not affected, as each fetch is a separate
+// command.
+func (c *Conn) checkBusy() error {
+	if c.activeRows != nil && !c.activeRows.finished {
+		return ErrBusyConnection
+	}
+	return nil
+}
+
+// acquire marks the connection as in use by a command and returns
+// ErrConcurrentUse if it already is.
+// driver.ErrBadConn is returned if the network connection failed and
+// ErrConnClosed if the connection was closed.
+//
+// Connections must not be used by multiple goroutines at the same time
+// - interleaved commands would corrupt the TDS communication.
+// The flag must be released with release once the command has been
+// sent and its response has been received.
+//
+// If the acknowledgement of an attention sent by Cancel is still
+// pending it is consumed before the connection is handed out.
+func (c *Conn) acquire() error {
+	if err := c.checkClosed(); err != nil {
+		return err
+	}
+
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+
+	if !atomic.CompareAndSwapInt32(&c.inUse, connIdle, connSending) {
+		return ErrConcurrentUse
+	}
+
+	if err := c.recvPendingAttention(); err != nil {
+		c.release()
+		return err
+	}
+
+	return nil
+}
+
+// startReceiving marks that the command holding the connection has
+// been sent and is waiting for its response, which allows Cancel to
+// abort it.
+func (c *Conn) startReceiving() {
+	atomic.CompareAndSwapInt32(&c.inUse, connSending, connReceiving)
+}
+
+// release releases the flag set by acquire.
+func (c *Conn) release() {
+	c.cancelLock.Lock()
+	defer c.cancelLock.Unlock()
+
+	atomic.StoreInt32(&c.inUse, connIdle)
+}
+
 // Close implements the driver.Conn interface.
+//
+// Commands executed after Close return ErrConnClosed. Calling Close
+// again is a no-op.
 func (c *Conn) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return nil
+	}
+	defer c.events.emit(ConnEvent{Type: ConnEventDisconnected})
+
 	if err := c.Conn.Close(); err != nil {
 		return fmt.Errorf("go-ase: error closing TDS connection: %w", err)
 	}
@@ -129,11 +452,23 @@ func (c *Conn) ExecContext(ctx context.Context, query string, args []driver.Name
 		rows.Close()
 	}
 
-	return result, err
+	if err != nil {
+		return result, err
+	}
+
+	if err := c.setLastInsertId(ctx, query, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // QueryContext implements the driver.QueryerContext.
 func (c *Conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+
 	if c.Info.NoQueryCursor {
 		rows, _, err := c.GenericExec(ctx, query, args)
 		return rows, err
@@ -148,22 +483,50 @@ func (c *Conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 }
 
 // Ping implements the driver.Pinger interface.
-func (c Conn) Ping(ctx context.Context) error {
-	// TODO implement ErrBadConn check
-	rows, _, err := c.language(ctx, "select 'ping'")
-	if err != nil {
-		return fmt.Errorf("go-ase: error pinging database: %w", err)
+//
+// Ping is a Healthcheck discarding the latency.
+func (c *Conn) Ping(ctx context.Context) error {
+	if err := c.checkClosed(); err != nil {
+		return err
 	}
 
-	if err := rows.Close(); err != nil {
-		return fmt.Errorf("go-ase: error closing rows from ping: %w", err)
+	if _, err := c.Healthcheck(ctx); err != nil {
+		return fmt.Errorf("go-ase: error pinging database: %w", err)
 	}
 
 	return nil
 }
 
 // CheckNamedValue implements the driver.NamedValueChecker interface.
+//
+// Values marked by Sensitive stay marked after being checked.
 func (conn *Conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if ok, err := checkSensitive(nv, conn.CheckNamedValue); ok {
+		return err
+	}
+
+	// database/sql only calls driver.Valuer when the driver does not
+	// implement driver.NamedValueChecker.
+	if valuer, ok := nv.Value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		nv.Value = v
+	}
+
+	// Durations are converted to time values and big numbers to
+	// decimals once the parameter format is known.
+	if _, ok := nv.Value.(time.Duration); ok || isBigNumber(nv.Value) {
+		return nil
+	}
+
+	// Real and Float select the parameter format of RPCs.
+	switch nv.Value.(type) {
+	case Real, Float:
+		return nil
+	}
+
 	v, err := asetypes.DefaultValueConverter.ConvertValue(nv.Value)
 	if err != nil {
 		return err
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// Interface satisfaction checks
+var (
+	_ driver.Validator = (*Conn)(nil)
+
+	// ErrConnClosed is matched by errors returned when the connection
+	// to the server failed, e.g. because it was reset by the server or
+	// a proxy. It is returned as-is by commands executed after Close.
+	//
+	// The errors of failed connections additionally implement the methods Temporary and
+	// IsConnectionError, which both return true:
+	//
+	//	var connErr interface{ IsConnectionError() bool }
+	//	if errors.As(err, &connErr) && connErr.IsConnectionError() {
+	//		// retry on a new connection
+	//	}
+	ErrConnClosed = errors.New("go-ase: connection closed")
+)
+
+// connError wraps errors of the network connection.
+type connError struct {
+	err error
+}
+
+func (e *connError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrConnClosed, e.err)
+}
+
+func (e *connError) Unwrap() error {
+	return e.err
+}
+
+// Is reports if target is ErrConnClosed.
+func (e *connError) Is(target error) bool {
+	return target == ErrConnClosed
+}
+
+// Temporary reports that the command may succeed on a new connection.
+func (e *connError) Temporary() bool {
+	return true
+}
+
+// IsConnectionError reports that the error stems from the connection
+// rather than the command.
+func (e *connError) IsConnectionError() bool {
+	return true
+}
+
+// isNetworkError reports if err was caused by the network connection.
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// checkConnError marks the connection as broken and wraps err as
+// connError if err was caused by the network connection.
+func (c *Conn) checkConnError(err error) error {
+	if err == nil || !isNetworkError(err) {
+		return err
+	}
+
+	atomic.StoreInt32(&c.broken, 1)
+
+	var connErr *connError
+	if errors.As(err, &connErr) {
+		return err
+	}
+	return &connError{err: err}
+}
+
+// checkClosed returns ErrConnClosed if Close was called.
+func (c *Conn) checkClosed() error {
+	if atomic.LoadInt32(&c.closed) == 1 {
+		return ErrConnClosed
+	}
+	return nil
+}
+
+// IsValid implements the driver.Validator interface.
+//
+// Connections whose network connection failed are not valid and are
+// discarded by database/sql.
+func (c *Conn) IsValid() bool {
+	return atomic.LoadInt32(&c.broken) == 0
+}
+
+// sendPackage wraps tds.Channel.SendPackage.
+func (c *Conn) sendPackage(ctx context.Context, pkg tds.Package) error {
+	c.sendLock.Lock()
+	defer c.sendLock.Unlock()
+
+	c.packages.add(true, pkg)
+	return c.checkConnError(c.channel.SendPackage(ctx, pkg))
+}
+
+// queuePackage wraps tds.Channel.QueuePackage.
+func (c *Conn) queuePackage(ctx context.Context, pkg tds.Package) error {
+	c.sendLock.Lock()
+	defer c.sendLock.Unlock()
+
+	c.packages.add(true, pkg)
+	return c.checkConnError(c.channel.QueuePackage(ctx, pkg))
+}
+
+// sendRemainingPackets wraps tds.Channel.SendRemainingPackets.
+func (c *Conn) sendRemainingPackets(ctx context.Context) error {
+	c.sendLock.Lock()
+	defer c.sendLock.Unlock()
+
+	return c.checkConnError(c.channel.SendRemainingPackets(ctx))
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestConn_checkConnError(t *testing.T) {
+	resetErr := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	cases := map[string]struct {
+		err       error
+		connError bool
+	}{
+		"nil":             {nil, false},
+		"sql error":       {&Error{Message: "syntax error"}, false},
+		"eof":             {io.EOF, false},
+		"reset":           {fmt.Errorf("error reading packet: %w", resetErr), true},
+		"unexpected eof":  {fmt.Errorf("error reading packet: %w", io.ErrUnexpectedEOF), true},
+		"broken pipe":     {fmt.Errorf("error writing packet: %w", syscall.EPIPE), true},
+		"wrapped already": {&connError{err: io.ErrUnexpectedEOF}, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{}
+
+			err := c.checkConnError(cas.err)
+
+			if c.IsValid() == cas.connError {
+				t.Errorf("Expected IsValid to be %t", !cas.connError)
+			}
+
+			if !cas.connError {
+				if err != cas.err {
+					t.Errorf("Expected error to be returned as-is, received %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrConnClosed) {
+				t.Errorf("Expected error to match ErrConnClosed, received %v", err)
+			}
+
+			if !errors.Is(err, cas.err) && !errors.Is(cas.err, err) {
+				t.Errorf("Expected error to wrap %v, received %v", cas.err, err)
+			}
+
+			var connErr interface {
+				Temporary() bool
+				IsConnectionError() bool
+			}
+			if !errors.As(err, &connErr) || !connErr.Temporary() || !connErr.IsConnectionError() {
+				t.Errorf("Expected error to report a temporary connection error, received %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestConn_acquire(t *testing.T) {
+	c := &Conn{}
+
+	if err := c.acquire(); err != nil {
+		t.Errorf("Received unexpected error acquiring unused connection: %v", err)
+		return
+	}
+
+	if err := c.acquire(); !errors.Is(err, ErrConcurrentUse) {
+		t.Errorf("Expected ErrConcurrentUse acquiring used connection, received %v", err)
+		return
+	}
+
+	c.release()
+
+	if err := c.acquire(); err != nil {
+		t.Errorf("Received unexpected error acquiring released connection: %v", err)
+	}
+}
+
+func TestConn_Cancel_NoOp(t *testing.T) {
+	cases := map[string]struct {
+		inUse      int32
+		activeRows *Rows
+	}{
+		"idle":          {connIdle, nil},
+		"finished rows": {connIdle, &Rows{finished: true}},
+		"sending":       {connSending, nil},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{inUse: cas.inUse, activeRows: cas.activeRows}
+
+			if err := c.Cancel(); err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if c.attention {
+				t.Errorf("Expected no pending attention")
+			}
+
+			if c.inUse != cas.inUse {
+				t.Errorf("Expected connection state %d, received %d", cas.inUse, c.inUse)
+			}
+		})
+	}
+}
+
+func TestConn_Cancel(t *testing.T) {
+	netConn := &bytes.Buffer{}
+	c := &Conn{inUse: connReceiving, netConn: netConn}
+
+	// Cancel is called concurrently while the command writes to the
+	// network connection, run with -race to detect unsynchronized
+	// writes.
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := c.Cancel(); err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			c.sendLock.Lock()
+			defer c.sendLock.Unlock()
+			netConn.Reset()
+		}()
+	}
+	wg.Wait()
+
+	if !c.attention {
+		t.Errorf("Expected pending attention")
+	}
+
+	// Only the first Cancel sends an attention.
+	if err := c.Cancel(); err != nil {
+		t.Errorf("Received unexpected error: %v", err)
+	}
+
+	netConn.Reset()
+	c.attention = false
+	if err := c.Cancel(); err != nil {
+		t.Errorf("Received unexpected error: %v", err)
+	}
+
+	expected := []byte{byte(tds.TDS_BUF_ATTN), byte(tds.TDS_BUFSTAT_EOM), 0, tds.PacketHeaderSize, 0, 0, 0, 0}
+	if !bytes.Equal(netConn.Bytes(), expected) {
+		t.Errorf("Expected header-only attention %v, received %v", expected, netConn.Bytes())
+	}
+}
+
+func TestConn_abortCompute(t *testing.T) {
+	cases := map[string]struct {
+		attention bool
+		sent      int
+	}{
+		"no pending attention": {false, tds.PacketHeaderSize},
+		// An attention already sent by Cancel is not repeated.
+		"pending attention": {true, 0},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			netConn := &bytes.Buffer{}
+			c := &Conn{inUse: connReceiving, netConn: netConn, attention: cas.attention}
+
+			if err := c.abortCompute(); !errors.Is(err, ErrComputeUnsupported) {
+				t.Errorf("Expected ErrComputeUnsupported, received %v", err)
+			}
+
+			if c.IsValid() {
+				t.Errorf("Expected connection to be marked as broken")
+			}
+
+			if !c.attention {
+				t.Errorf("Expected pending attention")
+			}
+
+			if netConn.Len() != cas.sent {
+				t.Errorf("Expected %d bytes to be sent, received %d", cas.sent, netConn.Len())
+			}
+		})
+	}
+}
+
+func TestNetConnOf(t *testing.T) {
+	// The error reports the missing network connection rather than a
+	// missing field.
+	_, err := netConnOf(&tds.Conn{})
+	if err == nil || !strings.Contains(err.Error(), "not set") {
+		t.Errorf("Expected error about unset network connection, received %v", err)
+	}
+}
+
+func TestConn_Lock(t *testing.T) {
+	c := &Conn{}
+
+	if err := c.Lock(); err != nil {
+		t.Errorf("Received unexpected error locking unused connection: %v", err)
+		return
+	}
+
+	if err := c.acquire(); !errors.Is(err, ErrConcurrentUse) {
+		t.Errorf("Expected ErrConcurrentUse acquiring locked connection, received %v", err)
+		return
+	}
+
+	c.Unlock()
+
+	c.activeRows = &Rows{}
+	if err := c.Lock(); !errors.Is(err, ErrBusyConnection) {
+		t.Errorf("Expected ErrBusyConnection locking busy connection, received %v", err)
+		return
+	}
+
+	if err := c.acquire(); err != nil {
+		t.Errorf("Expected connection to be released after failed Lock, received %v", err)
+	}
+}
+
+func TestConn_BusyConnection(t *testing.T) {
+	cases := map[string]func(c *Conn) error{
+		"DirectExec": func(c *Conn) error {
+			_, _, err := c.DirectExec(context.Background(), "select 1")
+			return err
+		},
+		"DirectExec with args": func(c *Conn) error {
+			_, _, err := c.DirectExec(context.Background(), "select ?", 1)
+			return err
+		},
+		"NewStmt": func(c *Conn) error {
+			_, err := c.NewStmt(context.Background(), "", "select 1", true)
+			return err
+		},
+		"Stmt.GenericExec": func(c *Conn) error {
+			stmt := &Stmt{conn: c}
+			_, _, err := stmt.GenericExec(context.Background(), nil)
+			return err
+		},
+		"SendRPC": func(c *Conn) error {
+			_, _, err := c.SendRPC(context.Background(), "sp_who", nil)
+			return err
+		},
+	}
+
+	for title, call := range cases {
+		t.Run(title, func(t *testing.T) {
+			// The rows of the previous command have not been
+			// consumed or closed.
+			c := &Conn{Info: &Info{}, activeRows: &Rows{}}
+
+			if err := call(c); !errors.Is(err, ErrBusyConnection) {
+				t.Errorf("Expected ErrBusyConnection, received %v", err)
+			}
+
+			if err := c.acquire(); err != nil {
+				t.Errorf("Expected connection to be released after ErrBusyConnection, received %v", err)
+			}
+		})
+	}
+}
+
+func TestConn_Closed(t *testing.T) {
+	cases := map[string]func(c *Conn) error{
+		"ExecContext": func(c *Conn) error {
+			_, err := c.ExecContext(context.Background(), "select 1", nil)
+			return err
+		},
+		"QueryContext": func(c *Conn) error {
+			_, err := c.QueryContext(context.Background(), "select 1", nil)
+			return err
+		},
+		"DirectExec": func(c *Conn) error {
+			_, _, err := c.DirectExec(context.Background(), "select 1")
+			return err
+		},
+		"NewStmt": func(c *Conn) error {
+			_, err := c.NewStmt(context.Background(), "", "select 1", true)
+			return err
+		},
+		"NewTransaction": func(c *Conn) error {
+			_, err := c.NewTransaction(context.Background(), DefaultTxOptions(), "")
+			return err
+		},
+		"Ping": func(c *Conn) error {
+			return c.Ping(context.Background())
+		},
+	}
+
+	for title, call := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{closed: 1}
+
+			if err := call(c); !errors.Is(err, ErrConnClosed) {
+				t.Errorf("Expected ErrConnClosed, received %v", err)
+			}
+		})
+	}
+
+	if err := (&Conn{closed: 1}).Close(); err != nil {
+		t.Errorf("Expected closing a closed connection to be a no-op, received %v", err)
+	}
+}
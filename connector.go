@@ -9,6 +9,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/SAP/go-dblib/tds"
 )
@@ -23,6 +24,12 @@ type Connector struct {
 	Info           *Info
 	EnvChangeHooks []tds.EnvChangeHook
 	EEDHooks       []tds.EEDHook
+
+	events      *eventDispatcher
+	queryLog    *queryLog
+	normalizer  Normalizer
+	schemaCache *schemaCache
+	retry       *retryConfig
 }
 
 // NewConnector returns a new connector with the passed configuration.
@@ -61,5 +68,105 @@ func (c Connector) Driver() driver.Driver {
 
 // Connect implements the driver.Connector interface.
 func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
-	return NewConnWithHooks(ctx, c.Info, c.EnvChangeHooks, c.EEDHooks)
+	conn, err := NewConnWithHooks(ctx, c.Info, c.EnvChangeHooks, c.EEDHooks)
+	if err != nil {
+		c.events.emit(ConnEvent{Type: ConnEventLoginFailed, Err: err})
+		return nil, err
+	}
+
+	conn.queryLog = c.queryLog
+	conn.normalizer = c.normalizer
+	conn.schemaCache = c.schemaCache
+	conn.retry = c.retry
+
+	if c.events != nil {
+		conn.events = c.events
+		if err := conn.channel.RegisterEEDHooks(c.events.eedHook); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("go-ase: error registering event EEDHook: %w", err)
+		}
+		if err := conn.channel.RegisterEnvChangeHooks(c.events.envChangeHook); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("go-ase: error registering event EnvChangeHook: %w", err)
+		}
+	}
+
+	c.events.emit(ConnEvent{Type: ConnEventConnected})
+	return conn, nil
+}
+
+// OnEvent registers fn to be called for lifecycle events of
+// connections opened by the connector. Observers must be registered
+// before the connector is used.
+//
+// Events are delivered asynchronously in a separate goroutine, so fn
+// does not block the connection. Events are dropped if fn cannot keep
+// up, see DroppedEvents.
+//
+// Connectors returned by NewConnector can be asserted to *Connector:
+//
+//	connector, _ := ase.NewConnector(info)
+//	connector.(*ase.Connector).OnEvent(func(ev ase.ConnEvent) { ... })
+func (c *Connector) OnEvent(fn func(ev ConnEvent)) {
+	if c.events == nil {
+		c.events = newEventDispatcher()
+	}
+
+	c.events.addObserver(fn)
+}
+
+// SetQueryLogger sets fn to be called with every statement executed on
+// connections opened by the connector and the values bound to its
+// parameters, e.g. for auditing. Sensitive values can be masked with
+// SetQueryRedactor.
+//
+// The logger must be set before the connector is used. Passing nil
+// disables logging.
+func (c *Connector) SetQueryLogger(fn QueryLogger) {
+	if c.queryLog == nil {
+		c.queryLog = &queryLog{}
+	}
+
+	c.queryLog.logger = fn
+}
+
+// SetContextQueryLogger sets fn to be called like the logger set with
+// SetQueryLogger with the context each statement is executed with:
+//
+//	connector.SetContextQueryLogger(func(ctx context.Context, query string, args []driver.NamedValue) {
+//		traceID, _ := ase.TraceIDFromContext(ctx)
+//		log.Printf("[%s] %s %v", traceID, query, args)
+//	})
+//
+// Both loggers can be set. Passing nil disables the logger.
+func (c *Connector) SetContextQueryLogger(fn ContextQueryLogger) {
+	if c.queryLog == nil {
+		c.queryLog = &queryLog{}
+	}
+
+	c.queryLog.ctxLogger = fn
+}
+
+// SetQueryRedactor sets fn to be called for each parameter before it
+// is passed to the loggers set with SetQueryLogger and
+// SetContextQueryLogger. The value returned
+// by fn is logged instead of the bound value.
+//
+//	connector.SetQueryRedactor(ase.RedactParams("@password"))
+func (c *Connector) SetQueryRedactor(fn QueryRedactor) {
+	if c.queryLog == nil {
+		c.queryLog = &queryLog{}
+	}
+
+	c.queryLog.redactor = fn
+}
+
+// DroppedEvents returns the number of events that were dropped because
+// the observers registered with OnEvent could not keep up.
+func (c *Connector) DroppedEvents() uint64 {
+	if c.events == nil {
+		return 0
+	}
+
+	return atomic.LoadUint64(&c.events.dropped)
 }
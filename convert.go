@@ -0,0 +1,585 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// convertValue converts a value to the Go type expected by the
+// FieldData of the passed format.
+func convertValue(fieldFmt tds.FieldFmt, value interface{}) (driver.Value, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	value = unwrapFloat(value)
+
+	if s, ok := value.(string); ok {
+		converted, err := convertString(fieldFmt, s)
+		if err != nil {
+			return nil, err
+		}
+		value = converted
+	}
+
+	switch fieldFmt.DataType() {
+	case asetypes.FLTN:
+		return convertFloat(fieldFmt.MaxLength(), value)
+	case asetypes.TIME, asetypes.TIMEN, asetypes.BIGTIMEN:
+		if d, ok := value.(time.Duration); ok {
+			return timeOfDay(d)
+		}
+	case asetypes.DECN, asetypes.NUMN, asetypes.MONEY, asetypes.MONEYN, asetypes.SHORTMONEY:
+		if isBigNumber(value) {
+			precision, scale := decimalFormat(fieldFmt)
+			return bigDecimal(value, precision, scale)
+		}
+	}
+
+	if intFmt, ok := lookupIntFormat(fieldFmt); ok {
+		return convertInt(intFmt, value)
+	}
+
+	return fieldFmt.DataType().ConvertValue(value)
+}
+
+// nullValue returns the format and value transmitting NULL for
+// a parameter of the passed format.
+//
+// Variable-length formats transmit NULL as a value of length zero.
+// Fixed-length formats cannot transmit NULL and are replaced by their
+// nullable format, e.g. datetime by datetimen. go-dblib cannot encode
+// values of length zero for decimals and the date and time formats
+// other than datetimen, these are sent as NULL of the related money
+// respectively datetime format - the server converts NULL to the type
+// of the parameter.
+func nullValue(fieldFmt tds.FieldFmt) (tds.FieldFmt, interface{}, error) {
+	var dataType asetypes.DataType
+	length := int64(fieldFmt.DataType().ByteSize())
+
+	switch fieldFmt.DataType() {
+	case asetypes.INT1, asetypes.INT2, asetypes.INT4, asetypes.INT8:
+		dataType = asetypes.INTN
+	case asetypes.UINT2, asetypes.UINT4, asetypes.UINT8:
+		dataType = asetypes.UINTN
+	case asetypes.FLT4, asetypes.FLT8:
+		dataType = asetypes.FLTN
+	case asetypes.MONEY, asetypes.SHORTMONEY:
+		dataType = asetypes.MONEYN
+	case asetypes.DECN, asetypes.NUMN:
+		dataType, length = asetypes.MONEYN, 8
+	case asetypes.DATETIME, asetypes.SHORTDATE:
+		dataType = asetypes.DATETIMEN
+	case asetypes.DATE, asetypes.DATEN, asetypes.TIME, asetypes.TIMEN,
+		asetypes.BIGDATETIMEN, asetypes.BIGTIMEN:
+		dataType, length = asetypes.DATETIMEN, 8
+	case asetypes.UNITEXT:
+		return fieldFmt, "", nil
+	default:
+		if fieldFmt.IsFixedLength() {
+			return nil, nil, fmt.Errorf("cannot transmit NULL as non-nullable %s", fieldFmt.DataType())
+		}
+		return fieldFmt, []byte{}, nil
+	}
+
+	nullFmt, err := newFieldFmt(dataType, length)
+	if err != nil {
+		return nil, nil, err
+	}
+	nullFmt.SetName(fieldFmt.Name())
+	nullFmt.SetStatus(fieldFmt.Status())
+
+	return nullFmt, []byte{}, nil
+}
+
+// timeLayouts are the layouts strings are parsed with for date and
+// time formats.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999",
+	time.RFC3339Nano,
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+// convertString parses a string into the Go type expected for
+// non-character formats.
+// Strings for character formats are returned as-is.
+func convertString(fieldFmt tds.FieldFmt, s string) (interface{}, error) {
+	switch fieldFmt.DataType() {
+	case asetypes.DECN, asetypes.NUMN, asetypes.MONEY, asetypes.MONEYN, asetypes.SHORTMONEY:
+		return convertDecimalString(fieldFmt, s)
+	case asetypes.DATE, asetypes.DATEN, asetypes.TIME, asetypes.TIMEN,
+		asetypes.SHORTDATE, asetypes.DATETIME, asetypes.DATETIMEN,
+		asetypes.BIGDATETIMEN, asetypes.BIGTIMEN:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot parse %q as %s", s, fieldFmt.DataType())
+	case asetypes.BIT:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %s: %w", s, fieldFmt.DataType(), err)
+		}
+		return b, nil
+	case asetypes.FLT4, asetypes.FLT8, asetypes.FLTN:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %s: %w", s, fieldFmt.DataType(), err)
+		}
+		return f, nil
+	}
+
+	if intFmt, ok := lookupIntFormat(fieldFmt); ok {
+		if strings.HasPrefix(s, "-") {
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse %q as %s: %w", s, intFmt.name, err)
+			}
+			return i, nil
+		}
+
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %s: %w", s, intFmt.name, err)
+		}
+		return u, nil
+	}
+
+	return s, nil
+}
+
+// convertDecimalString converts a string to an *asetypes.Decimal with
+// the precision and scale of the passed format.
+func convertDecimalString(fieldFmt tds.FieldFmt, s string) (driver.Value, error) {
+	precision, scale := decimalFormat(fieldFmt)
+
+	dec, err := asetypes.NewDecimalString(precision, scale, s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %q to decimal(%d, %d): %w", s, precision, scale, err)
+	}
+
+	return dec, nil
+}
+
+// decimalFormat returns the precision and scale of a numeric, decimal
+// or money format.
+func decimalFormat(fieldFmt tds.FieldFmt) (int, int) {
+	precision, scale := asetypes.ASEDecimalDefaultPrecision, asetypes.ASEDecimalDefaultScale
+
+	switch fieldFmt.DataType() {
+	case asetypes.MONEY:
+		precision, scale = asetypes.ASEMoneyPrecision, asetypes.ASEMoneyScale
+	case asetypes.SHORTMONEY:
+		precision, scale = asetypes.ASEShortMoneyPrecision, asetypes.ASEShortMoneyScale
+	case asetypes.MONEYN:
+		precision, scale = asetypes.ASEMoneyPrecision, asetypes.ASEMoneyScale
+		if fieldFmt.MaxLength() == 4 {
+			precision, scale = asetypes.ASEShortMoneyPrecision, asetypes.ASEShortMoneyScale
+		}
+	default:
+		if typed, ok := fieldFmt.(interface {
+			Precision() uint8
+			Scale() uint8
+		}); ok {
+			precision, scale = int(typed.Precision()), int(typed.Scale())
+		}
+	}
+
+	return precision, scale
+}
+
+// resultValue returns the value of a field as it is passed to the
+// consumer of rows, with the conversions configured in info applied.
+func resultValue(info *Info, field tds.FieldData) driver.Value {
+	value := field.Value()
+
+	switch field.Format().DataType() {
+	case asetypes.TEXT, asetypes.IMAGE, asetypes.UNITEXT, asetypes.XML:
+		// A NULL LOB must be reported as untyped nil - a nil byte
+		// slice would be scanned as a valid, empty value.
+		// Zero-length LOBs are returned as non-nil empty byte slices.
+		if b, ok := value.([]byte); ok && b == nil {
+			return nil
+		}
+	case asetypes.INTN, asetypes.UINTN, asetypes.FLTN:
+		// go-dblib decodes a NULL of the nullable numeric types as an
+		// untyped zero, which would be indistinguishable from a valid
+		// zero - e.g. for datediff or other computed columns with
+		// NULL operands.
+		// Valid values are always of a sized type.
+		if _, ok := value.(int); ok {
+			return nil
+		}
+	}
+
+	if info.TrimChar && isCharColumn(field.Format()) {
+		if s, ok := value.(string); ok {
+			return strings.TrimRight(s, " ")
+		}
+	}
+
+	if info.ExactDateTime {
+		switch field.Format().DataType() {
+		case asetypes.DATETIME, asetypes.DATETIMEN:
+			// Values of nullable datetime columns are transmitted
+			// as datetimen.
+			if t, ok := value.(time.Time); ok {
+				return exactDateTime(t)
+			}
+		}
+	}
+
+	if info.AllNumericFloat && isNumericColumn(field.Format()) {
+		if f, ok := float64Value(value); ok {
+			return f
+		}
+	}
+
+	if info.NumericAsString {
+		if dec, ok := value.(*asetypes.Decimal); ok && dec != nil {
+			return dec.String()
+		}
+	}
+
+	if info.Uint64AsString {
+		if u, ok := value.(uint64); ok {
+			return strconv.FormatUint(u, 10)
+		}
+	}
+
+	return value
+}
+
+// isNumericColumn reports if fieldFmt is the format of an integer,
+// floating point, numeric, decimal or money column.
+func isNumericColumn(fieldFmt tds.FieldFmt) bool {
+	switch fieldFmt.DataType() {
+	case asetypes.INT1, asetypes.INT2, asetypes.INT4, asetypes.INT8, asetypes.INTN,
+		asetypes.UINT2, asetypes.UINT4, asetypes.UINT8, asetypes.UINTN,
+		asetypes.FLT4, asetypes.FLT8, asetypes.FLTN,
+		asetypes.DECN, asetypes.NUMN,
+		asetypes.MONEY, asetypes.MONEYN, asetypes.SHORTMONEY:
+		return true
+	default:
+		return false
+	}
+}
+
+// float64Value returns a numeric value as float64.
+//
+// Integers beyond 2^53 and numeric and decimal values with more than
+// 15 to 17 significant digits cannot be represented exactly and are
+// rounded to the nearest float64.
+func float64Value(value driver.Value) (float64, bool) {
+	switch typed := value.(type) {
+	case uint8:
+		return float64(typed), true
+	case int16:
+		return float64(typed), true
+	case int32:
+		return float64(typed), true
+	case int64:
+		return float64(typed), true
+	case uint16:
+		return float64(typed), true
+	case uint32:
+		return float64(typed), true
+	case uint64:
+		return float64(typed), true
+	case float32:
+		// Converting through the shortest representation keeps e.g.
+		// 0.1 as 0.1 instead of 0.10000000149011612.
+		f, err := strconv.ParseFloat(strconv.FormatFloat(float64(typed), 'g', -1, 32), 64)
+		if err != nil {
+			return float64(typed), true
+		}
+		return f, true
+	case float64:
+		return typed, true
+	case *asetypes.Decimal:
+		if typed == nil {
+			return 0, false
+		}
+		// Parsing the decimal representation rounds correctly,
+		// unlike dividing the unscaled integer by a power of ten.
+		f, err := strconv.ParseFloat(typed.String(), 64)
+		if err != nil {
+			return 0, false
+		}
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+// dateTimeTicksPerSecond is the resolution of datetime values.
+const dateTimeTicksPerSecond = 300
+
+// exactDateTime returns the exact value of a datetime value decoded
+// with millisecond precision.
+//
+// ASE stores the fraction of datetime values in ticks of 1/300
+// second. When decoded the fraction is truncated to milliseconds,
+// e.g. one tick is decoded as 3ms instead of 3.333...ms. As the
+// truncated milliseconds are always within one tick the number of
+// ticks can be recovered and converted to nanoseconds, rounded to the
+// nearest nanosecond.
+func exactDateTime(t time.Time) time.Time {
+	ms := int64(t.Nanosecond() / int(time.Millisecond))
+
+	// go-dblib decodes the ticks with
+	// asetime.FractionalSecondToMillisecond as
+	// ASEDuration(float64(ticks)*1000/300), which truncates the
+	// fraction. Hence ms = floor(ticks * 1000 / 300) and
+	// ticks = ceil(ms * 300 / 1000). If go-dblib changes to rounding
+	// TestExactDateTime_Ticks fails.
+	ticks := (ms*dateTimeTicksPerSecond + 999) / 1000
+
+	ns := (ticks*int64(time.Second) + dateTimeTicksPerSecond/2) / dateTimeTicksPerSecond
+
+	return t.Truncate(time.Second).Add(time.Duration(ns))
+}
+
+// Usertypes ASE reports for blank-padded columns.
+const (
+	userTypeChar  = 1
+	userTypeNChar = 24
+)
+
+// isCharColumn reports whether the format describes a blank-padded
+// char or nchar column. Nullable char columns are transmitted as
+// varchar, hence the usertype is checked as well.
+func isCharColumn(fieldFmt tds.FieldFmt) bool {
+	if fieldFmt.DataType() == asetypes.CHAR {
+		return true
+	}
+
+	switch fieldFmt.UserType() {
+	case userTypeChar, userTypeNChar:
+		return true
+	}
+
+	return false
+}
+
+// intFormat describes the integer type expected by the server.
+type intFormat struct {
+	name     string
+	unsigned bool
+	size     int
+}
+
+var (
+	intFmtTinyInt          = intFormat{"tinyint", true, 1}
+	intFmtSmallInt         = intFormat{"smallint", false, 2}
+	intFmtInt              = intFormat{"int", false, 4}
+	intFmtBigInt           = intFormat{"bigint", false, 8}
+	intFmtUnsignedSmallInt = intFormat{"unsigned smallint", true, 2}
+	intFmtUnsignedInt      = intFormat{"unsigned int", true, 4}
+	intFmtUnsignedBigInt   = intFormat{"unsigned bigint", true, 8}
+)
+
+// lookupIntFormat returns the intFormat for integer formats.
+//
+// The nullable types INTN and UINTN are resolved by their length.
+// Note that ASE's tinyint is unsigned, hence an INTN of length 1 is
+// unsigned as well.
+func lookupIntFormat(fieldFmt tds.FieldFmt) (intFormat, bool) {
+	switch fieldFmt.DataType() {
+	case asetypes.INT1:
+		return intFmtTinyInt, true
+	case asetypes.INT2:
+		return intFmtSmallInt, true
+	case asetypes.INT4:
+		return intFmtInt, true
+	case asetypes.INT8:
+		return intFmtBigInt, true
+	case asetypes.UINT2:
+		return intFmtUnsignedSmallInt, true
+	case asetypes.UINT4:
+		return intFmtUnsignedInt, true
+	case asetypes.UINT8:
+		return intFmtUnsignedBigInt, true
+	case asetypes.INTN:
+		switch fieldFmt.MaxLength() {
+		case 1:
+			return intFmtTinyInt, true
+		case 2:
+			return intFmtSmallInt, true
+		case 4:
+			return intFmtInt, true
+		case 8:
+			return intFmtBigInt, true
+		}
+	case asetypes.UINTN:
+		switch fieldFmt.MaxLength() {
+		case 1:
+			return intFmtTinyInt, true
+		case 2:
+			return intFmtUnsignedSmallInt, true
+		case 4:
+			return intFmtUnsignedInt, true
+		case 8:
+			return intFmtUnsignedBigInt, true
+		}
+	}
+
+	return intFormat{}, false
+}
+
+// convertInt converts a value to the Go integer type matching the
+// size and signedness of intFmt.
+//
+// Values outside of the range of the ASE type are rejected instead of
+// being wrapped around.
+func convertInt(intFmt intFormat, value interface{}) (driver.Value, error) {
+	var (
+		i        int64
+		u        uint64
+		negative bool
+	)
+
+	sv := reflect.ValueOf(value)
+	switch sv.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		i = sv.Int()
+		negative = i < 0
+		u = uint64(i)
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		u = sv.Uint()
+		i = int64(u)
+	default:
+		return nil, fmt.Errorf("cannot convert %v (type %T) for %s", value, value, intFmt.name)
+	}
+
+	bits := uint(intFmt.size * 8)
+
+	if intFmt.unsigned {
+		if negative || (bits < 64 && u >= 1<<bits) {
+			return nil, fmt.Errorf("value %v is out of range for %s", value, intFmt.name)
+		}
+
+		switch intFmt.size {
+		case 1:
+			return uint8(u), nil
+		case 2:
+			return uint16(u), nil
+		case 4:
+			return uint32(u), nil
+		default:
+			return u, nil
+		}
+	}
+
+	if (!negative && u > uint64(math.MaxInt64)>>(64-bits)) ||
+		(negative && i < math.MinInt64>>(64-bits)) {
+		return nil, fmt.Errorf("value %v is out of range for %s", value, intFmt.name)
+	}
+
+	switch intFmt.size {
+	case 2:
+		return int16(i), nil
+	case 4:
+		return int32(i), nil
+	default:
+		return i, nil
+	}
+}
+
+// convertFloat converts a value for a nullable float parameter.
+//
+// FLTN is used for both real and float - the length of the format
+// determines which one is expected by the server.
+// The value is converted to float32 for real and to float64 for float,
+// as the value is written as-is.
+func convertFloat(length int64, value interface{}) (driver.Value, error) {
+	var f float64
+
+	sv := reflect.ValueOf(value)
+	switch sv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f = sv.Float()
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		f = float64(sv.Int())
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		f = float64(sv.Uint())
+	default:
+		return nil, fmt.Errorf("cannot convert %v (type %T) for %s", value, value, asetypes.FLTN)
+	}
+
+	switch length {
+	case 4:
+		if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+			return nil, fmt.Errorf("value %v is out of range for real", value)
+		}
+		return float32(f), nil
+	case 8:
+		return f, nil
+	default:
+		return nil, fmt.Errorf("invalid length %d for %s", length, asetypes.FLTN)
+	}
+}
+
+// scanType returns the Go type values of the passed format are
+// returned as, with the conversions configured in info applied.
+func scanType(info *Info, fieldFmt tds.FieldFmt) reflect.Type {
+	if info.AllNumericFloat && isNumericColumn(fieldFmt) {
+		return reflect.TypeOf(float64(0))
+	}
+
+	if info.NumericAsString {
+		switch fieldFmt.DataType() {
+		case asetypes.DECN, asetypes.NUMN, asetypes.MONEY, asetypes.MONEYN, asetypes.SHORTMONEY:
+			return reflect.TypeOf("")
+		}
+	}
+
+	if info.Uint64AsString {
+		if intFmt, ok := lookupIntFormat(fieldFmt); ok && intFmt == intFmtUnsignedBigInt {
+			return reflect.TypeOf("")
+		}
+	}
+
+	// The nullable types are transmitted with the length of the
+	// respective fixed length type.
+	switch fieldFmt.DataType() {
+	case asetypes.FLTN:
+		if fieldFmt.MaxLength() == 4 {
+			return asetypes.FLT4.GoReflectType()
+		}
+	case asetypes.INTN:
+		switch fieldFmt.MaxLength() {
+		case 1:
+			return asetypes.INT1.GoReflectType()
+		case 2:
+			return asetypes.INT2.GoReflectType()
+		case 4:
+			return asetypes.INT4.GoReflectType()
+		}
+	case asetypes.UINTN:
+		switch fieldFmt.MaxLength() {
+		case 1:
+			return asetypes.INT1.GoReflectType()
+		case 2:
+			return asetypes.UINT2.GoReflectType()
+		case 4:
+			return asetypes.UINT4.GoReflectType()
+		}
+	}
+
+	return fieldFmt.DataType().GoReflectType()
+}
@@ -0,0 +1,644 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/asetime"
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestConvertFloat(t *testing.T) {
+	cases := map[string]struct {
+		length int64
+		value  interface{}
+		expect driver.Value
+		err    bool
+	}{
+		"float32 to real": {
+			length: 4,
+			value:  float32(0.1),
+			expect: float32(0.1),
+		},
+		"float64 to float": {
+			length: 8,
+			value:  0.1,
+			expect: 0.1,
+		},
+		// 2^24+1 is the first integer that cannot be represented in
+		// a float32.
+		"float64 to real loses precision": {
+			length: 4,
+			value:  float64(1<<24 + 1),
+			expect: float32(1 << 24),
+		},
+		"float64 to float keeps precision": {
+			length: 8,
+			value:  float64(1<<24 + 1),
+			expect: float64(1<<24 + 1),
+		},
+		"float32 to float is widened": {
+			length: 8,
+			value:  float32(0.1),
+			expect: float64(float32(0.1)),
+		},
+		"int to real": {
+			length: 4,
+			value:  int64(5),
+			expect: float32(5),
+		},
+		"out of range for real": {
+			length: 4,
+			value:  math.MaxFloat64,
+			err:    true,
+		},
+		"string": {
+			length: 8,
+			value:  "0.1",
+			err:    true,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := convertFloat(cas.length, cas.value)
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error, received value %v (%T)", recv, recv)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, recv, recv)
+			}
+		})
+	}
+}
+
+func TestConvertValue_FloatOverride(t *testing.T) {
+	realFmt, err := newFieldFmt(asetypes.FLTN, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	floatFmt, err := newFieldFmt(asetypes.FLTN, 8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cases := map[string]struct {
+		fieldFmt tds.FieldFmt
+		value    interface{}
+		expect   driver.Value
+	}{
+		// 2^24+1 is the first integer that cannot be represented in
+		// a float32.
+		"real for float parameter is rounded": {
+			fieldFmt: floatFmt,
+			value:    Real(1<<24 + 1),
+			expect:   float64(1 << 24),
+		},
+		"float for float parameter": {
+			fieldFmt: floatFmt,
+			value:    Float(1<<24 + 1),
+			expect:   float64(1<<24 + 1),
+		},
+		"float for real parameter": {
+			fieldFmt: realFmt,
+			value:    Float(0.5),
+			expect:   float32(0.5),
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := convertValue(cas.fieldFmt, cas.value)
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, recv, recv)
+			}
+		})
+	}
+}
+
+func TestConvertInt(t *testing.T) {
+	cases := map[string]struct {
+		intFmt intFormat
+		value  interface{}
+		expect driver.Value
+		err    bool
+	}{
+		"tinyint max":                {intFmtTinyInt, 255, uint8(255), false},
+		"tinyint overflow":           {intFmtTinyInt, 256, nil, true},
+		"tinyint negative":           {intFmtTinyInt, -1, nil, true},
+		"tinyint from uint8":         {intFmtTinyInt, uint8(200), uint8(200), false},
+		"smallint min":               {intFmtSmallInt, math.MinInt16, int16(math.MinInt16), false},
+		"smallint underflow":         {intFmtSmallInt, math.MinInt16 - 1, nil, true},
+		"smallint overflow":          {intFmtSmallInt, math.MaxInt16 + 1, nil, true},
+		"int max":                    {intFmtInt, int64(math.MaxInt32), int32(math.MaxInt32), false},
+		"int overflow":               {intFmtInt, int64(math.MaxInt32) + 1, nil, true},
+		"bigint from uint64":         {intFmtBigInt, uint64(math.MaxInt64), int64(math.MaxInt64), false},
+		"bigint overflow":            {intFmtBigInt, uint64(math.MaxInt64) + 1, nil, true},
+		"unsigned smallint max":      {intFmtUnsignedSmallInt, 65535, uint16(65535), false},
+		"unsigned smallint overflow": {intFmtUnsignedSmallInt, 65536, nil, true},
+		"unsigned smallint negative": {intFmtUnsignedSmallInt, -1, nil, true},
+		"unsigned int max":           {intFmtUnsignedInt, uint64(math.MaxUint32), uint32(math.MaxUint32), false},
+		"unsigned int overflow":      {intFmtUnsignedInt, uint64(math.MaxUint32) + 1, nil, true},
+		"unsigned bigint max":        {intFmtUnsignedBigInt, uint64(math.MaxUint64), uint64(math.MaxUint64), false},
+		"unsigned bigint negative":   {intFmtUnsignedBigInt, int64(-1), nil, true},
+		"unsupported type":           {intFmtInt, "1", nil, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := convertInt(cas.intFmt, cas.value)
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error, received value %v (%T)", recv, recv)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, recv, recv)
+			}
+		})
+	}
+}
+
+func TestConvertDecimalString(t *testing.T) {
+	fieldFmt, err := tds.LookupFieldFmt(asetypes.MONEY)
+	if err != nil {
+		t.Errorf("Error looking up field format: %v", err)
+		return
+	}
+
+	recv, err := convertDecimalString(fieldFmt, "-922337203685477.5808")
+	if err != nil {
+		t.Errorf("Received unexpected error: %v", err)
+		return
+	}
+
+	dec, ok := recv.(*asetypes.Decimal)
+	if !ok {
+		t.Errorf("Expected *asetypes.Decimal, received %T", recv)
+		return
+	}
+
+	if dec.String() != "-922337203685477.5808" {
+		t.Errorf("Expected %q, received %q", "-922337203685477.5808", dec.String())
+	}
+
+	if _, err := convertDecimalString(fieldFmt, "not a number"); err == nil {
+		t.Errorf("Expected error converting invalid string")
+	}
+}
+
+func TestConvertString(t *testing.T) {
+	cases := map[string]struct {
+		dataType asetypes.DataType
+		value    string
+		expect   interface{}
+		err      bool
+	}{
+		"date":         {asetypes.DATEN, "2021-02-01", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), false},
+		"datetime":     {asetypes.DATETIME, "2021-02-01 13:14:15.5", time.Date(2021, 2, 1, 13, 14, 15, 5e8, time.UTC), false},
+		"invalid date": {asetypes.DATEN, "yesterday", nil, true},
+		"bit":          {asetypes.BIT, "true", true, false},
+		"float":        {asetypes.FLT8, "0.5", 0.5, false},
+		"int":          {asetypes.INT4, "-5", int64(-5), false},
+		"uint":         {asetypes.UINT8, "18446744073709551615", uint64(math.MaxUint64), false},
+		"uint 2^63":    {asetypes.UINT8, "9223372036854775808", uint64(1 << 63), false},
+		"uint 2^64":    {asetypes.UINT8, "18446744073709551616", nil, true},
+		"invalid int":  {asetypes.INT4, "five", nil, true},
+		"varchar":      {asetypes.VARCHAR, "five", "five", false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := tds.LookupFieldFmt(cas.dataType)
+			if err != nil {
+				t.Errorf("Error looking up field format: %v", err)
+				return
+			}
+
+			recv, err := convertString(fieldFmt, cas.value)
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error, received value %v (%T)", recv, recv)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, recv, recv)
+			}
+		})
+	}
+}
+
+func TestNullValue(t *testing.T) {
+	cases := map[string]struct {
+		dataType  asetypes.DataType
+		expectFmt asetypes.DataType
+		expect    interface{}
+		err       bool
+	}{
+		"longbinary":   {asetypes.LONGBINARY, asetypes.LONGBINARY, []byte{}, false},
+		"intn":         {asetypes.INTN, asetypes.INTN, []byte{}, false},
+		"unitext":      {asetypes.UNITEXT, asetypes.UNITEXT, "", false},
+		"int4":         {asetypes.INT4, asetypes.INTN, []byte{}, false},
+		"flt8":         {asetypes.FLT8, asetypes.FLTN, []byte{}, false},
+		"money":        {asetypes.MONEY, asetypes.MONEYN, []byte{}, false},
+		"decn":         {asetypes.DECN, asetypes.MONEYN, []byte{}, false},
+		"datetime":     {asetypes.DATETIME, asetypes.DATETIMEN, []byte{}, false},
+		"datetimen":    {asetypes.DATETIMEN, asetypes.DATETIMEN, []byte{}, false},
+		"daten":        {asetypes.DATEN, asetypes.DATETIMEN, []byte{}, false},
+		"time":         {asetypes.TIME, asetypes.DATETIMEN, []byte{}, false},
+		"bigdatetimen": {asetypes.BIGDATETIMEN, asetypes.DATETIMEN, []byte{}, false},
+		"bit":          {asetypes.BIT, 0, nil, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := tds.LookupFieldFmt(cas.dataType)
+			if err != nil {
+				t.Errorf("Error looking up field format: %v", err)
+				return
+			}
+			fieldFmt.SetName("@p1")
+
+			nullFmt, recv, err := nullValue(fieldFmt)
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error, received value %v (%T)", recv, recv)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if nullFmt.DataType() != cas.expectFmt {
+				t.Errorf("Expected format %s, received %s", cas.expectFmt, nullFmt.DataType())
+			}
+
+			if nullFmt.Name() != "@p1" {
+				t.Errorf("Expected name of the parameter to be kept, received %q", nullFmt.Name())
+			}
+
+			if !reflect.DeepEqual(recv, cas.expect) {
+				t.Errorf("Expected %#v, received %#v", cas.expect, recv)
+			}
+
+			// The value must be encodable as NULL, i.e. with
+			// a length of zero.
+			bs, err := nullFmt.DataType().Bytes(binary.LittleEndian, recv)
+			if err != nil {
+				t.Errorf("Error encoding NULL: %v", err)
+				return
+			}
+
+			if len(bs) != 0 {
+				t.Errorf("Expected NULL to be encoded with length zero, received %#v", bs)
+			}
+		})
+	}
+}
+
+func TestResultValue_TrimChar(t *testing.T) {
+	cases := map[string]struct {
+		dataType asetypes.DataType
+		userType int32
+		trimChar bool
+		expect   string
+	}{
+		"char preserved":    {asetypes.CHAR, userTypeChar, false, "ab  "},
+		"char trimmed":      {asetypes.CHAR, userTypeChar, true, "ab"},
+		"null char trimmed": {asetypes.VARCHAR, userTypeChar, true, "ab"},
+		"nchar trimmed":     {asetypes.VARCHAR, userTypeNChar, true, "ab"},
+		"varchar preserved": {asetypes.VARCHAR, 2, true, "ab  "},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := tds.LookupFieldFmt(cas.dataType)
+			if err != nil {
+				t.Errorf("Error looking up field format: %v", err)
+				return
+			}
+			fieldFmt.SetUserType(cas.userType)
+
+			field, err := tds.LookupFieldData(fieldFmt)
+			if err != nil {
+				t.Errorf("Error looking up field data: %v", err)
+				return
+			}
+			field.SetValue("ab  ")
+
+			recv := resultValue(&Info{TrimChar: cas.trimChar}, field)
+			if recv != cas.expect {
+				t.Errorf("Expected %q, received %q", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestResultValue_Uint64AsString(t *testing.T) {
+	cases := map[string]struct {
+		dataType asetypes.DataType
+		value    uint64
+		expect   driver.Value
+	}{
+		"zero":          {asetypes.UINT8, 0, "0"},
+		"2^63-1":        {asetypes.UINT8, math.MaxInt64, "9223372036854775807"},
+		"2^63":          {asetypes.UINT8, 1 << 63, "9223372036854775808"},
+		"2^64-1":        {asetypes.UINT8, math.MaxUint64, "18446744073709551615"},
+		"nullable 2^63": {asetypes.UINTN, 1 << 63, "9223372036854775808"},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := newFieldFmt(cas.dataType, 8)
+			if err != nil {
+				t.Errorf("Error creating field format: %v", err)
+				return
+			}
+
+			field, err := tds.LookupFieldData(fieldFmt)
+			if err != nil {
+				t.Errorf("Error looking up field data: %v", err)
+				return
+			}
+			field.SetValue(cas.value)
+
+			recv := resultValue(&Info{Uint64AsString: true}, field)
+			if recv != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, recv, recv)
+			}
+
+			// The scan type must match the type of all values.
+			if typ := scanType(&Info{Uint64AsString: true}, fieldFmt); typ != reflect.TypeOf(recv) {
+				t.Errorf("Expected scan type %T, received %s", recv, typ)
+			}
+
+			if recv := resultValue(&Info{}, field); recv != cas.value {
+				t.Errorf("Expected %v without uint64asstring, received %v (%T)", cas.value, recv, recv)
+			}
+		})
+	}
+}
+
+func TestResultValue_NullableNumeric(t *testing.T) {
+	cases := map[string]struct {
+		dataType asetypes.DataType
+		value    interface{}
+		expect   driver.Value
+	}{
+		"intn null":   {asetypes.INTN, 0, nil},
+		"intn zero":   {asetypes.INTN, int32(0), int32(0)},
+		"intn bigint": {asetypes.INTN, int64(-5), int64(-5)},
+		"uintn null":  {asetypes.UINTN, 0, nil},
+		"uintn zero":  {asetypes.UINTN, uint16(0), uint16(0)},
+		"fltn null":   {asetypes.FLTN, 0, nil},
+		"fltn zero":   {asetypes.FLTN, float64(0), float64(0)},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := tds.LookupFieldFmt(cas.dataType)
+			if err != nil {
+				t.Errorf("Error looking up field format: %v", err)
+				return
+			}
+
+			field, err := tds.LookupFieldData(fieldFmt)
+			if err != nil {
+				t.Errorf("Error looking up field data: %v", err)
+				return
+			}
+			field.SetValue(cas.value)
+
+			recv := resultValue(&Info{}, field)
+			if recv != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, recv, recv)
+			}
+		})
+	}
+}
+
+func TestResultValue_AllNumericFloat(t *testing.T) {
+	decimal := func(precision, scale int, s string) *asetypes.Decimal {
+		dec, err := asetypes.NewDecimalString(precision, scale, s)
+		if err != nil {
+			t.Fatalf("Error creating decimal %s: %v", s, err)
+		}
+		return dec
+	}
+
+	cases := map[string]struct {
+		dataType asetypes.DataType
+		value    interface{}
+		expect   driver.Value
+	}{
+		"tinyint":          {asetypes.INT1, uint8(255), float64(255)},
+		"smallint":         {asetypes.INT2, int16(-5), float64(-5)},
+		"int":              {asetypes.INT4, int32(7), float64(7)},
+		"bigint":           {asetypes.INT8, int64(1) << 53, float64(1 << 53)},
+		"unsigned bigint":  {asetypes.UINT8, uint64(math.MaxUint64), float64(math.MaxUint64)},
+		"intn null":        {asetypes.INTN, 0, nil},
+		"real":             {asetypes.FLT4, float32(0.1), 0.1},
+		"float":            {asetypes.FLT8, 0.25, 0.25},
+		"decimal":          {asetypes.DECN, decimal(10, 2, "123.45"), 123.45},
+		"negative numeric": {asetypes.NUMN, decimal(10, 4, "-0.0001"), -0.0001},
+		"money":            {asetypes.MONEY, decimal(asetypes.ASEMoneyPrecision, asetypes.ASEMoneyScale, "1.5"), 1.5},
+		"bit":              {asetypes.BIT, true, true},
+		"varchar":          {asetypes.VARCHAR, "1", "1"},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := tds.LookupFieldFmt(cas.dataType)
+			if err != nil {
+				t.Errorf("Error looking up field format: %v", err)
+				return
+			}
+
+			field, err := tds.LookupFieldData(fieldFmt)
+			if err != nil {
+				t.Errorf("Error looking up field data: %v", err)
+				return
+			}
+			field.SetValue(cas.value)
+
+			info := &Info{AllNumericFloat: true, NumericAsString: true}
+
+			recv := resultValue(info, field)
+			if recv != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, recv, recv)
+			}
+
+			expectFloat := isNumericColumn(fieldFmt)
+			if recv := scanType(info, fieldFmt) == reflect.TypeOf(float64(0)); recv != expectFloat {
+				t.Errorf("Expected float64 scan type %t, received %t", expectFloat, recv)
+			}
+		})
+	}
+}
+
+func TestExactDateTime(t *testing.T) {
+	base := time.Date(2021, time.March, 4, 23, 59, 59, 0, time.UTC)
+
+	cases := map[string]struct {
+		value  time.Duration
+		expect time.Duration
+	}{
+		"zero":              {0, 0},
+		"one tick":          {3333333, 3333333},
+		"two ticks":         {6666667, 6666667},
+		"three ticks":       {10 * time.Millisecond, 10 * time.Millisecond},
+		"below first tick":  {1 * time.Millisecond, 0},
+		"above first tick":  {2 * time.Millisecond, 3333333},
+		"below second tick": {4999 * time.Microsecond, 3333333},
+		"above second tick": {5 * time.Millisecond, 6666667},
+		"last tick":         {996667 * time.Microsecond, 996666667},
+		"rollover":          {999 * time.Millisecond, time.Second},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			bs, err := asetypes.DATETIME.Bytes(binary.LittleEndian, base.Add(cas.value))
+			if err != nil {
+				t.Errorf("Error encoding datetime: %v", err)
+				return
+			}
+
+			value, err := asetypes.DATETIME.GoValue(binary.LittleEndian, bs)
+			if err != nil {
+				t.Errorf("Error decoding datetime: %v", err)
+				return
+			}
+
+			recv := exactDateTime(value.(time.Time))
+			if expect := base.Add(cas.expect); !recv.Equal(expect) {
+				t.Errorf("Expected %v, received %v", expect, recv)
+			}
+		})
+	}
+}
+
+func TestExactDateTime_Ticks(t *testing.T) {
+	base := time.Date(2021, time.March, 4, 23, 59, 59, 0, time.UTC)
+
+	for ticks := 0; ticks < dateTimeTicksPerSecond; ticks++ {
+		decoded := base.Add(time.Duration(asetime.FractionalSecondToMillisecond(ticks).Microseconds()) * time.Microsecond)
+
+		expect := base.Add(time.Duration(math.Round(float64(ticks) * float64(time.Second) / dateTimeTicksPerSecond)))
+		if recv := exactDateTime(decoded); !recv.Equal(expect) {
+			t.Errorf("Expected %v for %d ticks decoded as %v, received %v", expect, ticks, decoded, recv)
+		}
+	}
+}
+
+func TestResultValue_ExactDateTime(t *testing.T) {
+	// 00:00:00.003333333 is decoded by go-dblib as .003.
+	decoded := time.Date(2021, time.March, 4, 0, 0, 0, int(3*time.Millisecond), time.UTC)
+	exact := time.Date(2021, time.March, 4, 0, 0, 0, 3333333, time.UTC)
+
+	cases := map[string]struct {
+		dataType asetypes.DataType
+		enabled  bool
+		value    interface{}
+		expect   driver.Value
+	}{
+		"datetime":          {asetypes.DATETIME, true, decoded, exact},
+		"nullable datetime": {asetypes.DATETIMEN, true, decoded, exact},
+		"null":              {asetypes.DATETIMEN, true, nil, nil},
+		"disabled":          {asetypes.DATETIMEN, false, decoded, decoded},
+		"bigdatetime":       {asetypes.BIGDATETIMEN, true, decoded, decoded},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := tds.LookupFieldFmt(cas.dataType)
+			if err != nil {
+				t.Errorf("Error looking up field format: %v", err)
+				return
+			}
+
+			field, err := tds.LookupFieldData(fieldFmt)
+			if err != nil {
+				t.Errorf("Error looking up field data: %v", err)
+				return
+			}
+			field.SetValue(cas.value)
+
+			recv := resultValue(&Info{ExactDateTime: cas.enabled}, field)
+			if recv != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, recv, recv)
+			}
+		})
+	}
+}
+
+func TestBigDateTimeMicroseconds(t *testing.T) {
+	cases := map[string]struct {
+		value time.Time
+	}{
+		"one microsecond": {time.Date(2021, time.March, 4, 0, 0, 0, 1000, time.UTC)},
+		"below tick":      {time.Date(2021, time.March, 4, 0, 0, 0, 3332000, time.UTC)},
+		"above tick":      {time.Date(2021, time.March, 4, 0, 0, 0, 3334000, time.UTC)},
+		"last":            {time.Date(2021, time.March, 4, 23, 59, 59, 999999000, time.UTC)},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			bs, err := asetypes.BIGDATETIMEN.Bytes(binary.LittleEndian, cas.value)
+			if err != nil {
+				t.Errorf("Error encoding bigdatetime: %v", err)
+				return
+			}
+
+			value, err := asetypes.BIGDATETIMEN.GoValue(binary.LittleEndian, bs)
+			if err != nil {
+				t.Errorf("Error decoding bigdatetime: %v", err)
+				return
+			}
+
+			if recv := value.(time.Time); !recv.Equal(cas.value) {
+				t.Errorf("Expected %v, received %v", cas.value, recv)
+			}
+		})
+	}
+}
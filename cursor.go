@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/SAP/go-dblib/namepool"
 	"github.com/SAP/go-dblib/tds"
@@ -29,6 +30,7 @@ type Cursor struct {
 
 	cursorID int32
 	name     string
+	query    string
 	// currently unused
 	// tableName string
 
@@ -49,42 +51,128 @@ type Cursor struct {
 	closed bool
 }
 
+// CursorOptions configure the concurrency of a cursor created with
+// NewCursor.
+//
+// ASE only allows updating rows through cursors on queries that select
+// from a single table and contain no distinct, group by, aggregate
+// functions, union or subqueries in the select list. Declaring a
+// cursor for update with other queries fails.
+type CursorOptions struct {
+	// ReadOnly declares the cursor `for read only`.
+	ReadOnly bool
+	// ForUpdate declares the cursor `for update`, which allows to
+	// update or delete the fetched rows and locks them with update
+	// locks.
+	ForUpdate bool
+	// UpdateColumns restricts the updatable columns of a cursor
+	// declared for update to the listed columns with
+	// `for update of col1, col2`.
+	UpdateColumns []string
+}
+
+// clause returns the clause to append to the query and the option to
+// declare the cursor with.
+func (opts CursorOptions) clause() (string, tds.CursorOption, error) {
+	if len(opts.UpdateColumns) > 0 && !opts.ForUpdate {
+		return "", 0, errors.New("go-ase: cursor with update columns must be declared for update")
+	}
+
+	switch {
+	case opts.ReadOnly && opts.ForUpdate:
+		return "", 0, errors.New("go-ase: cursor cannot be declared both read only and for update")
+	case opts.ReadOnly:
+		return " for read only", tds.TDS_CUR_DOPT_RDONLY, nil
+	case opts.ForUpdate && len(opts.UpdateColumns) > 0:
+		columns := make([]string, len(opts.UpdateColumns))
+		for i, column := range opts.UpdateColumns {
+			columns[i] = QuoteIdentifier(column)
+		}
+		return " for update of " + strings.Join(columns, ", "), tds.TDS_CUR_DOPT_UPDATABLE, nil
+	case opts.ForUpdate:
+		return " for update", tds.TDS_CUR_DOPT_UPDATABLE, nil
+	}
+
+	return "", tds.TDS_CUR_DOPT_UNUSED, nil
+}
+
 // NewCursor creates a new cursor.
 //
 // NewCursor is a wrapper around NewCursorWithValues that converts
-// arguments into driver.NamedValues.
+// arguments into driver.NamedValues. A CursorOptions passed in args
+// configures the cursor instead of being passed as argument:
+//
+//	cursor, err := c.NewCursor(ctx, "select a, b from t where a > ?",
+//		ase.CursorOptions{ForUpdate: true, UpdateColumns: []string{"b"}}, 5)
 func (c *Conn) NewCursor(ctx context.Context, query string, args ...interface{}) (*Cursor, error) {
-	valueArgs := make([]driver.NamedValue, len(args))
-	for i, arg := range args {
-		valueArgs[i] = driver.NamedValue{
-			Ordinal: i + 1,
-			Value:   arg,
+	var opts CursorOptions
+
+	valueArgs := make([]driver.NamedValue, 0, len(args))
+	for _, arg := range args {
+		if typed, ok := arg.(CursorOptions); ok {
+			opts = typed
+			continue
 		}
+
+		valueArgs = append(valueArgs, driver.NamedValue{
+			Ordinal: len(valueArgs) + 1,
+			Value:   arg,
+		})
 	}
 
-	return c.NewCursorWithValues(ctx, query, valueArgs)
+	return c.newCursor(ctx, query, opts, valueArgs)
 }
 
 // NewCursorWithValues creates a new cursor.
 func (c *Conn) NewCursorWithValues(ctx context.Context, query string, args []driver.NamedValue) (*Cursor, error) {
+	return c.newCursor(ctx, query, CursorOptions{}, args)
+}
+
+func (c *Conn) newCursor(ctx context.Context, query string, opts CursorOptions, args []driver.NamedValue) (*Cursor, error) {
+	clause, option, err := opts.clause()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.propagateTraceID(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	if err := c.checkBusy(); err != nil {
+		return nil, err
+	}
+
+	c.queryLog.log(ctx, query, args)
+
 	cursor := new(Cursor)
 	cursor.conn = c
+	cursor.query = query
 
-	if err := cursor.allocateOnServer(ctx, query, args); err != nil {
+	if err := cursor.allocateOnServer(ctx, query+clause, option, args); err != nil {
+		var aseErr *Error
+		if opts.ForUpdate && errors.As(err, &aseErr) {
+			return nil, fmt.Errorf("go-ase: error declaring cursor for update, the query may not be updatable: %w", err)
+		}
 		return nil, fmt.Errorf("go-ase: error allocating cursor on server: %w", err)
 	}
+	c.trackCursor(cursor)
 
 	return cursor, nil
 }
 
 // allocateOnServer allocates the cursor on the TDS server.
-func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args []driver.NamedValue) error {
+func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, option tds.CursorOption, args []driver.NamedValue) error {
 	cursor.poolName = cursorPool.Acquire()
 	cursor.hasArgs = len(args) > 0
 
 	if cursor.hasArgs {
 		// cursor has argument, prepare statement
-		stmt, err := cursor.conn.NewStmt(ctx, cursor.poolName.String(), query, true)
+		stmt, err := cursor.conn.newStmt(ctx, cursor.poolName.String(), query, true, hasNamedArgs(args))
 		if err != nil {
 			return fmt.Errorf("error creating stmt: %w", err)
 		}
@@ -102,7 +190,7 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 	// Declare cursor.
 	declarePkg, err := tds.NewCurDeclarePackage(cursor.poolName.String(), cursorQuery,
 		tds.TDS_CUR_DSTAT_UNUSED,
-		tds.TDS_CUR_DOPT_UNUSED,
+		option,
 	)
 	if err != nil {
 		return fmt.Errorf("could not create CurDeclarePackage: %w", err)
@@ -111,11 +199,11 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 		declarePkg.Options |= tds.TDS_CUR_DOPT_DYNAMIC
 	}
 
-	if err := cursor.conn.Channel.SendPackage(ctx, declarePkg); err != nil {
+	if err := cursor.conn.sendPackage(ctx, declarePkg); err != nil {
 		return fmt.Errorf("error sending CurDeclarePackage: %w", err)
 	}
 
-	_, err = cursor.conn.Channel.NextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
+	_, err = cursor.conn.nextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
 		switch typed := pkg.(type) {
 		case *tds.DynamicPackage:
 			if typed.Type&tds.TDS_DYN_ACK != tds.TDS_DYN_ACK {
@@ -147,7 +235,7 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 			}
 			return ok, nil
 		default:
-			return true, fmt.Errorf("unhandled package type %T: %s", typed, typed)
+			return true, cursor.conn.unhandledPackage(typed)
 		}
 	})
 	if err != nil && !errors.Is(err, io.EOF) {
@@ -165,11 +253,11 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 		RowCount:  int32(cursor.conn.Info.CursorCacheRows),
 	}
 
-	if err := cursor.conn.Channel.SendPackage(ctx, setFetchCount); err != nil {
+	if err := cursor.conn.sendPackage(ctx, setFetchCount); err != nil {
 		return fmt.Errorf("error queueing CurInfoPackage to set fetch row count: %w", err)
 	}
 
-	_, err = cursor.conn.Channel.NextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
+	_, err = cursor.conn.nextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
 		switch typed := pkg.(type) {
 		case *tds.DynamicPackage:
 			if typed.Type&tds.TDS_DYN_ACK != tds.TDS_DYN_ACK {
@@ -201,7 +289,7 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 			}
 			return ok, nil
 		default:
-			return true, fmt.Errorf("unhandled package type %T: %s", typed, typed)
+			return true, cursor.conn.unhandledPackage(typed)
 		}
 	})
 	if err != nil && !errors.Is(err, io.EOF) {
@@ -218,7 +306,7 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 		openPkg.Status = tds.TDS_CUR_OSTAT_HASARGS
 	}
 
-	if err := cursor.conn.Channel.QueuePackage(ctx, openPkg); err != nil {
+	if err := cursor.conn.queuePackage(ctx, openPkg); err != nil {
 		return fmt.Errorf("error queueing and sending CurOpenPackage: %w", err)
 	}
 
@@ -228,11 +316,11 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 		}
 	}
 
-	if err := cursor.conn.Channel.SendRemainingPackets(ctx); err != nil {
+	if err := cursor.conn.sendRemainingPackets(ctx); err != nil {
 		return fmt.Errorf("error sending packages: %w", err)
 	}
 
-	_, err = cursor.conn.Channel.NextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
+	_, err = cursor.conn.nextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
 		switch typed := pkg.(type) {
 		case *tds.CurInfoPackage:
 			if typed.Command != tds.TDS_CUR_CMD_INFORM {
@@ -263,7 +351,7 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 			}
 			return ok, nil
 		default:
-			return true, fmt.Errorf("unhandled package type %T: %v", typed, typed)
+			return true, cursor.conn.unhandledPackage(typed)
 		}
 	})
 	if err != nil && !errors.Is(err, io.EOF) {
@@ -276,6 +364,7 @@ func (cursor *Cursor) allocateOnServer(ctx context.Context, query string, args [
 // Close closes the cursor.
 func (cursor *Cursor) Close(ctx context.Context) error {
 	defer cursorPool.Release(cursor.poolName)
+	defer cursor.conn.untrackCursor(cursor)
 
 	// If the cursor was already closed - e.g. because its result set
 	// was exhausted and the CursorRows closed the cursor already, it
@@ -293,13 +382,18 @@ func (cursor *Cursor) Close(ctx context.Context) error {
 		}
 	}
 
+	if err := cursor.conn.acquire(); err != nil {
+		return err
+	}
+	defer cursor.conn.release()
+
 	closePkg := &tds.CurClosePackage{
 		CursorID: cursor.cursorID,
 		Name:     cursor.name,
 		Options:  tds.TDS_CUR_COPT_DEALLOC,
 	}
 
-	if err := cursor.conn.Channel.SendPackage(ctx, closePkg); err != nil {
+	if err := cursor.conn.sendPackage(ctx, closePkg); err != nil {
 		return fmt.Errorf("go-ase: error sending CurClosePackage: %w", err)
 	}
 
@@ -332,7 +426,7 @@ func (cursor *Cursor) Close(ctx context.Context) error {
 func (cursor *Cursor) closeReadResponse(ctx context.Context) (bool, error) {
 	rxCurDealloc := false
 
-	_, err := cursor.conn.Channel.NextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
+	_, err := cursor.conn.nextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
 		switch typed := pkg.(type) {
 		case *tds.CurInfoPackage:
 			if typed.Command != tds.TDS_CUR_CMD_INFORM {
@@ -363,7 +457,7 @@ func (cursor *Cursor) closeReadResponse(ctx context.Context) (bool, error) {
 			}
 			return ok, nil
 		default:
-			return true, fmt.Errorf("go-ase: unhandled package type %T: %v", typed, typed)
+			return true, cursor.conn.unhandledPackage(typed)
 		}
 	})
 	if err != nil && !errors.Is(err, io.EOF) {
@@ -381,5 +475,11 @@ func (cursor Cursor) CursorID() int {
 // Fetch returns CursorRows to iterate over the rows selected by
 // a cursor.
 func (cursor *Cursor) Fetch(ctx context.Context) (*CursorRows, error) {
-	return cursor.NewCursorRows()
+	rows, err := cursor.NewCursorRows()
+	if err != nil {
+		return nil, err
+	}
+
+	rows.maxRows = cursor.conn.maxRows(ctx)
+	return rows, nil
 }
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 
 	"github.com/SAP/go-dblib/asetypes"
 	"github.com/SAP/go-dblib/tds"
@@ -19,6 +20,7 @@ var (
 	_ driver.Rows                           = (*CursorRows)(nil)
 	_ driver.RowsColumnTypeLength           = (*CursorRows)(nil)
 	_ driver.RowsColumnTypeDatabaseTypeName = (*CursorRows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*CursorRows)(nil)
 
 	ErrCurNoMoreRows = errors.New("no more rows in cursor")
 )
@@ -34,6 +36,10 @@ type CursorRows struct {
 	// the cursor.
 	readRows  int
 	totalRows int
+
+	// maxRows is the maximum number of rows of the result set, see
+	// WithMaxRows.
+	maxRows int
 }
 
 // NewCursorRows returns CursorRows for a Cursor.
@@ -41,8 +47,9 @@ type CursorRows struct {
 // It does not immediately fetch a result set from the remote. See .Fetch.
 func (cursor *Cursor) NewCursorRows() (*CursorRows, error) {
 	return &CursorRows{
-		cursor: cursor,
-		rows:   make(chan *tds.RowPackage, cursor.conn.Info.CursorCacheRows),
+		cursor:  cursor,
+		rows:    make(chan *tds.RowPackage, cursor.conn.Info.CursorCacheRows),
+		maxRows: cursor.conn.Info.MaxRows,
 	}, nil
 }
 
@@ -66,6 +73,10 @@ func (rows CursorRows) Columns() []string {
 }
 
 // Next implements driver.Rows.
+//
+// If the result set returns more rows than the limit set through the
+// property maxrows or WithMaxRows ErrRowLimitExceeded is returned.
+// The cursor is closed with the rows.
 func (rows *CursorRows) Next(dst []driver.Value) error {
 	rowPkg, err := rows.nextPkg(context.Background())
 	if err != nil {
@@ -76,8 +87,12 @@ func (rows *CursorRows) Next(dst []driver.Value) error {
 		return fmt.Errorf("go-ase: error getting next row: %w", err)
 	}
 
+	if rows.maxRows > 0 && rows.readRows >= rows.maxRows {
+		return ErrRowLimitExceeded
+	}
+
 	for i := range dst {
-		dst[i] = rowPkg.DataFields[i].Value()
+		dst[i] = resultValue(rows.cursor.conn.Info, rowPkg.DataFields[i])
 	}
 	rows.readRows++
 
@@ -107,17 +122,22 @@ func (rows *CursorRows) nextPkg(ctx context.Context) (*tds.RowPackage, error) {
 
 // fetch retrieves the next part of the result set from the ASE server.
 func (rows *CursorRows) fetch(ctx context.Context) error {
+	if err := rows.cursor.conn.acquire(); err != nil {
+		return err
+	}
+	defer rows.cursor.conn.release()
+
 	// Set the last received package to the rowfmt received during
 	// setup. The params/rows packages need the information from the
 	// format to setup the data fields.
-	rows.cursor.conn.Channel.SetLastPkgRx(rows.cursor.rowFmt)
+	rows.cursor.conn.channel.SetLastPkgRx(rows.cursor.rowFmt)
 
 	fetchPkg := &tds.CurFetchPackage{
 		CursorID: rows.cursor.cursorID,
 		Name:     rows.cursor.name,
 		Type:     tds.TDS_CUR_NEXT,
 	}
-	if err := rows.cursor.conn.Channel.SendPackage(ctx, fetchPkg); err != nil {
+	if err := rows.cursor.conn.sendPackage(ctx, fetchPkg); err != nil {
 		return fmt.Errorf("error sending CurFetchPackage: %w", err)
 	}
 
@@ -140,7 +160,7 @@ func (rows *CursorRows) fetch(ctx context.Context) error {
 	// cursor finished the result set.
 	readMoreRows := false
 
-	_, err := rows.cursor.conn.Channel.NextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
+	_, err := rows.cursor.conn.nextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
 		switch typed := pkg.(type) {
 		case *tds.RowPackage:
 			rows.rows <- typed
@@ -151,7 +171,7 @@ func (rows *CursorRows) fetch(ctx context.Context) error {
 			// finished?
 			rows.cursor.rowFmt = typed
 			return false, nil
-		case *tds.OrderByPackage:
+		case *tds.OrderByPackage, *tds.OrderBy2Package:
 			return false, nil
 		case *tds.CurInfoPackage:
 			// When the result set is exhausted the TDS server
@@ -175,7 +195,7 @@ func (rows *CursorRows) fetch(ctx context.Context) error {
 
 			return false, nil
 		case *tds.DonePackage:
-			if typed.Status&tds.TDS_DONE_COUNT == tds.TDS_DONE_COUNT {
+			if typed.Status&tds.TDS_DONE_COUNT == tds.TDS_DONE_COUNT && typed.Status&tds.TDS_DONE_ERROR == 0 {
 				rows.totalRows += int(typed.Count)
 				return false, nil
 			}
@@ -187,12 +207,12 @@ func (rows *CursorRows) fetch(ctx context.Context) error {
 
 			return ok, nil
 		case *tds.ReturnStatusPackage:
-			if typed.ReturnValue != 0 {
-				return true, fmt.Errorf("query failed with return status %d", typed.ReturnValue)
+			if err := returnStatusError(rows.cursor.conn.Info, typed); err != nil {
+				return true, err
 			}
 			return false, nil
 		default:
-			return true, fmt.Errorf("unhandled package type %T: %v", pkg, pkg)
+			return true, rows.cursor.conn.unhandledPackage(pkg)
 		}
 	})
 
@@ -216,6 +236,11 @@ func (rows *CursorRows) fetch(ctx context.Context) error {
 
 // Delete deletes the last read row.
 func (rows *CursorRows) Delete(ctx context.Context) error {
+	if err := rows.cursor.conn.acquire(); err != nil {
+		return err
+	}
+	defer rows.cursor.conn.release()
+
 	delPkg := new(tds.CurDeletePackage)
 	delPkg.CursorID = rows.cursor.cursorID
 	if rows.cursor.paramFmt != nil {
@@ -226,7 +251,7 @@ func (rows *CursorRows) Delete(ctx context.Context) error {
 		return fmt.Errorf("go-ase: cursor has neither paramFmt nor rowFmt set")
 	}
 
-	if err := rows.cursor.conn.Channel.QueuePackage(ctx, delPkg); err != nil {
+	if err := rows.cursor.conn.queuePackage(ctx, delPkg); err != nil {
 		return fmt.Errorf("go-ase: error queueing CurDeletePackage: %w", err)
 	}
 
@@ -234,11 +259,11 @@ func (rows *CursorRows) Delete(ctx context.Context) error {
 	keyPkg.DataType = asetypes.INTN
 	keyPkg.Value = int64(rows.readRows - 1)
 
-	if err := rows.cursor.conn.Channel.SendPackage(ctx, keyPkg); err != nil {
+	if err := rows.cursor.conn.sendPackage(ctx, keyPkg); err != nil {
 		return fmt.Errorf("go-ase: error sending KeyPackage: %w", err)
 	}
 
-	if err := finalize(ctx, rows.cursor.conn.Channel); err != nil {
+	if err := rows.cursor.conn.finalize(ctx); err != nil {
 		return err
 	}
 
@@ -253,5 +278,11 @@ func (rows CursorRows) ColumnTypeLength(index int) (int64, bool) {
 // ColumnTypeDatabaseTypeName implements the
 // driver.RowsColumnTypeDatabaseTypeName interface.
 func (rows CursorRows) ColumnTypeDatabaseTypeName(index int) string {
-	return string(rows.cursor.rowFmt.Fmts[index].DataType())
+	return databaseTypeName(rows.cursor.rowFmt.Fmts[index])
+}
+
+// ColumnTypeScanType implements the driver.RowsColumnTypeScanType
+// interface.
+func (rows CursorRows) ColumnTypeScanType(index int) reflect.Type {
+	return scanType(rows.cursor.conn.Info, rows.cursor.rowFmt.Fmts[index])
 }
@@ -130,6 +130,7 @@ func BenchmarkRows_Next(b *testing.B) {
 }
 
 func rows_Next(b *testing.B, conn *Conn) {
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		rows, _, err := conn.DirectExec(context.Background(), query)
@@ -150,3 +151,30 @@ func rows_Next(b *testing.B, conn *Conn) {
 		}
 	}
 }
+
+func BenchmarkRows_NextInto(b *testing.B) {
+	prepare(b, rows_NextInto)
+}
+
+func rows_NextInto(b *testing.B, conn *Conn) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, _, err := conn.DirectExec(context.Background(), query)
+		if err != nil {
+			b.Errorf("error executing statement: %v", err)
+			return
+		}
+
+		values := []driver.Value{0, make([]byte, 0, 32)}
+		for {
+			if err := rows.(*Rows).NextInto(values); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				b.Errorf("error scanning fields: %v", err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestCursorOptions_clause(t *testing.T) {
+	cases := map[string]struct {
+		opts   CursorOptions
+		clause string
+		option tds.CursorOption
+		err    bool
+	}{
+		"default":        {CursorOptions{}, "", tds.TDS_CUR_DOPT_UNUSED, false},
+		"read only":      {CursorOptions{ReadOnly: true}, " for read only", tds.TDS_CUR_DOPT_RDONLY, false},
+		"for update":     {CursorOptions{ForUpdate: true}, " for update", tds.TDS_CUR_DOPT_UPDATABLE, false},
+		"update columns": {CursorOptions{ForUpdate: true, UpdateColumns: []string{"a", "b c"}}, " for update of [a], [b c]", tds.TDS_CUR_DOPT_UPDATABLE, false},
+		"conflicting":    {CursorOptions{ReadOnly: true, ForUpdate: true}, "", 0, true},
+		"columns only":   {CursorOptions{UpdateColumns: []string{"a"}}, "", 0, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			clause, option, err := cas.opts.clause()
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error, received clause %q", clause)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if clause != cas.clause {
+				t.Errorf("Expected clause %q, received %q", cas.clause, clause)
+			}
+
+			if option != cas.option {
+				t.Errorf("Expected option %s, received %s", cas.option, option)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+)
+
+// msgDeadlockVictim is the number of the message the server sends to
+// the connection it chose as deadlock victim.
+const msgDeadlockVictim = 1205
+
+// ErrDeadlockVictim is matched by the errors of commands that were
+// aborted because the server chose the connection as deadlock victim.
+//
+// The server rolls back the transaction of the victim. Retry logic can
+// check for the error with errors.Is and repeat the transaction:
+//
+//	if errors.Is(err, ase.ErrDeadlockVictim) {
+//		// retry
+//	}
+var ErrDeadlockVictim = errors.New("go-ase: connection was chosen as deadlock victim")
+
+// isDeadlockVictim reports if the server reported the connection as
+// deadlock victim in any of the messages of the error.
+func (e *Error) isDeadlockVictim() bool {
+	for _, msg := range e.Messages {
+		if msg.MsgNumber == msgDeadlockVictim {
+			return true
+		}
+	}
+	return false
+}
+
+// abortTransaction marks the transaction of the connection as rolled
+// back by the server.
+func (c *Conn) abortTransaction() {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	c.txAborted = true
+	c.txDepth = 0
+}
+
+// takeTxAborted reports if the transaction of the connection was
+// rolled back by the server and resets the mark.
+func (c *Conn) takeTxAborted() bool {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	aborted := c.txAborted
+	c.txAborted = false
+	return aborted
+}
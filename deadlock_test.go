@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestErrorIsDeadlockVictim(t *testing.T) {
+	cases := map[string]struct {
+		msgNumbers []uint32
+		wrap       bool
+		expect     bool
+	}{
+		"deadlock":         {[]uint32{msgDeadlockVictim}, false, true},
+		"later message":    {[]uint32{3621, msgDeadlockVictim}, false, true},
+		"other error":      {[]uint32{2601}, false, false},
+		"multiple errors":  {[]uint32{2601, 3621}, false, false},
+		"wrapped deadlock": {[]uint32{msgDeadlockVictim}, true, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			eeds := make([]*tds.EEDPackage, len(cas.msgNumbers))
+			for i, msgNumber := range cas.msgNumbers {
+				eeds[i] = &tds.EEDPackage{MsgNumber: msgNumber, Class: minErrorSeverity}
+			}
+
+			var err error = newError(errors.New("test"), eeds)
+			if cas.wrap {
+				err = fmt.Errorf("go-ase: error executing: %w", err)
+			}
+
+			if recv := errors.Is(err, ErrDeadlockVictim); recv != cas.expect {
+				t.Errorf("Expected %t, received %t", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestConn_takeTxAborted(t *testing.T) {
+	c := &Conn{sessionLock: &sync.Mutex{}}
+
+	if c.takeTxAborted() {
+		t.Errorf("Expected no aborted transaction")
+	}
+
+	c.abortTransaction()
+
+	if !c.takeTxAborted() {
+		t.Errorf("Expected aborted transaction")
+	}
+
+	if c.takeTxAborted() {
+		t.Errorf("Expected mark to be reset")
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Interface satisfaction checks.
+var (
+	_ sql.Scanner = (*durationScanner)(nil)
+)
+
+// maxTimeOfDay is the exclusive upper bound of durations bound to and
+// scanned from time values.
+const maxTimeOfDay = 24 * time.Hour
+
+// AsDuration returns a sql.Scanner scanning time values into d as the
+// duration since midnight:
+//
+//	var d time.Duration
+//	db.QueryRow("select opens from shops where id = ?", id).Scan(ase.AsDuration(&d))
+//
+// Scanning NULL into d returns an error.
+func AsDuration(d *time.Duration) sql.Scanner {
+	return &durationScanner{d: d}
+}
+
+type durationScanner struct {
+	d *time.Duration
+}
+
+// Scan implements the sql.Scanner interface.
+func (scanner *durationScanner) Scan(src interface{}) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return fmt.Errorf("go-ase: cannot scan %T into time.Duration", src)
+	}
+
+	hour, min, sec := t.Clock()
+	*scanner.d = time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+	return nil
+}
+
+// timeOfDay returns the time value of a duration since midnight.
+func timeOfDay(d time.Duration) (time.Time, error) {
+	if d < 0 || d >= maxTimeOfDay {
+		return time.Time{}, fmt.Errorf("duration %s is not within the range of time values [0, %s)", d, maxTimeOfDay)
+	}
+
+	return time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC).Add(d), nil
+}
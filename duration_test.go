@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeOfDay(t *testing.T) {
+	cases := map[string]struct {
+		d         time.Duration
+		expect    string
+		expectErr bool
+	}{
+		"midnight":    {0, "00:00:00.000", false},
+		"noon":        {12 * time.Hour, "12:00:00.000", false},
+		"fraction":    {8*time.Hour + 30*time.Minute + 1500*time.Millisecond, "08:30:01.500", false},
+		"last":        {24*time.Hour - time.Millisecond, "23:59:59.999", false},
+		"end of day":  {24 * time.Hour, "", true},
+		"negative":    {-time.Second, "", true},
+		"exceeds day": {36 * time.Hour, "", true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := timeOfDay(cas.d)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if s := recv.Format("15:04:05.000"); s != cas.expect {
+				t.Errorf("Expected %s, received %s", cas.expect, s)
+			}
+		})
+	}
+}
+
+func TestAsDuration(t *testing.T) {
+	cases := map[string]struct {
+		src       interface{}
+		expect    time.Duration
+		expectErr bool
+	}{
+		"time": {
+			time.Date(1, time.January, 1, 8, 30, 1, int(500*time.Millisecond), time.UTC),
+			8*time.Hour + 30*time.Minute + 1500*time.Millisecond, false,
+		},
+		"date ignored": {
+			time.Date(2021, time.March, 4, 23, 0, 0, 0, time.UTC),
+			23 * time.Hour, false,
+		},
+		"null":   {nil, 0, true},
+		"string": {"08:30:00", 0, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			var d time.Duration
+			err := AsDuration(&d).Scan(cas.src)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if d != cas.expect {
+				t.Errorf("Expected %s, received %s", cas.expect, d)
+			}
+		})
+	}
+}
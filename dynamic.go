@@ -33,9 +33,15 @@ type Stmt struct {
 
 	stmtId *namepool.Name
 	pkg    *tds.DynamicPackage
+	query  string
 
 	paramFmt *tds.ParamFmtPackage
 	rowFmt   *tds.RowFmtPackage
+
+	// paramNames are the names of the named placeholders in the order
+	// of the parameter formats. It is nil if the statement uses
+	// positional placeholders.
+	paramNames []string
 }
 
 // Prepare implements the driver.Conn interface.
@@ -45,13 +51,50 @@ func (c *Conn) Prepare(query string) (driver.Stmt, error) {
 
 // PrepareContext implements the driver.ConnPrepareContext interface.
 func (c *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	// TODO option for create_proc
-	return c.NewStmt(ctx, "", query, true)
+	return c.NewStmt(ctx, "", query, c.createProc())
+}
+
+// createProc reports if statements prepared for database/sql are
+// allocated as lightweight procedures, see the property
+// no-dynamic-proc. Servers that did not acknowledge the capability
+// TDS_PROTO_DYNPROC do not expect procedures to be created either.
+func (c *Conn) createProc() bool {
+	return !c.Info.NoDynamicProc && c.HasCapability(tds.TDS_PROTO_DYNPROC)
 }
 
 // NewStmt creates a new statement.
+//
+// If create_proc is set the statement is allocated as a lightweight
+// procedure, whose plan can be shared by the statement cache of the
+// server, otherwise the query is prepared as-is.
 func (c *Conn) NewStmt(ctx context.Context, name, query string, create_proc bool) (*Stmt, error) {
-	stmt := &Stmt{conn: c}
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	return c.newStmt(ctx, name, query, create_proc, true)
+}
+
+// newStmt creates a new statement without acquiring the connection.
+//
+// Named placeholders are only replaced if named is set, i.e. if the
+// statement may be executed with named arguments.
+func (c *Conn) newStmt(ctx context.Context, name, query string, create_proc, named bool) (*Stmt, error) {
+	if err := c.checkBusy(); err != nil {
+		return nil, err
+	}
+
+	stmt := &Stmt{conn: c, query: query}
+
+	if named {
+		parsed, paramNames, err := parseNamedParams(query)
+		if err != nil {
+			return nil, err
+		}
+		query = parsed
+		stmt.paramNames = paramNames
+	}
 
 	if name == "" {
 		// TODO different pools for procs and prepares
@@ -59,7 +102,7 @@ func (c *Conn) NewStmt(ctx context.Context, name, query string, create_proc bool
 		name = stmt.stmtId.Name()
 	}
 
-	stmt.pkg = tds.NewDynamicPackage(true)
+	stmt.pkg = tds.NewDynamicPackage(c.wideTables())
 	stmt.pkg.ID = name
 
 	if create_proc {
@@ -72,7 +115,14 @@ func (c *Conn) NewStmt(ctx context.Context, name, query string, create_proc bool
 	stmt.Reset()
 
 	if err := stmt.allocateOnServer(ctx); err != nil {
-		return nil, fmt.Errorf("go-ase: error allocating dynamic statement '%s': %w", query, err)
+		return nil, fmt.Errorf("go-ase: error allocating dynamic statement '%s': %w", stmt.query, err)
+	}
+	c.trackStmt(stmt)
+
+	if stmt.paramNames != nil && (stmt.paramFmt == nil || len(stmt.paramFmt.Fmts) != len(stmt.paramNames)) {
+		stmt.close(ctx)
+		return nil, fmt.Errorf("go-ase: server reported a different number of parameters than the %d named placeholders in '%s'",
+			len(stmt.paramNames), stmt.query)
 	}
 
 	return stmt, nil
@@ -82,7 +132,7 @@ func (c *Conn) NewStmt(ctx context.Context, name, query string, create_proc bool
 // on the server and retrieves the input and output formats.
 func (stmt *Stmt) allocateOnServer(ctx context.Context) error {
 	stmt.pkg.Type = tds.TDS_DYN_PREPARE
-	if err := stmt.conn.Channel.SendPackage(ctx, stmt.pkg); err != nil {
+	if err := stmt.conn.sendPackage(ctx, stmt.pkg); err != nil {
 		return fmt.Errorf("error queueing dynamic prepare package: %w", err)
 	}
 	stmt.Reset()
@@ -91,7 +141,7 @@ func (stmt *Stmt) allocateOnServer(ctx context.Context) error {
 		return err
 	}
 
-	_, err := stmt.conn.Channel.NextPackageUntil(ctx, true,
+	_, err := stmt.conn.nextPackageUntil(ctx, true,
 		func(pkg tds.Package) (bool, error) {
 			switch typed := pkg.(type) {
 			case *tds.ParamFmtPackage:
@@ -128,6 +178,12 @@ func (stmt Stmt) fieldFmts() ([]tds.FieldFmt, error) {
 	return nil, fmt.Errorf("bot paramFmt and rowFmt are unset")
 }
 
+// Name returns the name the statement is allocated with on the server,
+// e.g. to look it up in monitoring tables such as monCachedStatement.
+func (stmt Stmt) Name() string {
+	return stmt.pkg.ID
+}
+
 // Reset resets a statement.
 func (stmt *Stmt) Reset() {
 	stmt.pkg.Type = tds.TDS_DYN_INVALID
@@ -140,14 +196,24 @@ func (stmt *Stmt) Close() error {
 }
 
 func (stmt *Stmt) close(ctx context.Context) error {
+	if err := stmt.conn.acquire(); err != nil {
+		return err
+	}
+	defer stmt.conn.release()
+
+	if err := stmt.conn.checkBusy(); err != nil {
+		return err
+	}
+
 	if stmt.stmtId != nil {
 		defer stmtIdPool.Release(stmt.stmtId)
 	}
+	defer stmt.conn.untrackStmt(stmt)
 
 	// communicate deallocation with server
 	// TODO option to not deallocate procs
 	stmt.pkg.Type = tds.TDS_DYN_DEALLOC
-	if err := stmt.conn.Channel.SendPackage(ctx, stmt.pkg); err != nil {
+	if err := stmt.conn.sendPackage(ctx, stmt.pkg); err != nil {
 		return fmt.Errorf("error sending dealloc package: %w", err)
 	}
 	stmt.Reset()
@@ -164,7 +230,13 @@ func (stmt *Stmt) close(ctx context.Context) error {
 }
 
 // NumInput implements the driver.Stmt interface.
+//
+// Named placeholders used multiple times count as one input.
 func (stmt Stmt) NumInput() int {
+	if stmt.paramNames != nil {
+		return numNamedParams(stmt.paramNames)
+	}
+
 	fieldFmts, err := stmt.fieldFmts()
 	if err != nil {
 		return -1
@@ -183,7 +255,15 @@ func (stmt Stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (dri
 	if rows != nil {
 		rows.Close()
 	}
-	return result, err
+	if err != nil {
+		return result, err
+	}
+
+	if err := stmt.conn.setLastInsertId(ctx, stmt.query, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // Query implements the driver.Stmt interface.
@@ -218,13 +298,40 @@ func (stmt Stmt) DirectExec(ctx context.Context, args ...interface{}) (driver.Ro
 
 // GenericExec is the central method through which SQL statements are
 // sent to ASE.
+//
+// Statements failing with a retryable error are executed again, see
+// Connector.SetRetryableErrors.
 func (stmt Stmt) GenericExec(ctx context.Context, args []driver.NamedValue) (driver.Rows, driver.Result, error) {
+	if err := stmt.conn.propagateTraceID(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return stmt.conn.withRetry(ctx, func() (driver.Rows, driver.Result, error) {
+		return stmt.genericExec(ctx, args)
+	})
+}
+
+// genericExec executes the statement once.
+func (stmt Stmt) genericExec(ctx context.Context, args []driver.NamedValue) (driver.Rows, driver.Result, error) {
+	if err := stmt.conn.acquire(); err != nil {
+		return nil, nil, err
+	}
+	defer stmt.conn.release()
+
+	if err := stmt.conn.checkBusy(); err != nil {
+		return nil, nil, err
+	}
+
+	stmt.conn.resetStats()
+	stmt.conn.takeMessages()
+	stmt.conn.queryLog.log(ctx, stmt.query, args)
+
 	// Prepare and send payload
 	stmt.pkg.Type = tds.TDS_DYN_EXEC
 	if stmt.paramFmt != nil {
 		stmt.pkg.Status |= tds.TDS_DYNAMIC_HASARGS
 	}
-	if err := stmt.conn.Channel.QueuePackage(ctx, stmt.pkg); err != nil {
+	if err := stmt.conn.queuePackage(ctx, stmt.pkg); err != nil {
 		return nil, nil, fmt.Errorf("error queueing dynamic statement exec package: %w", err)
 	}
 	stmt.Reset()
@@ -235,7 +342,7 @@ func (stmt Stmt) GenericExec(ctx context.Context, args []driver.NamedValue) (dri
 		}
 	}
 
-	if err := stmt.conn.Channel.SendRemainingPackets(ctx); err != nil {
+	if err := stmt.conn.sendRemainingPackets(ctx); err != nil {
 		return nil, nil, fmt.Errorf("error sending queued packages for dynamic statement execution: %w", err)
 	}
 
@@ -248,18 +355,45 @@ func (stmt Stmt) GenericExec(ctx context.Context, args []driver.NamedValue) (dri
 }
 
 func (stmt Stmt) sendArgs(ctx context.Context, args []driver.NamedValue) error {
-	if err := stmt.conn.Channel.QueuePackage(ctx, stmt.paramFmt); err != nil {
-		return fmt.Errorf("error queueing dynamic statement parameter format: %w", err)
+	if stmt.paramNames != nil {
+		var err error
+		args, err = bindNamedParams(stmt.paramNames, args)
+		if err != nil {
+			return err
+		}
 	}
 
+	// The formats of NULL arguments may be replaced, the formats of the
+	// statement are copied on the first replacement.
+	paramFmt := stmt.paramFmt
+
 	dataFields := []tds.FieldData{}
 
 	for i, arg := range args {
 		if err := stmt.CheckNamedValue(&arg); err != nil {
 			return fmt.Errorf("error checking argument: %w", err)
 		}
+		arg.Value, _ = unwrapSensitive(arg.Value)
+
+		fmtField := paramFmt.Fmts[i]
 
-		fmtField := stmt.paramFmt.Fmts[i]
+		if arg.Value == nil {
+			nullFmt, value, err := nullValue(fmtField)
+			if err != nil {
+				return fmt.Errorf("error binding NULL to parameter %d: %w", arg.Ordinal, err)
+			}
+
+			if nullFmt != fmtField {
+				if paramFmt == stmt.paramFmt {
+					copied := *stmt.paramFmt
+					copied.Fmts = append([]tds.FieldFmt{}, stmt.paramFmt.Fmts...)
+					paramFmt = &copied
+				}
+				paramFmt.Fmts[i] = nullFmt
+				fmtField = nullFmt
+			}
+			arg.Value = value
+		}
 
 		dataField, err := tds.LookupFieldData(fmtField)
 		if err != nil {
@@ -271,7 +405,11 @@ func (stmt Stmt) sendArgs(ctx context.Context, args []driver.NamedValue) error {
 		dataFields = append(dataFields, dataField)
 	}
 
-	if err := stmt.conn.Channel.QueuePackage(ctx, tds.NewParamsPackage(dataFields...)); err != nil {
+	if err := stmt.conn.queuePackage(ctx, paramFmt); err != nil {
+		return fmt.Errorf("error queueing dynamic statement parameter format: %w", err)
+	}
+
+	if err := stmt.conn.queuePackage(ctx, tds.NewParamsPackage(dataFields...)); err != nil {
 		return fmt.Errorf("error queueing dynamic statement parameters: %w", err)
 	}
 
@@ -279,20 +417,66 @@ func (stmt Stmt) sendArgs(ctx context.Context, args []driver.NamedValue) error {
 }
 
 // CheckNamedValue implements the driver.NamedValueChecker interface.
+//
+// The value is converted to the Go type expected for the parameter
+// format the server reported when the statement was prepared. Strings
+// are parsed for non-character parameters, e.g. "2021-02-01" for
+// a date parameter.
+//
+// Named values of statements with named placeholders are converted for
+// the parameter of the placeholder with the same name.
+//
+// Character and binary values longer than the parameter are rejected
+// with ErrParamTruncated, see the property allowtruncation.
+//
+// Values marked by Sensitive stay marked after being converted.
 func (stmt Stmt) CheckNamedValue(named *driver.NamedValue) error {
+	if ok, err := checkSensitive(named, stmt.CheckNamedValue); ok {
+		return err
+	}
+
 	fieldFmts, err := stmt.fieldFmts()
 	if err != nil {
 		return fmt.Errorf("go-ase: no formats are set: %w", err)
 	}
 
-	if named.Ordinal-1 >= len(fieldFmts) {
+	index := named.Ordinal - 1
+	if stmt.paramNames != nil && named.Name != "" {
+		index = -1
+		for i, name := range stmt.paramNames {
+			if name == named.Name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("go-ase: argument %q does not match a placeholder", named.Name)
+		}
+	}
+
+	if index >= len(fieldFmts) {
 		return fmt.Errorf("go-ase: ordinal %d (index %d) is larger than the number of expected arguments %d",
-			named.Ordinal, named.Ordinal-1, len(fieldFmts))
+			named.Ordinal, index, len(fieldFmts))
+	}
+
+	if valuer, ok := named.Value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return fmt.Errorf("go-ase: error calling Value on %T: %w", named.Value, err)
+		}
+		named.Value = v
+	}
+	named.Value = stmt.conn.emptyStringAsNull(stmt.conn.normalize(named.Value), isNullableParam(fieldFmts[index]))
+
+	val, err := convertValue(fieldFmts[index], named.Value)
+	if err != nil {
+		return fmt.Errorf("go-ase: error converting parameter %d: %w", named.Ordinal, err)
 	}
+	val = emptyValue(fieldFmts[index], val)
 
-	val, err := fieldFmts[named.Ordinal-1].DataType().ConvertValue(named.Value)
+	val, err = stmt.conn.checkLength(fieldFmts[index], val)
 	if err != nil {
-		return fmt.Errorf("go-ase: error converting value: %w", err)
+		return fmt.Errorf("go-ase: error binding parameter %d: %w", named.Ordinal, err)
 	}
 
 	named.Value = val
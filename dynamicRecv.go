@@ -15,7 +15,7 @@ import (
 )
 
 func (stmt Stmt) recvDynAck(ctx context.Context) error {
-	_, err := stmt.conn.Channel.NextPackageUntil(ctx, true,
+	_, err := stmt.conn.nextPackageUntil(ctx, true,
 		func(pkg tds.Package) (bool, error) {
 			ack, ok := pkg.(*tds.DynamicPackage)
 			if !ok {
@@ -38,7 +38,7 @@ func (stmt Stmt) recvDynAck(ctx context.Context) error {
 }
 
 func (stmt Stmt) recvDoneFinal(ctx context.Context) error {
-	_, err := stmt.conn.Channel.NextPackageUntil(ctx, true,
+	_, err := stmt.conn.nextPackageUntil(ctx, true,
 		func(pkg tds.Package) (bool, error) {
 			done, ok := pkg.(*tds.DonePackage)
 			if !ok {
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"testing"
+)
+
+func BenchmarkStmt_ExecProc(b *testing.B) {
+	prepare(b, func(b *testing.B, conn *Conn) {
+		stmt_Exec(b, conn, true)
+	})
+}
+
+func BenchmarkStmt_ExecDynamic(b *testing.B) {
+	prepare(b, func(b *testing.B, conn *Conn) {
+		stmt_Exec(b, conn, false)
+	})
+}
+
+func stmt_Exec(b *testing.B, conn *Conn, createProc bool) {
+	stmt, err := conn.NewStmt(context.Background(), "", "select a, b from "+tablename+" where a = ?", createProc)
+	if err != nil {
+		b.Errorf("error preparing statement: %v", err)
+		return
+	}
+	defer stmt.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, _, err := stmt.DirectExec(context.Background(), i%1000)
+		if err != nil {
+			b.Errorf("error executing statement: %v", err)
+			return
+		}
+
+		if err := rows.Close(); err != nil {
+			b.Errorf("error closing rows: %v", err)
+			return
+		}
+	}
+}
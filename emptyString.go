@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// emptyStringAsNull returns nil if value is an empty string, the
+// parameter it is bound to is nullable and Info.EmptyStringAsNull is
+// set.
+func (c *Conn) emptyStringAsNull(value interface{}, nullable bool) interface{} {
+	if !nullable || c.Info == nil || !c.Info.EmptyStringAsNull {
+		return value
+	}
+
+	if s, ok := value.(string); ok && s == "" {
+		return nil
+	}
+
+	return value
+}
+
+// isNullableParam reports whether the server accepts NULL for
+// a parameter of a prepared statement, e.g. because it is compared to
+// or stored in a nullable column.
+func isNullableParam(fieldFmt tds.FieldFmt) bool {
+	status := tds.ParamFmtStatus(fieldFmt.Status())
+	return status&tds.TDS_PARAM_NULLALLOWED == tds.TDS_PARAM_NULLALLOWED
+}
+
+// emptyValue returns the value transmitting value for the passed
+// format if it is an empty string or byte slice.
+//
+// TDS transmits values of length zero as NULL. Empty strings are sent
+// as a single space instead, which is how ASE stores an empty string
+// literal. Empty byte slices are sent as a single zero byte.
+func emptyValue(fieldFmt tds.FieldFmt, value interface{}) interface{} {
+	switch typed := value.(type) {
+	case string:
+		if typed != "" {
+			return value
+		}
+	case []byte:
+		if len(typed) != 0 {
+			return value
+		}
+	default:
+		return value
+	}
+
+	switch fieldFmt.DataType() {
+	case asetypes.CHAR, asetypes.VARCHAR, asetypes.LONGCHAR, asetypes.TEXT, asetypes.UNITEXT:
+		if _, ok := value.(string); ok {
+			return " "
+		}
+		return []byte{' '}
+	case asetypes.BINARY, asetypes.VARBINARY, asetypes.LONGBINARY, asetypes.IMAGE:
+		if _, ok := value.(string); ok {
+			return "\x00"
+		}
+		return []byte{0}
+	}
+
+	return value
+}
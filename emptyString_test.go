@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestConn_emptyStringAsNull(t *testing.T) {
+	cases := map[string]struct {
+		enabled  bool
+		nullable bool
+		value    interface{}
+		expect   interface{}
+	}{
+		"disabled":     {false, true, "", ""},
+		"empty string": {true, true, "", nil},
+		"not nullable": {true, false, "", ""},
+		"string":       {true, true, "a", "a"},
+		"space":        {true, true, " ", " "},
+		"empty bytes":  {true, true, []byte{}, []byte{}},
+		"non-string":   {true, true, int64(0), int64(0)},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{Info: &Info{EmptyStringAsNull: cas.enabled}}
+			if recv := c.emptyStringAsNull(cas.value, cas.nullable); !reflect.DeepEqual(recv, cas.expect) {
+				t.Errorf("Expected %#v, received %#v", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestIsNullableParam(t *testing.T) {
+	fieldFmt, err := tds.LookupFieldFmt(asetypes.VARCHAR)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if isNullableParam(fieldFmt) {
+		t.Errorf("Expected parameter without status to be not nullable")
+	}
+
+	fieldFmt.SetStatus(uint(tds.TDS_PARAM_NULLALLOWED))
+	if !isNullableParam(fieldFmt) {
+		t.Errorf("Expected parameter with TDS_PARAM_NULLALLOWED to be nullable")
+	}
+}
+
+func TestEmptyValue(t *testing.T) {
+	cases := map[string]struct {
+		dataType asetypes.DataType
+		value    interface{}
+		expect   interface{}
+	}{
+		"empty string":        {asetypes.VARCHAR, "", " "},
+		"empty string bytes":  {asetypes.LONGCHAR, []byte{}, []byte{' '}},
+		"empty text":          {asetypes.TEXT, "", " "},
+		"empty unitext":       {asetypes.UNITEXT, "", " "},
+		"string":              {asetypes.VARCHAR, "a", "a"},
+		"empty binary":        {asetypes.LONGBINARY, []byte{}, []byte{0}},
+		"empty image":         {asetypes.IMAGE, []byte{}, []byte{0}},
+		"binary":              {asetypes.LONGBINARY, []byte{1}, []byte{1}},
+		"null":                {asetypes.VARCHAR, nil, nil},
+		"empty string as int": {asetypes.INTN, "", ""},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := tds.LookupFieldFmt(cas.dataType)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if recv := emptyValue(fieldFmt, cas.value); !reflect.DeepEqual(recv, cas.expect) {
+				t.Errorf("Expected %#v, received %#v", cas.expect, recv)
+			}
+		})
+	}
+}
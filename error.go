@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// minErrorSeverity is the lowest severity of server messages reporting
+// an error. Messages with a lower severity are informational.
+const minErrorSeverity = 11
+
+// Error is returned when the server reports an error for a command.
+//
+// The fields are populated from the first error message of the
+// command, which usually is the cause of any following errors. All
+// messages are available in Messages.
+//
+// Error wraps the *tds.EEDError returned by go-dblib, which can still
+// be retrieved with errors.As.
+type Error struct {
+	// MsgNumber is the number of the message, e.g. 208 for an unknown
+	// object or the number passed to raiserror.
+	MsgNumber uint32
+	State     uint8
+	Severity  uint8
+	SQLState  string
+	Message   string
+
+	// ServerName is the name of the server that raised the error.
+	ServerName string
+	// ProcName is the name of the stored procedure that raised the
+	// error, or empty if the error was not raised in a procedure.
+	ProcName string
+	// LineNr is the line in the command batch or stored procedure
+	// the error was raised on.
+	LineNr int
+
+	// Messages are all messages the server sent with the command.
+	Messages []*tds.EEDPackage
+
+	err error
+}
+
+// newError returns an *Error wrapping err populated from the passed
+// messages.
+func newError(err error, eeds []*tds.EEDPackage) *Error {
+	eed := eeds[0]
+	for _, msg := range eeds {
+		if msg.Class >= minErrorSeverity {
+			eed = msg
+			break
+		}
+	}
+
+	return &Error{
+		MsgNumber:  eed.MsgNumber,
+		State:      eed.State,
+		Severity:   eed.Class,
+		SQLState:   string(eed.SQLState),
+		Message:    eed.Msg,
+		ServerName: eed.ServerName,
+		ProcName:   eed.ProcName,
+		LineNr:     int(eed.LineNr),
+		Messages:   eeds,
+		err:        err,
+	}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	location := fmt.Sprintf("line %d", e.LineNr)
+	if e.ProcName != "" {
+		location = fmt.Sprintf("procedure %s, %s", e.ProcName, location)
+	}
+
+	return fmt.Sprintf("%v (msg %d, severity %d, %s)", e.err, e.MsgNumber, e.Severity, location)
+}
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports if target is ErrDeadlockVictim and the server reported
+// the connection as deadlock victim.
+func (e *Error) Is(target error) bool {
+	return target == ErrDeadlockVictim && e.isDeadlockVictim()
+}
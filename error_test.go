@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestError(t *testing.T) {
+	integration.TestForEachDB("TestError", t, testError)
+}
+
+func testError(t *testing.T, db *sql.DB, tableName string) {
+	procName := tableName + "_proc"
+
+	// raiserror is on the fifth line of the procedure
+	proc := fmt.Sprintf(`create procedure %s
+as
+begin
+	declare @a int
+	raiserror 20001 'go-ase test error'
+end`, procName)
+
+	if _, err := db.Exec(proc); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	_, err := db.Exec("exec " + procName)
+	if err == nil {
+		t.Errorf("Expected error executing procedure")
+		return
+	}
+
+	var aseErr *Error
+	if !errors.As(err, &aseErr) {
+		t.Errorf("Expected *Error, received %T: %v", err, err)
+		return
+	}
+
+	if aseErr.MsgNumber != 20001 {
+		t.Errorf("Expected message number 20001, received %d", aseErr.MsgNumber)
+	}
+
+	if aseErr.Message != "go-ase test error" {
+		t.Errorf("Expected message %q, received %q", "go-ase test error", aseErr.Message)
+	}
+
+	if aseErr.ProcName != procName {
+		t.Errorf("Expected procedure name %q, received %q", procName, aseErr.ProcName)
+	}
+
+	if aseErr.LineNr != 5 {
+		t.Errorf("Expected line number 5, received %d", aseErr.LineNr)
+	}
+
+	if aseErr.ServerName == "" {
+		t.Errorf("Expected server name to be set")
+	}
+
+	var eedError *tds.EEDError
+	if !errors.As(err, &eedError) {
+		t.Errorf("Expected *Error to wrap *tds.EEDError")
+	}
+}
+
+func TestErrorCommandFailed(t *testing.T) {
+	integration.TestForEachDB("TestErrorCommandFailed", t, testErrorCommandFailed)
+}
+
+func testErrorCommandFailed(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int primary key)", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// The failing insert is only read after the first result set.
+	batch := fmt.Sprintf("select 1 insert into %s values (1) insert into %s values (1) select 2", tableName, tableName)
+	err = conn.Raw(func(driverConn interface{}) error {
+		driverRows, _, err := driverConn.(*Conn).DirectExec(context.Background(), batch)
+		if err != nil {
+			return err
+		}
+		rows := driverRows.(*Rows)
+		defer rows.Close()
+
+		for {
+			if _, err := rows.ReadAll(); err != nil {
+				return err
+			}
+
+			if err := rows.NextResultSet(); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+		}
+	})
+
+	if !errors.Is(err, ErrCommandFailed) {
+		t.Errorf("Expected ErrCommandFailed, received %v", err)
+		return
+	}
+
+	var aseErr *Error
+	if !errors.As(err, &aseErr) {
+		t.Errorf("Expected *Error, received %T: %v", err, err)
+		return
+	}
+
+	if aseErr.MsgNumber != 2601 {
+		t.Errorf("Expected message number 2601 for the duplicate key, received %d", aseErr.MsgNumber)
+	}
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// ConnEventType is the type of a ConnEvent.
+type ConnEventType int
+
+const (
+	// ConnEventConnected is emitted after a connection was established.
+	ConnEventConnected ConnEventType = iota
+	// ConnEventLoginFailed is emitted when establishing a connection
+	// failed.
+	ConnEventLoginFailed
+	// ConnEventDisconnected is emitted after a connection was closed.
+	ConnEventDisconnected
+	// ConnEventServerMessage is emitted for every message the server
+	// sends.
+	ConnEventServerMessage
+	// ConnEventFatalError is emitted for server messages with
+	// a severity terminating the connection.
+	ConnEventFatalError
+	// ConnEventEnvChange is emitted when the server reports a change
+	// of the session environment, e.g. the current database.
+	ConnEventEnvChange
+)
+
+func (typ ConnEventType) String() string {
+	switch typ {
+	case ConnEventConnected:
+		return "Connected"
+	case ConnEventLoginFailed:
+		return "LoginFailed"
+	case ConnEventDisconnected:
+		return "Disconnected"
+	case ConnEventServerMessage:
+		return "ServerMessage"
+	case ConnEventFatalError:
+		return "FatalError"
+	case ConnEventEnvChange:
+		return "EnvChange"
+	}
+	return fmt.Sprintf("ConnEventType(%d)", int(typ))
+}
+
+// MessageCategory classifies the server messages and environment
+// changes of events, allowing observers to filter them.
+type MessageCategory int
+
+const (
+	// MessageInfo are informational messages, e.g. of print or
+	// raiserror with a low severity.
+	MessageInfo MessageCategory = iota
+	// MessageError are messages with the severity of an error.
+	MessageError
+	// MessageDBContextChange reports a change of the current
+	// database, e.g. through `use`.
+	MessageDBContextChange
+	// MessageLanguageChange reports a change of the language.
+	MessageLanguageChange
+	// MessageCharsetChange reports a change of the character set.
+	MessageCharsetChange
+	// MessagePacketSizeChange reports a change of the packet size.
+	MessagePacketSizeChange
+)
+
+func (category MessageCategory) String() string {
+	switch category {
+	case MessageInfo:
+		return "Info"
+	case MessageError:
+		return "Error"
+	case MessageDBContextChange:
+		return "DBContextChange"
+	case MessageLanguageChange:
+		return "LanguageChange"
+	case MessageCharsetChange:
+		return "CharsetChange"
+	case MessagePacketSizeChange:
+		return "PacketSizeChange"
+	}
+	return fmt.Sprintf("MessageCategory(%d)", int(category))
+}
+
+// Numbers of the messages ASE sends with environment changes.
+const (
+	msgDBContextChange = 5701
+	msgLanguageChange  = 5703
+	msgCharsetChange   = 5704
+)
+
+// messageCategory returns the category of a server message.
+func messageCategory(eed tds.EEDPackage) MessageCategory {
+	switch eed.MsgNumber {
+	case msgDBContextChange:
+		return MessageDBContextChange
+	case msgLanguageChange:
+		return MessageLanguageChange
+	case msgCharsetChange:
+		return MessageCharsetChange
+	}
+
+	if eed.Class >= minErrorSeverity {
+		return MessageError
+	}
+	return MessageInfo
+}
+
+// envChangeCategory returns the category of an environment change.
+func envChangeCategory(typ tds.EnvChangeType) (MessageCategory, bool) {
+	switch typ {
+	case tds.TDS_ENV_DB:
+		return MessageDBContextChange, true
+	case tds.TDS_ENV_LANG:
+		return MessageLanguageChange, true
+	case tds.TDS_ENV_CHARSET:
+		return MessageCharsetChange, true
+	case tds.TDS_ENV_PACKSIZE:
+		return MessagePacketSizeChange, true
+	}
+	return 0, false
+}
+
+// fatalSeverity is the lowest severity of server messages which
+// terminate the connection.
+const fatalSeverity = 20
+
+// errFatalServerMessage is wrapped by the error of ConnEventFatalError
+// events.
+var errFatalServerMessage = errors.New("fatal server message")
+
+// connEventBufferSize is the number of events buffered for delivery.
+const connEventBufferSize = 64
+
+// ConnEvent is an event in the lifecycle of a connection.
+type ConnEvent struct {
+	Type ConnEventType
+	// Message is the server message of ConnEventServerMessage and
+	// ConnEventFatalError events.
+	Message *tds.EEDPackage
+	// Category classifies the server message or environment change
+	// of ConnEventServerMessage, ConnEventFatalError and
+	// ConnEventEnvChange events.
+	Category MessageCategory
+	// OldValue and NewValue are the previous and new value of the
+	// environment of ConnEventEnvChange events, e.g. the name of the
+	// database.
+	OldValue, NewValue string
+	// Err is the error of ConnEventLoginFailed and ConnEventFatalError
+	// events.
+	Err error
+}
+
+// eventDispatcher delivers ConnEvents to observers.
+//
+// Events are buffered and delivered in a separate goroutine to not block
+// the connection. If the buffer is full events are dropped.
+type eventDispatcher struct {
+	lock      *sync.RWMutex
+	observers []func(ConnEvent)
+	events    chan ConnEvent
+	dropped   uint64
+}
+
+func newEventDispatcher() *eventDispatcher {
+	d := &eventDispatcher{
+		lock:   &sync.RWMutex{},
+		events: make(chan ConnEvent, connEventBufferSize),
+	}
+
+	go d.run()
+
+	return d
+}
+
+func (d *eventDispatcher) run() {
+	for ev := range d.events {
+		d.lock.RLock()
+		observers := d.observers
+		d.lock.RUnlock()
+
+		for _, fn := range observers {
+			fn(ev)
+		}
+	}
+}
+
+func (d *eventDispatcher) addObserver(fn func(ConnEvent)) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.observers = append(d.observers, fn)
+}
+
+// emit queues an event for delivery. It does not block.
+// Emitting on a nil dispatcher is a no-op.
+func (d *eventDispatcher) emit(ev ConnEvent) {
+	if d == nil {
+		return
+	}
+
+	select {
+	case d.events <- ev:
+	default:
+		atomic.AddUint64(&d.dropped, 1)
+	}
+}
+
+// eedHook emits ConnEventServerMessage events and ConnEventFatalError
+// events for messages with a fatal severity.
+func (d *eventDispatcher) eedHook(eed tds.EEDPackage) {
+	category := messageCategory(eed)
+	d.emit(ConnEvent{Type: ConnEventServerMessage, Message: &eed, Category: category})
+
+	if eed.Class >= fatalSeverity {
+		eeds := []*tds.EEDPackage{&eed}
+		d.emit(ConnEvent{
+			Type:     ConnEventFatalError,
+			Message:  &eed,
+			Category: category,
+			Err: newError(&tds.EEDError{
+				EEDPackages:  eeds,
+				WrappedError: errFatalServerMessage,
+			}, eeds),
+		})
+	}
+}
+
+// envChangeHook emits ConnEventEnvChange events.
+func (d *eventDispatcher) envChangeHook(typ tds.EnvChangeType, oldValue, newValue string) {
+	category, ok := envChangeCategory(typ)
+	if !ok {
+		return
+	}
+
+	d.emit(ConnEvent{Type: ConnEventEnvChange, Category: category, OldValue: oldValue, NewValue: newValue})
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestEventDispatcher_eedHook(t *testing.T) {
+	cases := map[string]struct {
+		msgNumber      uint32
+		class          uint8
+		expect         []ConnEventType
+		expectCategory MessageCategory
+	}{
+		"info":          {0, 0, []ConnEventType{ConnEventServerMessage}, MessageInfo},
+		"error":         {0, 16, []ConnEventType{ConnEventServerMessage}, MessageError},
+		"fatal":         {0, 20, []ConnEventType{ConnEventServerMessage, ConnEventFatalError}, MessageError},
+		"database":      {msgDBContextChange, 10, []ConnEventType{ConnEventServerMessage}, MessageDBContextChange},
+		"language":      {msgLanguageChange, 10, []ConnEventType{ConnEventServerMessage}, MessageLanguageChange},
+		"character set": {msgCharsetChange, 10, []ConnEventType{ConnEventServerMessage}, MessageCharsetChange},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			d := newEventDispatcher()
+
+			recv := make(chan ConnEvent, connEventBufferSize)
+			d.addObserver(func(ev ConnEvent) { recv <- ev })
+
+			d.eedHook(tds.EEDPackage{MsgNumber: cas.msgNumber, Class: cas.class, Msg: "message"})
+
+			for _, expect := range cas.expect {
+				select {
+				case ev := <-recv:
+					if ev.Type != expect {
+						t.Errorf("Expected event %s, received %s", expect, ev.Type)
+					}
+					if ev.Message == nil || ev.Message.Msg != "message" {
+						t.Errorf("Expected event to carry the server message, received %v", ev.Message)
+					}
+					if ev.Category != cas.expectCategory {
+						t.Errorf("Expected category %s, received %s", cas.expectCategory, ev.Category)
+					}
+					if ev.Type == ConnEventFatalError {
+						var aseErr *Error
+						if !errors.As(ev.Err, &aseErr) || aseErr.Message != "message" {
+							t.Errorf("Expected event to carry an *Error, received %v", ev.Err)
+						}
+					}
+				case <-time.After(time.Second):
+					t.Errorf("Timed out waiting for event %s", expect)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestEventDispatcher_envChangeHook(t *testing.T) {
+	cases := map[string]struct {
+		typ            tds.EnvChangeType
+		expectCategory MessageCategory
+	}{
+		"database":      {tds.TDS_ENV_DB, MessageDBContextChange},
+		"language":      {tds.TDS_ENV_LANG, MessageLanguageChange},
+		"character set": {tds.TDS_ENV_CHARSET, MessageCharsetChange},
+		"packet size":   {tds.TDS_ENV_PACKSIZE, MessagePacketSizeChange},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			d := newEventDispatcher()
+
+			recv := make(chan ConnEvent, connEventBufferSize)
+			d.addObserver(func(ev ConnEvent) { recv <- ev })
+
+			d.envChangeHook(cas.typ, "old", "new")
+
+			select {
+			case ev := <-recv:
+				if ev.Type != ConnEventEnvChange {
+					t.Errorf("Expected event %s, received %s", ConnEventEnvChange, ev.Type)
+				}
+				if ev.Category != cas.expectCategory {
+					t.Errorf("Expected category %s, received %s", cas.expectCategory, ev.Category)
+				}
+				if ev.OldValue != "old" || ev.NewValue != "new" {
+					t.Errorf("Expected values old and new, received %q and %q", ev.OldValue, ev.NewValue)
+				}
+			case <-time.After(time.Second):
+				t.Errorf("Timed out waiting for event %s", ConnEventEnvChange)
+			}
+		})
+	}
+}
+
+func TestEventDispatcher_emitDrops(t *testing.T) {
+	d := newEventDispatcher()
+
+	block := make(chan struct{})
+	defer close(block)
+	d.addObserver(func(ev ConnEvent) { <-block })
+
+	// One event is taken by the blocked observer, the buffer takes
+	// connEventBufferSize events and the remaining events are dropped.
+	for i := 0; i < 2*connEventBufferSize; i++ {
+		d.emit(ConnEvent{Type: ConnEventConnected})
+	}
+
+	dropped := atomic.LoadUint64(&d.dropped)
+	if dropped < connEventBufferSize-1 || dropped > connEventBufferSize {
+		t.Errorf("Expected %d or %d dropped events, received %d", connEventBufferSize-1, connEventBufferSize, dropped)
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"math"
 
@@ -138,28 +137,25 @@ func rawProcess(driverConn interface{}) error {
 }
 
 func readTable(conn *ase.Conn) error {
-	stmt, err := conn.NewStmt(context.Background(), "", "select * from subtransaction_tab", true)
+	it, err := conn.Query(context.Background(), "select a, b from subtransaction_tab")
 	if err != nil {
-		return fmt.Errorf("error preparing statement: %w", err)
+		return fmt.Errorf("error querying table: %w", err)
 	}
-	defer stmt.Close()
+	defer it.Close()
 
-	rows, _, err := stmt.DirectExec(context.Background())
-	if err != nil {
-		return fmt.Errorf("error querying with prepared statement: %w", err)
-	}
-
-	values := []driver.Value{0, ""}
-	for {
-		if err := rows.Next(values); err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			return fmt.Errorf("error reading row: %w", err)
+	for it.Next() {
+		var a int
+		var b string
+		if err := it.Scan(&a, &b); err != nil {
+			return fmt.Errorf("error scanning row: %w", err)
 		}
 
-		fmt.Printf("a: %d\n", values[0])
-		fmt.Printf("b: %s\n", values[1])
+		fmt.Printf("a: %d\n", a)
+		fmt.Printf("b: %s\n", b)
+	}
+
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("error reading rows: %w", err)
 	}
 
 	return nil
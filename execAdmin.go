@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// ExecAdmin executes a long-running administrative command such as
+// `dump database` or `load database` and passes the messages the
+// server sends while the command is running to onProgress as they
+// arrive, e.g. the percentage of pages dumped reported by the backup
+// server:
+//
+//	err := conn.ExecAdmin(ctx, "dump database db to '/dumps/db.dmp'", func(msg *tds.EEDPackage) {
+//		log.Print(msg.Msg)
+//	})
+//
+// The driver imposes no timeout on the command, packet-read-timeout
+// only applies while a packet is being received. The command is
+// cancelled through an attention once ctx ends, after which the server
+// may still need some time to abort the operation.
+//
+// onProgress is called for all messages of the command, including
+// those of a failure, which are returned as *Error as well. It is
+// called from the goroutine receiving the response and must return
+// quickly, as reading the response is blocked in the meantime.
+// onProgress may be nil.
+func (c *Conn) ExecAdmin(ctx context.Context, query string, onProgress func(msg *tds.EEDPackage)) error {
+	c.progressLock.Lock()
+	c.progress = onProgress
+	c.progressLock.Unlock()
+
+	defer func() {
+		c.progressLock.Lock()
+		c.progress = nil
+		c.progressLock.Unlock()
+	}()
+
+	return c.execNoRows(ctx, query)
+}
+
+// progressEEDHook is registered as an EEDHook on the connection and
+// passes messages to the callback of ExecAdmin while it is running.
+func (c *Conn) progressEEDHook(eed tds.EEDPackage) {
+	c.progressLock.Lock()
+	progress := c.progress
+	c.progressLock.Unlock()
+
+	if progress != nil {
+		progress(&eed)
+	}
+}
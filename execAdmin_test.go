@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestConn_progressEEDHook(t *testing.T) {
+	c := &Conn{progressLock: &sync.Mutex{}}
+
+	// Messages outside of ExecAdmin are ignored.
+	c.progressEEDHook(tds.EEDPackage{Msg: "ignored"})
+
+	var received []string
+	c.progress = func(msg *tds.EEDPackage) {
+		received = append(received, msg.Msg)
+	}
+
+	c.progressEEDHook(tds.EEDPackage{Msg: "10% dumped"})
+	c.progressEEDHook(tds.EEDPackage{Msg: "20% dumped"})
+
+	if len(received) != 2 || received[0] != "10% dumped" || received[1] != "20% dumped" {
+		t.Errorf("Unexpected messages: %v", received)
+	}
+}
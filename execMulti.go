@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// ExecMulti executes a batch of multiple statements, e.g. a migration
+// script, and returns a result for each statement reporting a count of
+// affected rows.
+//
+// database/sql only reports the count of the last statement of
+// a batch. ExecMulti records the count of every TDS_DONE sent by the
+// server instead. Statements not reporting a count, such as DDL, have no
+// result. The rows of queries in the batch are discarded.
+//
+// If a statement fails the results of the previous statements are
+// returned with the error.
+func (c *Conn) ExecMulti(ctx context.Context, query string, args ...interface{}) ([]driver.Result, error) {
+	var results []driver.Result
+
+	c.doneHook = func(done *tds.DonePackage) {
+		// TDS_DONE_PROC marks the done of the procedure created for
+		// batches with arguments, which is not a statement of the
+		// batch.
+		if count, ok := affectedCount(done); ok {
+			results = append(results, &Result{rowsAffected: count})
+		}
+	}
+	defer func() { c.doneHook = nil }()
+
+	rows, _, err := c.DirectExec(ctx, query, args...)
+	if err != nil {
+		return results, err
+	}
+
+	// The result sets are consumed explicitly as the closemode cancel
+	// would abort the remaining statements.
+	driverRows := rows.(*Rows)
+	for {
+		if err := driverRows.NextResultSet(); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			driverRows.Close()
+			return results, fmt.Errorf("go-ase: error consuming results: %w", err)
+		}
+	}
+
+	if err := driverRows.Close(); err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
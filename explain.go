@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// ExplainQuery returns the query plan ASE chooses for query.
+//
+// The query is compiled with `set showplan on` and `set noexec on`,
+// hence it is not executed. The plan is sent by the server as
+// messages, which are returned joined by newlines.
+func (c *Conn) ExplainQuery(ctx context.Context, query string) (string, error) {
+	if err := c.execNoRows(ctx, "set showplan on"); err != nil {
+		return "", err
+	}
+
+	if err := c.execNoRows(ctx, "set noexec on"); err != nil {
+		c.execNoRows(ctx, "set showplan off")
+		return "", err
+	}
+
+	c.planLock.Lock()
+	c.plan = &strings.Builder{}
+	c.planLock.Unlock()
+
+	explainErr := c.execNoRows(ctx, query)
+
+	c.planLock.Lock()
+	plan := c.plan.String()
+	c.plan = nil
+	c.planLock.Unlock()
+
+	// noexec must be disabled first, as the following commands
+	// would not be executed otherwise.
+	if err := c.execNoRows(ctx, "set noexec off"); err != nil {
+		return "", err
+	}
+
+	if err := c.execNoRows(ctx, "set showplan off"); err != nil {
+		return "", err
+	}
+
+	if explainErr != nil {
+		return "", explainErr
+	}
+
+	return plan, nil
+}
+
+// execNoRows executes a language command and discards its result sets.
+func (c *Conn) execNoRows(ctx context.Context, query string) error {
+	rows, _, err := c.language(ctx, query)
+	if err != nil {
+		return fmt.Errorf("go-ase: error executing %q: %w", query, err)
+	}
+
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("go-ase: error closing rows of %q: %w", query, err)
+	}
+
+	return nil
+}
+
+// planEEDHook is registered as an EEDHook on the connection and
+// records the messages of the query plan while ExplainQuery is
+// running.
+func (c *Conn) planEEDHook(eed tds.EEDPackage) {
+	c.planLock.Lock()
+	defer c.planLock.Unlock()
+
+	if c.plan == nil || eed.Class >= minErrorSeverity {
+		return
+	}
+
+	c.plan.WriteString(eed.Msg)
+	if !strings.HasSuffix(eed.Msg, "\n") {
+		c.plan.WriteString("\n")
+	}
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestConn_planEEDHook(t *testing.T) {
+	c := &Conn{planLock: &sync.Mutex{}}
+
+	// Messages outside of ExplainQuery are ignored.
+	c.planEEDHook(tds.EEDPackage{Class: 10, Msg: "ignored"})
+
+	c.plan = &strings.Builder{}
+	c.planEEDHook(tds.EEDPackage{Class: 10, Msg: "QUERY PLAN FOR STATEMENT 1 (at line 1).\n"})
+	c.planEEDHook(tds.EEDPackage{Class: 10, Msg: "    STEP 1"})
+	c.planEEDHook(tds.EEDPackage{Class: 16, Msg: "error"})
+
+	expect := "QUERY PLAN FOR STATEMENT 1 (at line 1).\n    STEP 1\n"
+	if plan := c.plan.String(); plan != expect {
+		t.Errorf("Expected plan %q, received %q", expect, plan)
+	}
+}
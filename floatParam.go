@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+// Real overrides the floating point type of a parameter to real, the
+// 4-byte floating point type of ASE:
+//
+//	db.Exec("insert into measurements values (?)", ase.Real(x))
+//
+// Parameters of RPCs are bound as real instead of float. For prepared
+// statements the parameter type is reported by the server; the value
+// is rounded to the precision of real before it is bound.
+type Real float32
+
+// Float overrides the floating point type of a parameter to float, the
+// 8-byte floating point type of ASE.
+//
+// Parameters of RPCs are bound as float instead of real, e.g. for
+// a float32 value passed to a float parameter.
+type Float float64
+
+// unwrapFloat returns the value of a Real or Float as float32
+// respectively float64. Other values are returned as-is.
+func unwrapFloat(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case Real:
+		return float32(typed)
+	case Float:
+		return float64(typed)
+	default:
+		return value
+	}
+}
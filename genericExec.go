@@ -36,7 +36,35 @@ func (c *Conn) DirectExec(ctx context.Context, query string, args ...interface{}
 
 // GenericExec is the central method through which SQL statements are
 // sent to ASE.
+//
+// Statements failing with a retryable error are executed again, see
+// Connector.SetRetryableErrors.
 func (c *Conn) GenericExec(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, driver.Result, error) {
+	if err := c.checkClosed(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.propagateTraceID(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return c.withRetry(ctx, func() (driver.Rows, driver.Result, error) {
+		return c.genericExec(ctx, query, args)
+	})
+}
+
+// genericExec executes query once.
+func (c *Conn) genericExec(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, driver.Result, error) {
+	if c.Info.RPC {
+		if proc, params, ok := parseProcCall(query, args); ok {
+			rows, result, err := c.SendRPC(ctx, proc, params)
+			if err != nil {
+				return nil, nil, err
+			}
+			return rows, result, nil
+		}
+	}
+
 	if len(args) == 0 {
 		rows, result, err := c.language(ctx, query)
 		if err != nil && !errors.Is(err, io.EOF) {
@@ -45,34 +73,64 @@ func (c *Conn) GenericExec(ctx context.Context, query string, args []driver.Name
 		return rows, result, nil
 	}
 
-	stmt, err := c.NewStmt(ctx, "", query, true)
+	stmt, err := c.newAdHocStmt(ctx, query, hasNamedArgs(args))
 	if err != nil {
 		return nil, nil, fmt.Errorf("go-ase: error creating prepared statement: %w", err)
 	}
 
-	rows, result, err := stmt.GenericExec(ctx, args)
+	// The statement is only used for this command, it is deallocated
+	// once its rows are closed.
+	rows, result, err := stmt.genericExec(ctx, args)
 	if err != nil {
+		if closeErr := stmt.close(ctx); closeErr != nil {
+			err = fmt.Errorf("%w (%v)", err, closeErr)
+		}
 		return nil, nil, fmt.Errorf("go-ase: error executing dynamic SQL: %w", err)
 	}
+	if typed, ok := rows.(*Rows); ok {
+		typed.adHocStmt = stmt
+	}
 
 	return rows, result, nil
 }
 
+// newAdHocStmt prepares query to be executed once with arguments.
+// Named placeholders are only replaced if named is set.
+func (c *Conn) newAdHocStmt(ctx context.Context, query string, named bool) (*Stmt, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	return c.newStmt(ctx, "", query, c.createProc(), named)
+}
+
 func (c *Conn) genericResults(ctx context.Context) (driver.Rows, driver.Result, error) {
-	rows := &Rows{Conn: c}
 	result := &Result{}
+	rows := &Rows{Conn: c, ctx: ctx, stats: c.currentStats(), result: result, maxRows: c.maxRows(ctx)}
+
+	c.startReceiving()
 
-	_, err := c.Channel.NextPackageUntil(ctx, true,
+	pkg, err := c.nextPackageUntil(ctx, true,
 		func(pkg tds.Package) (bool, error) {
 			switch typed := pkg.(type) {
 			case *tds.RowFmtPackage:
 				rows.RowFmt = typed
 				return true, nil
 			case *tds.DonePackage:
-				if typed.Status&tds.TDS_DONE_COUNT == tds.TDS_DONE_COUNT {
-					result.rowsAffected = int64(typed.Count)
+				if typed.Status&tds.TDS_DONE_ATTN == tds.TDS_DONE_ATTN {
+					return true, nil
 				}
 
+				// The done ending a procedure, e.g. the procedure
+				// created for dynamic SQL, must not replace the
+				// count of the last statement, such as the rows
+				// inserted by select into.
+				if count, ok := affectedCount(typed); ok {
+					result.rowsAffected = count
+				}
+				rows.addAffectedCount(typed)
+
 				ok, err := handleDonePackage(typed)
 				if err != nil {
 					return true, fmt.Errorf("go-ase: %w", err)
@@ -80,18 +138,43 @@ func (c *Conn) genericResults(ctx context.Context) (driver.Rows, driver.Result,
 
 				return ok, nil
 			case *tds.ReturnStatusPackage:
-				if typed.ReturnValue != 0 {
-					return true, fmt.Errorf("go-ase: query failed with return status %d", typed.ReturnValue)
+				if err := rows.addReturnStatus(typed); err != nil {
+					return true, err
 				}
 				return false, nil
+			case *tds.ParamFmtPackage:
+				return false, nil
+			case *tds.ParamsPackage:
+				if err := result.addOutputParams(c.Info, typed); err != nil {
+					return true, err
+				}
+				return false, nil
+			case *tds.TokenlessPackage:
+				if isComputePackage(typed) {
+					return true, c.abortCompute()
+				}
+				return false, c.unhandledPackage(typed)
 			default:
-				return false, fmt.Errorf("go-ase: unhandled package type %T", typed)
+				return false, c.unhandledPackage(typed)
 			}
 		},
 	)
 	if err != nil && !errors.Is(err, io.EOF) {
-		return nil, nil, err
+		return nil, nil, c.abortOnContext(ctx, err)
 	}
 
+	if c.attentionAcknowledged(pkg) {
+		return nil, nil, ErrCancelled
+	}
+
+	// Without a RowFmtPackage the communication was consumed until
+	// the final DonePackage.
+	rows.finished = rows.RowFmt == nil
+
+	// With nocount no count is reported, which must not be mistaken
+	// for zero affected rows.
+	result.countUnavailable = rows.finished && len(rows.affectedCounts) == 0 && c.NoCount()
+	c.activeRows = rows
+
 	return rows, result, nil
 }
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// healthcheckQuery is the trivial query sent by Healthcheck.
+const healthcheckQuery = "select 'ping'"
+
+// HealthcheckError is returned by Healthcheck if the server responded
+// with an error. The server is reachable but cannot process commands.
+type HealthcheckError struct {
+	// Latency is the round-trip time of the failed command.
+	Latency time.Duration
+
+	err error
+}
+
+// Error implements the error interface.
+func (e *HealthcheckError) Error() string {
+	return fmt.Sprintf("go-ase: server responded with an error after %v: %v", e.Latency, e.err)
+}
+
+// Unwrap returns the error reported by the server, which can be
+// retrieved as *Error with errors.As.
+func (e *HealthcheckError) Unwrap() error {
+	return e.err
+}
+
+// Healthcheck sends a trivial query and returns the round-trip time,
+// e.g. for readiness probes.
+//
+// If the server responds with an error a *HealthcheckError with the
+// latency is returned. All other errors, e.g. if the context is
+// cancelled or the connection is broken, are returned with a latency
+// of zero.
+func (c *Conn) Healthcheck(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	err := c.execNoRows(ctx, healthcheckQuery)
+	latency := time.Since(start)
+
+	if err != nil {
+		var aseErr *Error
+		if errors.As(err, &aseErr) {
+			return latency, &HealthcheckError{Latency: latency, err: err}
+		}
+		return 0, fmt.Errorf("go-ase: healthcheck failed: %w", err)
+	}
+
+	return latency, nil
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthcheckError(t *testing.T) {
+	aseErr := &Error{MsgNumber: 208, Message: "tab not found."}
+
+	var err error = &HealthcheckError{Latency: time.Millisecond, err: aseErr}
+
+	var unwrapped *Error
+	if !errors.As(err, &unwrapped) {
+		t.Errorf("Expected *HealthcheckError to unwrap to *Error")
+		return
+	}
+
+	if unwrapped.MsgNumber != 208 {
+		t.Errorf("Expected message number 208, received %d", unwrapped.MsgNumber)
+	}
+}
@@ -10,17 +10,34 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync/atomic"
 
 	"github.com/SAP/go-dblib/tds"
 )
 
+// ErrCommandFailed is returned when the server marks a command as
+// failed in the status of a done token (TDS_DONE_ERROR).
+//
+// If the server sent error messages with the command the error is
+// returned as *Error, which still matches ErrCommandFailed with
+// errors.Is. The server does not always send a message, e.g. for some
+// failures in nested procedures.
+var ErrCommandFailed = errors.New("command failed")
+
+// handleDonePackage reports if pkg ends the communication of
+// a command.
+//
+// The flags TDS_DONE_COUNT, TDS_DONE_EVENT and TDS_DONE_CUMULATIVE only
+// carry additional information and are ignored when determining if the
+// package is final.
 func handleDonePackage(pkg *tds.DonePackage) (bool, error) {
-	if pkg.Status == tds.TDS_DONE_COUNT {
+	status := pkg.Status &^ (tds.TDS_DONE_COUNT | tds.TDS_DONE_EVENT | tds.TDS_DONE_CUMULATIVE)
+	if status == tds.TDS_DONE_FINAL {
 		return true, io.EOF
 	}
 
 	if pkg.Status&tds.TDS_DONE_ERROR == tds.TDS_DONE_ERROR {
-		return true, fmt.Errorf("query failed with errors")
+		return true, fmt.Errorf("%w: done status %#x", ErrCommandFailed, uint16(pkg.Status))
 	}
 
 	if pkg.Status&tds.TDS_DONE_MORE == tds.TDS_DONE_MORE ||
@@ -29,18 +46,112 @@ func handleDonePackage(pkg *tds.DonePackage) (bool, error) {
 		return false, nil
 	}
 
-	if pkg.Status == tds.TDS_DONE_FINAL {
-		return true, io.EOF
+	return false, fmt.Errorf("%T with unrecognized Status: %s", pkg, pkg)
+}
+
+// affectedCount returns the count of affected rows reported by done.
+//
+// Dones without a count, acknowledging an attention or ending
+// a procedure (TDS_DONE_PROC) do not report a statement's count.
+func affectedCount(done *tds.DonePackage) (int64, bool) {
+	if done.Status&tds.TDS_DONE_COUNT == 0 || done.Status&(tds.TDS_DONE_ATTN|tds.TDS_DONE_PROC) != 0 {
+		return 0, false
 	}
+	return int64(done.Count), true
+}
 
-	return false, fmt.Errorf("%T with unrecognized Status: %s", pkg, pkg)
+// returnStatusError returns an error for return statuses other than
+// zero if the property returnstatuserror is set.
+func returnStatusError(info *Info, status *tds.ReturnStatusPackage) error {
+	if !info.ReturnStatusError || status.ReturnValue == 0 {
+		return nil
+	}
+	return fmt.Errorf("go-ase: query failed with return status %d", status.ReturnValue)
+}
+
+// nextPackageUntil wraps tds.Channel.NextPackageUntil, tracks the
+// transaction state reported in DonePackages and returns errors with
+// messages from the server as *Error and network errors as connError.
+func (c *Conn) nextPackageUntil(ctx context.Context, wait bool, processPkg func(tds.Package) (bool, error)) (tds.Package, error) {
+	pkg, err := c.channel.NextPackageUntil(ctx, wait, func(pkg tds.Package) (bool, error) {
+		c.packages.add(false, pkg)
+
+		done, ok := pkg.(*tds.DonePackage)
+		if !ok {
+			return processPkg(pkg)
+		}
+
+		c.trackTransaction(done)
+		if c.doneHook != nil {
+			c.doneHook(done)
+		}
+
+		// The messages of a statement are sent before its
+		// DonePackage.
+		defer c.takeMessages()
+		return processPkg(pkg)
+	})
+	if err != nil {
+		var eedError *tds.EEDError
+		if errors.As(err, &eedError) && len(eedError.EEDPackages) > 0 {
+			aseErr := newError(err, eedError.EEDPackages)
+			if aseErr.isDeadlockVictim() {
+				c.abortTransaction()
+			}
+			return pkg, aseErr
+		}
+	}
+
+	return pkg, c.checkConnError(err)
+}
+
+// isComputePackage reports if pkg is the format, name or row of
+// a compute clause.
+//
+// Unlike the format of regular rows (TDS_ROWFMT) compute formats are
+// transmitted as TDS_ALTFMT, which go-dblib does not parse and returns
+// as a TokenlessPackage starting with the token.
+func isComputePackage(pkg *tds.TokenlessPackage) bool {
+	if pkg.Data == nil || pkg.Data.Len() == 0 {
+		return false
+	}
+
+	switch tds.Token(pkg.Data.Bytes()[0]) {
+	case tds.TDS_ALTFMT, tds.TDS_ALTNAME, tds.TDS_ALTROW, tds.TDS_ALTCONTROL:
+		return true
+	}
+
+	return false
+}
+
+// abortCompute aborts the current command after a compute package was
+// received and marks the connection as broken.
+//
+// go-dblib reads a token it does not parse together with all data
+// received after it into a single TokenlessPackage, hence the
+// remaining packages of the command cannot be consumed reliably. The
+// command is aborted with an attention to stop the server from sending
+// further results and the connection is discarded by database/sql.
+// The attention is sent like by Cancel, which may be called
+// concurrently.
+func (c *Conn) abortCompute() error {
+	atomic.StoreInt32(&c.broken, 1)
+
+	c.cancelLock.Lock()
+	defer c.cancelLock.Unlock()
+
+	if err := c.signalAttention(); err != nil {
+		return fmt.Errorf("%w (%v)", ErrComputeUnsupported, err)
+	}
+
+	return ErrComputeUnsupported
 }
 
 // finalize consumes all remaining packages in a communication using
 // handleDonePackage.
 // If any other package is received an error is returned.
-func finalize(ctx context.Context, channel *tds.Channel) error {
-	_, err := channel.NextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
+func (c *Conn) finalize(ctx context.Context) error {
+	_, err := c.nextPackageUntil(ctx, true, func(pkg tds.Package) (bool, error) {
 		switch typed := pkg.(type) {
 		case *tds.DonePackage:
 			ok, err := handleDonePackage(typed)
@@ -49,7 +160,7 @@ func finalize(ctx context.Context, channel *tds.Channel) error {
 			}
 			return ok, nil
 		default:
-			return true, fmt.Errorf("go-ase: unhandled package type %T: %v", typed, typed)
+			return true, c.unhandledPackage(typed)
 		}
 	})
 	if err != nil && !errors.Is(err, io.EOF) {
@@ -58,3 +169,29 @@ func finalize(ctx context.Context, channel *tds.Channel) error {
 
 	return nil
 }
+
+// Usertypes of the system-defined domain types, which are transmitted
+// as varchar.
+const (
+	userTypeSysname     = 18
+	userTypeLongSysname = 42
+)
+
+// databaseTypeName returns the database type name for a field format.
+//
+// ASE transmits some types as one of the basic data types and only
+// marks them through the usertype, e.g. timestamp as varbinary or
+// sysname as varchar. These are reported with their ASE name, their
+// values are decoded as the basic data type.
+func databaseTypeName(fieldFmt tds.FieldFmt) string {
+	switch fieldFmt.UserType() {
+	case userTypeTimestamp:
+		return "TIMESTAMP"
+	case userTypeSysname:
+		return "SYSNAME"
+	case userTypeLongSysname:
+		return "LONGSYSNAME"
+	}
+
+	return fieldFmt.DataType().String()
+}
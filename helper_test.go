@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestIsComputePackage(t *testing.T) {
+	cases := map[string]struct {
+		data   []byte
+		expect bool
+	}{
+		"empty":   {nil, false},
+		"altfmt":  {[]byte{byte(tds.TDS_ALTFMT), 0x01, 0x00}, true},
+		"altname": {[]byte{byte(tds.TDS_ALTNAME), 0x01, 0x00}, true},
+		"altrow":  {[]byte{byte(tds.TDS_ALTROW), 0x01, 0x00}, true},
+		"rowfmt":  {[]byte{byte(tds.TDS_ROWFMT), 0x01, 0x00}, false},
+		"row":     {[]byte{byte(tds.TDS_ROW), 0x01}, false},
+		"unknown": {[]byte{0x00}, false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			pkg := tds.NewTokenlessPackage()
+			pkg.Data.Write(cas.data)
+
+			if recv := isComputePackage(pkg); recv != cas.expect {
+				t.Errorf("Expected %t, received %t", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestDatabaseTypeName(t *testing.T) {
+	cases := map[string]struct {
+		dataType asetypes.DataType
+		userType int32
+		expect   string
+	}{
+		"varchar":     {asetypes.VARCHAR, 2, "VARCHAR"},
+		"timestamp":   {asetypes.VARBINARY, userTypeTimestamp, "TIMESTAMP"},
+		"sysname":     {asetypes.VARCHAR, userTypeSysname, "SYSNAME"},
+		"longsysname": {asetypes.VARCHAR, userTypeLongSysname, "LONGSYSNAME"},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := tds.LookupFieldFmt(cas.dataType)
+			if err != nil {
+				t.Errorf("Error looking up field format: %v", err)
+				return
+			}
+			fieldFmt.SetUserType(cas.userType)
+
+			if name := databaseTypeName(fieldFmt); name != cas.expect {
+				t.Errorf("Expected %q, received %q", cas.expect, name)
+			}
+		})
+	}
+}
+
+func TestHandleDonePackage(t *testing.T) {
+	cases := map[string]struct {
+		status    tds.DoneState
+		expectOk  bool
+		expectEOF bool
+		expectErr bool
+	}{
+		"final":            {tds.TDS_DONE_FINAL, true, true, false},
+		"count":            {tds.TDS_DONE_COUNT, true, true, false},
+		"count cumulative": {tds.TDS_DONE_COUNT | tds.TDS_DONE_CUMULATIVE, true, true, false},
+		"event":            {tds.TDS_DONE_EVENT, true, true, false},
+		"more":             {tds.TDS_DONE_MORE | tds.TDS_DONE_COUNT, false, false, false},
+		"proc":             {tds.TDS_DONE_PROC | tds.TDS_DONE_COUNT, false, false, false},
+		"inxact":           {tds.TDS_DONE_INXACT, false, false, false},
+		"error":            {tds.TDS_DONE_ERROR | tds.TDS_DONE_COUNT, true, false, true},
+		"error final":      {tds.TDS_DONE_ERROR, true, false, true},
+		"error more":       {tds.TDS_DONE_ERROR | tds.TDS_DONE_MORE, true, false, true},
+		"error proc":       {tds.TDS_DONE_ERROR | tds.TDS_DONE_PROC, true, false, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			ok, err := handleDonePackage(&tds.DonePackage{Status: cas.status})
+			if ok != cas.expectOk {
+				t.Errorf("Expected ok %t, received %t", cas.expectOk, ok)
+			}
+
+			if errors.Is(err, io.EOF) != cas.expectEOF {
+				t.Errorf("Expected io.EOF %t, received %v", cas.expectEOF, err)
+			}
+
+			if (err != nil && !errors.Is(err, io.EOF)) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+			}
+
+			if errors.Is(err, ErrCommandFailed) != cas.expectErr {
+				t.Errorf("Expected ErrCommandFailed %t, received %v", cas.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestAffectedCount(t *testing.T) {
+	cases := map[string]struct {
+		done        tds.DonePackage
+		expectCount int64
+		expectOk    bool
+	}{
+		"count":          {tds.DonePackage{Status: tds.TDS_DONE_COUNT | tds.TDS_DONE_MORE, Count: 3}, 3, true},
+		"zero count":     {tds.DonePackage{Status: tds.TDS_DONE_COUNT}, 0, true},
+		"no count":       {tds.DonePackage{Status: tds.TDS_DONE_MORE, Count: 3}, 0, false},
+		"attention":      {tds.DonePackage{Status: tds.TDS_DONE_COUNT | tds.TDS_DONE_ATTN, Count: 3}, 0, false},
+		"procedure done": {tds.DonePackage{Status: tds.TDS_DONE_COUNT | tds.TDS_DONE_PROC, Count: 3}, 0, false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			count, ok := affectedCount(&cas.done)
+			if ok != cas.expectOk || count != cas.expectCount {
+				t.Errorf("Expected %d, %t, received %d, %t", cas.expectCount, cas.expectOk, count, ok)
+			}
+		})
+	}
+}
+
+func TestReturnStatusError(t *testing.T) {
+	cases := map[string]struct {
+		status            int32
+		returnStatusError bool
+		expectErr         bool
+	}{
+		"zero":              {0, false, false},
+		"nonzero":           {-6, false, false},
+		"zero as error":     {0, true, false},
+		"nonzero as error":  {-6, true, true},
+		"positive as error": {1, true, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			info := &Info{ReturnStatusError: cas.returnStatusError}
+
+			err := returnStatusError(info, &tds.ReturnStatusPackage{ReturnValue: cas.status})
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+			}
+		})
+	}
+}
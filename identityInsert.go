@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithIdentityInsert enables identity_insert for the table tableName,
+// allowing to insert explicit values into its identity column, and
+// calls fn. identity_insert is disabled again after fn returned, even
+// if fn returned an error or panicked.
+//
+// fn must close all rows it opened on the connection, otherwise
+// identity_insert cannot be disabled.
+//
+// The table name is used as-is and must be quoted by the caller if
+// required, see QuoteIdentifier.
+func (c *Conn) WithIdentityInsert(ctx context.Context, tableName string, fn func() error) (err error) {
+	if err := c.execNoRows(ctx, fmt.Sprintf("set identity_insert %s on", tableName)); err != nil {
+		return fmt.Errorf("go-ase: error enabling identity_insert for %s: %w", tableName, err)
+	}
+
+	defer func() {
+		// identity_insert is disabled even if ctx is already done.
+		offErr := c.execNoRows(context.Background(), fmt.Sprintf("set identity_insert %s off", tableName))
+		if offErr != nil && err == nil {
+			err = fmt.Errorf("go-ase: error disabling identity_insert for %s: %w", tableName, offErr)
+		}
+	}()
+
+	return fn()
+}
@@ -19,7 +19,69 @@ type Info struct {
 
 	NoQueryCursor bool `json:"no-query-cursor" doc:"Prevents the use of cursors for database/sql query methods. See README for details."`
 
+	NoDynamicProc bool `json:"no-dynamic-proc" doc:"Prepare statements as plain dynamic SQL instead of lightweight procedures. See README for details."`
+
 	CursorCacheRows int `json:"cursor-cache-rows" doc:"How many rows to cache at once when reading the result set of a cursor"`
+
+	CloseMode string `json:"closemode" doc:"How unread result sets are handled when closing rows, either 'drain' or 'cancel'"`
+
+	NumericAsString bool `json:"numericasstring" doc:"Return numeric, decimal and money values as strings"`
+
+	TrimChar bool `json:"trimchar" doc:"Trim trailing spaces from values of char and nchar columns"`
+
+	RPC bool `json:"rpc" doc:"Invoke stored procedures called with 'exec proc' through RPCs"`
+
+	Uint64AsString bool `json:"uint64asstring" doc:"Return unsigned bigint values as decimal strings"`
+
+	AllNumericFloat bool `json:"allnumericfloat" doc:"Return values of all integer, floating point, numeric, decimal and money columns as float64. See README for details."`
+
+	ExactDateTime bool `json:"exactdatetime" doc:"Return datetime values with the exact 1/300 second fraction instead of milliseconds"`
+
+	Language string `json:"language" doc:"Language of server messages, sent at login"`
+
+	DateFormat string `json:"dateformat" doc:"Order of date parts for string-to-date conversions, e.g. 'dmy'"`
+
+	Chained bool `json:"chained" doc:"Enable chained transaction mode, in which statements implicitly begin transactions"`
+
+	ArithAbort string `json:"arithabort" doc:"Abort the batch on arithmetic overflows and divisions by zero, either 'on' or 'off'"`
+
+	ArithIgnore string `json:"arithignore" doc:"Suppress warnings on arithmetic overflows and divisions by zero, either 'on' or 'off'"`
+
+	MaxRows int `json:"maxrows" doc:"Fail with ErrRowLimitExceeded once a result set returns more than this number of rows, 0 disables the limit"`
+
+	LastInsertId bool `json:"lastinsertid" doc:"Retrieve @@identity after single-row inserts for Result.LastInsertId"`
+
+	EmptyStringAsNull bool `json:"emptystringasnull" doc:"Bind empty string arguments for nullable parameters as NULL. See README for details."`
+
+	TraceID bool `json:"traceid" doc:"Send trace IDs set with WithTraceID to the server as clientapplname"`
+
+	ReturnStatusError bool `json:"returnstatuserror" doc:"Fail commands with an error if a procedure returns a status other than zero"`
+
+	AllowTruncation bool `json:"allowtruncation" doc:"Truncate character and binary arguments exceeding the length of their parameter instead of failing with ErrParamTruncated"`
+
+	DebugPackages int `json:"debugpackages" doc:"Number of the last sent and received TDS packages to retain for diagnosing protocol errors, at most 1000, 0 disables the retention"`
+}
+
+// Recognized values for Info.CloseMode.
+const (
+	// CloseModeDrain reads and discards all remaining result sets when
+	// closing rows.
+	CloseModeDrain = "drain"
+	// CloseModeCancel sends an attention to abort the remaining result
+	// sets when closing rows.
+	CloseModeCancel = "cancel"
+)
+
+// checkCloseMode returns an error if mode is not a recognized value
+// for Info.CloseMode. An empty mode, e.g. of an Info not created by
+// NewInfo, is handled as CloseModeDrain.
+func checkCloseMode(mode string) error {
+	switch mode {
+	case "", CloseModeDrain, CloseModeCancel:
+		return nil
+	default:
+		return fmt.Errorf("go-ase: invalid closemode %q, expected %q or %q", mode, CloseModeDrain, CloseModeCancel)
+	}
 }
 
 // NewInfo returns a bare Info for github.com/SAP/go-dblib/dsn with defaults.
@@ -34,6 +96,8 @@ func NewInfo() (*Info, error) {
 
 	info.CursorCacheRows = 1000
 
+	info.CloseMode = CloseModeDrain
+
 	return info, nil
 }
 
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import "testing"
+
+func TestCheckCloseMode(t *testing.T) {
+	cases := map[string]struct {
+		mode string
+		err  bool
+	}{
+		"empty":   {"", false},
+		"drain":   {CloseModeDrain, false},
+		"cancel":  {CloseModeCancel, false},
+		"invalid": {"discard", true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			err := checkCloseMode(cas.mode)
+			if cas.err && err == nil {
+				t.Errorf("Expected error for closemode %q", cas.mode)
+			}
+			if !cas.err && err != nil {
+				t.Errorf("Unexpected error for closemode %q: %v", cas.mode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// defaultCSVBatchSize is the number of rows inserted per transaction
+// if CSVOptions.BatchSize is not set.
+const defaultCSVBatchSize = 1000
+
+// CSVOptions configures InsertCSV.
+type CSVOptions struct {
+	// Delimiter separates the fields of a record. Defaults to ','.
+	Delimiter rune
+	// NullToken is the field value inserted as NULL, e.g. `\N`.
+	NullToken string
+	// EmptyAsNull inserts empty fields as NULL. Otherwise empty fields
+	// are inserted as empty strings, see the README.
+	EmptyAsNull bool
+	// Header signals that the first record contains the names of the
+	// target columns.
+	Header bool
+	// Columns are the names of the target columns of the fields.
+	// If set the header is skipped but not used for mapping.
+	// Without Columns and Header the fields are inserted by position.
+	Columns []string
+	// BatchSize is the number of rows inserted per transaction.
+	// Defaults to 1000.
+	BatchSize int
+}
+
+// InsertCSV reads CSV records from r and inserts them into the table
+// tableName. It returns the number of inserted rows.
+//
+// The bulk-copy protocol is not implemented - the records are inserted
+// one by one through a prepared statement, which requires a round trip
+// per record. The rows are committed every opts.BatchSize rows. If an
+// error occurs the current batch is rolled back and the number of rows
+// committed in previous batches is returned with the error.
+//
+// The fields are converted to the types of the target columns as
+// reported by the server.
+//
+// The table name is used as-is and must be quoted by the caller if
+// required, see QuoteIdentifier.
+func (c *Conn) InsertCSV(ctx context.Context, tableName string, r io.Reader, opts CSVOptions) (int64, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultCSVBatchSize
+	}
+
+	columns := opts.Columns
+	if opts.Header {
+		header, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, nil
+			}
+			return 0, fmt.Errorf("go-ase: error reading CSV header: %w", err)
+		}
+
+		if columns == nil {
+			columns = append([]string{}, header...)
+		}
+	}
+
+	record, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("go-ase: error reading CSV record 1: %w", err)
+	}
+
+	if columns != nil && len(columns) != len(record) {
+		return 0, fmt.Errorf("go-ase: CSV record 1 has %d fields, expected %d columns", len(record), len(columns))
+	}
+
+	stmt, err := c.NewStmt(ctx, "", csvInsertQuery(tableName, columns, len(record)), true)
+	if err != nil {
+		return 0, fmt.Errorf("go-ase: error preparing insert into %s: %w", tableName, err)
+	}
+	defer stmt.Close()
+
+	var inserted, batched int64
+	var tx *Transaction
+
+	rollback := func() {
+		if tx != nil {
+			tx.Rollback()
+		}
+	}
+
+	args := make([]driver.NamedValue, len(record))
+	for n := int64(1); ; n++ {
+		if tx == nil {
+			tx, err = c.NewTransaction(ctx, DefaultTxOptions(), "")
+			if err != nil {
+				return inserted, fmt.Errorf("go-ase: error starting transaction: %w", err)
+			}
+		}
+
+		for i, field := range record {
+			args[i] = driver.NamedValue{Ordinal: i + 1, Value: field}
+			if (opts.NullToken != "" && field == opts.NullToken) || (opts.EmptyAsNull && field == "") {
+				args[i].Value = nil
+			}
+		}
+
+		if _, err := stmt.ExecContext(ctx, args); err != nil {
+			rollback()
+			return inserted, fmt.Errorf("go-ase: error inserting CSV record %d: %w", n, err)
+		}
+		batched++
+
+		if batched == int64(batchSize) {
+			if err := tx.Commit(); err != nil {
+				return inserted, fmt.Errorf("go-ase: error committing CSV records: %w", err)
+			}
+			inserted += batched
+			batched = 0
+			tx = nil
+		}
+
+		record, err = reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			rollback()
+			return inserted, fmt.Errorf("go-ase: error reading CSV record %d: %w", n+1, err)
+		}
+	}
+
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return inserted, fmt.Errorf("go-ase: error committing CSV records: %w", err)
+		}
+		inserted += batched
+	}
+
+	return inserted, nil
+}
+
+// csvInsertQuery returns the statement inserting a record with the
+// passed number of fields.
+func csvInsertQuery(tableName string, columns []string, fields int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", fields), ", ")
+
+	if len(columns) == 0 {
+		return fmt.Sprintf("insert into %s values (%s)", tableName, placeholders)
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = QuoteIdentifier(column)
+	}
+
+	return fmt.Sprintf("insert into %s (%s) values (%s)", tableName, strings.Join(quoted, ", "), placeholders)
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestInsertCSV(t *testing.T) {
+	integration.TestForEachDB("TestInsertCSV", t, testInsertCSV)
+}
+
+func testInsertCSV(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (id int, name varchar(30) null, amount decimal(10,2) null, created datetime null)", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	csvData := strings.Join([]string{
+		"name;id;amount;created",
+		"first;1;1.50;2021-03-04 10:11:12",
+		`second;2;\N;2021-03-05`,
+		`third;3;-3.25;\N`,
+		";4;;",
+	}, "\n")
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		n, err := c.InsertCSV(context.Background(), tableName, strings.NewReader(csvData), CSVOptions{
+			Delimiter:   ';',
+			NullToken:   `\N`,
+			EmptyAsNull: true,
+			Header:      true,
+			BatchSize:   2,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting CSV: %w", err)
+		}
+
+		if n != 4 {
+			return fmt.Errorf("expected 4 inserted rows, received %d", n)
+		}
+
+		// Without EmptyAsNull empty fields are inserted as empty
+		// strings.
+		n, err = c.InsertCSV(context.Background(), tableName, strings.NewReader("5,"), CSVOptions{
+			Columns: []string{"id", "name"},
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting CSV with empty field: %w", err)
+		}
+
+		if n != 1 {
+			return fmt.Errorf("expected 1 inserted row, received %d", n)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select id, case when name is null then 'NULL' when name = '' then 'empty' else name end, convert(varchar(10), amount), convert(varchar(10), created, 23) from %s order by id", tableName))
+	if err != nil {
+		t.Errorf("Error selecting inserted rows: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	var recv []string
+	for rows.Next() {
+		var id int
+		var name, amount, created sql.NullString
+		if err := rows.Scan(&id, &name, &amount, &created); err != nil {
+			t.Errorf("Error scanning row: %v", err)
+			return
+		}
+		recv = append(recv, fmt.Sprintf("%d %s %s %s", id, name.String, amount.String, created.String))
+	}
+
+	expect := []string{
+		"1 first 1.50 2021-03-04",
+		"2 second  2021-03-05",
+		"3 third -3.25 ",
+		"4 NULL  ",
+		"5 empty  ",
+	}
+
+	if !reflect.DeepEqual(recv, expect) {
+		t.Errorf("Expected rows %q, received %q", expect, recv)
+	}
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+// InsertRows inserts the elements of rows, a slice of structs or of
+// pointers to structs, into the table tableName and returns the number
+// of inserted rows.
+//
+// Exported fields are inserted into the column named by their `db`
+// tag or, without a tag, by the field name. Fields tagged with
+// `db:"-"` and fields of identity columns are skipped, the values of
+// identity columns are generated by the server:
+//
+//	type user struct {
+//		ID   int64  `db:"id"`
+//		Name string `db:"name"`
+//	}
+//
+//	n, err := conn.InsertRows(ctx, "users", []user{{Name: "alice"}, {Name: "bob"}})
+//
+// The rows are inserted through a single prepared statement. If no
+// transaction is open the rows are inserted within a transaction,
+// which is rolled back if an insert fails. Within an open transaction
+// the number of rows inserted before the failing row is returned with
+// the error and the caller decides whether to roll back.
+//
+// The table name is used as-is and must be quoted by the caller if
+// required, see QuoteIdentifier. The column names are quoted.
+func (c *Conn) InsertRows(ctx context.Context, tableName string, rows interface{}) (int64, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return 0, fmt.Errorf("go-ase: rows must be a slice of structs, received %T", rows)
+	}
+
+	elemType := v.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("go-ase: rows must be a slice of structs, received %T", rows)
+	}
+
+	if v.Len() == 0 {
+		return 0, nil
+	}
+
+	tableColumns, err := c.TableColumns(ctx, tableName)
+	if err != nil {
+		return 0, err
+	}
+
+	identity := map[string]bool{}
+	for _, column := range tableColumns {
+		if column.Identity {
+			identity[column.Name] = true
+		}
+	}
+
+	fields, columns := insertFields(elemType, identity)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("go-ase: %s has no fields to insert into %s", elemType, tableName)
+	}
+
+	stmt, err := c.NewStmt(ctx, "", csvInsertQuery(tableName, columns, len(columns)), true)
+	if err != nil {
+		return 0, fmt.Errorf("go-ase: error preparing insert into %s: %w", tableName, err)
+	}
+	defer stmt.Close()
+
+	var tx *Transaction
+	if !c.InTransaction() {
+		tx, err = c.NewTransaction(ctx, DefaultTxOptions(), "")
+		if err != nil {
+			return 0, fmt.Errorf("go-ase: error starting transaction: %w", err)
+		}
+	}
+
+	inserted, err := insertElems(ctx, stmt, v, fields)
+	if err != nil {
+		if tx != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		return inserted, err
+	}
+
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("go-ase: error committing rows: %w", err)
+		}
+	}
+
+	return inserted, nil
+}
+
+// insertFields returns the indices of the fields of the struct type t
+// to insert and the names of their columns. Fields of the columns in
+// skip are omitted.
+func insertFields(t reflect.Type, skip map[string]bool) ([]int, []string) {
+	var fields []int
+	var columns []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Tag.Get("db")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if skip[name] {
+			continue
+		}
+
+		fields = append(fields, i)
+		columns = append(columns, name)
+	}
+
+	return fields, columns
+}
+
+// insertElems executes stmt with the fields of every element of v and
+// returns the number of inserted rows.
+func insertElems(ctx context.Context, stmt *Stmt, v reflect.Value, fields []int) (int64, error) {
+	var inserted int64
+
+	args := make([]driver.NamedValue, len(fields))
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				return inserted, fmt.Errorf("go-ase: row %d is nil", i+1)
+			}
+			elem = elem.Elem()
+		}
+
+		for j, index := range fields {
+			args[j] = driver.NamedValue{Ordinal: j + 1, Value: insertValue(elem.Field(index))}
+		}
+
+		result, err := stmt.ExecContext(ctx, args)
+		if err != nil {
+			return inserted, fmt.Errorf("go-ase: error inserting row %d: %w", i+1, err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return inserted, fmt.Errorf("go-ase: error retrieving affected rows of row %d: %w", i+1, err)
+		}
+		inserted += affected
+	}
+
+	return inserted, nil
+}
+
+// insertValue returns the value of a field to bind. Nil pointers are
+// bound as NULL and other pointers are dereferenced, unless they
+// implement driver.Valuer or are decimals.
+func insertValue(field reflect.Value) interface{} {
+	value := field.Interface()
+	if field.Kind() != reflect.Ptr {
+		return value
+	}
+
+	if field.IsNil() {
+		return nil
+	}
+
+	switch value.(type) {
+	case driver.Valuer, *asetypes.Decimal:
+		return value
+	}
+
+	return field.Elem().Interface()
+}
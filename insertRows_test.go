@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertFields(t *testing.T) {
+	type row struct {
+		ID       int64 `db:"id"`
+		Name     string
+		Comment  string `db:"-"`
+		internal int
+		Amount   int `db:"amount"`
+	}
+
+	cases := map[string]struct {
+		skip          map[string]bool
+		expectFields  []int
+		expectColumns []string
+	}{
+		"all":      {nil, []int{0, 1, 4}, []string{"id", "Name", "amount"}},
+		"identity": {map[string]bool{"id": true}, []int{1, 4}, []string{"Name", "amount"}},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fields, columns := insertFields(reflect.TypeOf(row{}), cas.skip)
+
+			if !reflect.DeepEqual(fields, cas.expectFields) {
+				t.Errorf("Expected fields %v, received %v", cas.expectFields, fields)
+			}
+
+			if !reflect.DeepEqual(columns, cas.expectColumns) {
+				t.Errorf("Expected columns %v, received %v", cas.expectColumns, columns)
+			}
+		})
+	}
+}
+
+func TestInsertValue(t *testing.T) {
+	i := 5
+	var nilInt *int
+
+	cases := map[string]struct {
+		value  interface{}
+		expect interface{}
+	}{
+		"value":       {5, 5},
+		"pointer":     {&i, 5},
+		"nil pointer": {nilInt, nil},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := insertValue(reflect.ValueOf(cas.value)); recv != cas.expect {
+				t.Errorf("Expected %v, received %v", cas.expect, recv)
+			}
+		})
+	}
+}
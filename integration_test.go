@@ -8,12 +8,19 @@
 package ase
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"log"
+	"math/big"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/SAP/go-dblib/integration"
+	"github.com/SAP/go-dblib/tds"
 )
 
 func TestMain(m *testing.M) {
@@ -107,3 +114,1603 @@ func TestImage(t *testing.T) { integration.DoTestImage(t) }
 // Routines
 func TestSQLTx(t *testing.T)   { integration.DoTestSQLTx(t) }
 func TestSQLExec(t *testing.T) { integration.DoTestSQLExec(t) }
+
+func TestSessionSettings(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	info.Language = "us_english"
+	info.DateFormat = "dmy"
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	if lang := conn.Language(); lang != "us_english" {
+		t.Errorf("Expected language %q, received %q", "us_english", lang)
+	}
+
+	if format := conn.DateFormat(); format != "dmy" {
+		t.Errorf("Expected dateformat %q, received %q", "dmy", format)
+	}
+
+	rows, _, err := conn.DirectExec(context.Background(), "select datepart(month, convert(datetime, '01/02/2021'))")
+	if err != nil {
+		t.Errorf("Error converting date: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		t.Errorf("Error reading converted date: %v", err)
+		return
+	}
+
+	if month := fmt.Sprint(values[0]); month != "2" {
+		t.Errorf("Expected month 2 with dateformat dmy, received %s", month)
+	}
+}
+
+func TestLastInsertId(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	info.LastInsertId = true
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(context.Background(), "create table #lastinsertid (id numeric(10, 0) identity, a int)", nil); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		result, err := conn.ExecContext(context.Background(), "insert into #lastinsertid (a) values (?)", []driver.NamedValue{{Ordinal: 1, Value: i}})
+		if err != nil {
+			t.Errorf("Error inserting row: %v", err)
+			return
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			t.Errorf("Error retrieving last insert id: %v", err)
+			return
+		}
+
+		if id != i {
+			t.Errorf("Expected last insert id %d, received %d", i, id)
+		}
+	}
+}
+
+func TestExplainQuery(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	plan, err := conn.ExplainQuery(context.Background(), "select * from sysobjects where id = 1")
+	if err != nil {
+		t.Errorf("Error explaining query: %v", err)
+		return
+	}
+
+	if !strings.Contains(plan, "QUERY PLAN") {
+		t.Errorf("Expected query plan, received %q", plan)
+	}
+
+	// The query plan must not be captured after ExplainQuery and
+	// statements must be executed again.
+	rows, _, err := conn.DirectExec(context.Background(), "select 1")
+	if err != nil {
+		t.Errorf("Error executing statement: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		t.Errorf("Expected row after ExplainQuery, received error: %v", err)
+	}
+}
+
+func TestChained(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	info.Chained = true
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	selectInt := func(query string) (int64, error) {
+		rows, _, err := conn.DirectExec(context.Background(), query)
+		if err != nil {
+			return 0, err
+		}
+		defer rows.Close()
+
+		values := make([]driver.Value, 1)
+		if err := rows.Next(values); err != nil {
+			return 0, err
+		}
+
+		var i int64
+		_, err = fmt.Sscan(fmt.Sprint(values[0]), &i)
+		return i, err
+	}
+
+	if chained, err := selectInt("select @@tranchained"); err != nil || chained != 1 {
+		t.Errorf("Expected @@tranchained to be 1, received %d: %v", chained, err)
+		return
+	}
+
+	if _, err := conn.ExecContext(context.Background(), "create table #chained (a int)", nil); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	tx, err := conn.BeginTx(context.Background(), DefaultTxOptions())
+	if err != nil {
+		t.Errorf("Error beginning transaction: %v", err)
+		return
+	}
+
+	if _, err := conn.ExecContext(context.Background(), "insert into #chained values (1)", nil); err != nil {
+		t.Errorf("Error inserting value: %v", err)
+		return
+	}
+
+	if !conn.InTransaction() {
+		t.Errorf("Expected insert to begin transaction")
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("Error rolling back transaction: %v", err)
+		return
+	}
+
+	if count, err := selectInt("select count(*) from #chained"); err != nil || count != 0 {
+		t.Errorf("Expected rolled back insert, received %d rows: %v", count, err)
+	}
+
+	// Statements outside of transactions start a transaction, which
+	// must be ended by the application before the session is reset.
+	if err := conn.resetChained(context.Background()); !errors.Is(err, ErrImplicitTransaction) {
+		t.Errorf("Expected ErrImplicitTransaction, received: %v", err)
+	}
+
+	if err := conn.ResetSession(context.Background()); !errors.Is(err, driver.ErrBadConn) {
+		t.Errorf("Expected driver.ErrBadConn resetting session, received: %v", err)
+	}
+
+	if _, err := conn.ExecContext(context.Background(), "commit", nil); err != nil {
+		t.Errorf("Error committing implicit transaction: %v", err)
+		return
+	}
+
+	if err := conn.ResetSession(context.Background()); err != nil {
+		t.Errorf("Error resetting session: %v", err)
+		return
+	}
+
+	if conn.InTransaction() {
+		t.Errorf("Expected no transaction after resetting session")
+	}
+}
+
+func TestCursorOptions(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(context.Background(), "create table #cursoroptions (a int primary key, b int)", nil); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+	defer conn.ExecContext(context.Background(), "drop table #cursoroptions", nil)
+
+	cursor, err := conn.NewCursor(context.Background(), "select a, b from #cursoroptions where a > ?",
+		CursorOptions{ForUpdate: true, UpdateColumns: []string{"b"}}, 0)
+	if err != nil {
+		t.Errorf("Error declaring cursor for update: %v", err)
+		return
+	}
+
+	if err := cursor.Close(context.Background()); err != nil {
+		t.Errorf("Error closing cursor: %v", err)
+		return
+	}
+
+	_, err = conn.NewCursor(context.Background(), "select distinct b from #cursoroptions", CursorOptions{ForUpdate: true})
+	if err == nil || !strings.Contains(err.Error(), "not be updatable") {
+		t.Errorf("Expected error declaring non-updatable query for update, received %v", err)
+	}
+}
+
+func TestConnOpenStatements(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	stmt, err := conn.NewStmt(context.Background(), "", "select ?", false)
+	if err != nil {
+		t.Errorf("Error preparing statement: %v", err)
+		return
+	}
+
+	if stmts := conn.OpenStatements(); len(stmts) != 1 || stmts[0].Query != "select ?" {
+		t.Errorf("Expected prepared statement to be listed, received %v", stmts)
+	}
+
+	if err := stmt.Close(); err != nil {
+		t.Errorf("Error closing statement: %v", err)
+		return
+	}
+
+	if stmts := conn.OpenStatements(); len(stmts) != 0 {
+		t.Errorf("Expected no open statements after closing, received %v", stmts)
+	}
+
+	// Statements prepared to execute queries with arguments are
+	// deallocated once their rows are closed.
+	for i := 0; i < 5; i++ {
+		rows, _, err := conn.DirectExec(context.Background(), "select ?", i)
+		if err != nil {
+			t.Errorf("Error executing query with arguments: %v", err)
+			return
+		}
+
+		if err := rows.Close(); err != nil {
+			t.Errorf("Error closing rows: %v", err)
+			return
+		}
+	}
+
+	if _, err := conn.ExecContext(context.Background(), "select convert(int, ?)", []driver.NamedValue{{Ordinal: 1, Value: "x"}}); err == nil {
+		t.Errorf("Expected error executing failing query with arguments")
+	}
+
+	if stmts := conn.OpenStatements(); len(stmts) != 0 {
+		t.Errorf("Expected no open statements after queries with arguments, received %v", stmts)
+	}
+
+	cursor, err := conn.NewCursor(context.Background(), "select 1")
+	if err != nil {
+		t.Errorf("Error declaring cursor: %v", err)
+		return
+	}
+
+	cursors := conn.OpenCursors()
+	if len(cursors) != 1 || cursors[0].Query != "select 1" || cursors[0].ID != cursor.CursorID() {
+		t.Errorf("Expected cursor to be listed, received %v", cursors)
+	}
+
+	if err := cursor.Close(context.Background()); err != nil {
+		t.Errorf("Error closing cursor: %v", err)
+		return
+	}
+
+	if cursors := conn.OpenCursors(); len(cursors) != 0 {
+		t.Errorf("Expected no open cursors after closing, received %v", cursors)
+	}
+}
+
+func TestNamedParams(t *testing.T) {
+	integration.TestForEachDB("TestNamedParams", t, testNamedParams)
+}
+
+func testNamedParams(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec("create table " + tableName + " (a int, b varchar(30))"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	insert, err := db.Prepare("insert into " + tableName + " values (@a, @b)")
+	if err != nil {
+		t.Errorf("Error preparing insert: %v", err)
+		return
+	}
+	defer insert.Close()
+
+	// The arguments are bound by name, not by their order.
+	if _, err := insert.Exec(sql.Named("b", "one"), sql.Named("a", 1)); err != nil {
+		t.Errorf("Error inserting with named arguments: %v", err)
+		return
+	}
+
+	if _, err := insert.Exec(1, "one"); err == nil {
+		t.Errorf("Expected error inserting with unnamed arguments")
+	}
+
+	query, err := db.Prepare("select b from " + tableName + " where a = @a or a = @a + 1")
+	if err != nil {
+		t.Errorf("Error preparing query: %v", err)
+		return
+	}
+	defer query.Close()
+
+	var b string
+	if err := query.QueryRow(sql.Named("a", 1)).Scan(&b); err != nil {
+		t.Errorf("Error querying with repeated named argument: %v", err)
+		return
+	}
+
+	if b != "one" {
+		t.Errorf("Expected %q, received %q", "one", b)
+	}
+
+	if _, err := db.Prepare("select b from " + tableName + " where a = ? or a = @a"); !errors.Is(err, ErrMixedPlaceholders) {
+		t.Errorf("Expected ErrMixedPlaceholders, received %v", err)
+	}
+
+	// Declared variables are not placeholders.
+	var x int
+	if err := db.QueryRow("declare @x int select @x = ? select @x", 2).Scan(&x); err != nil {
+		t.Errorf("Error querying with declared variable: %v", err)
+	} else if x != 2 {
+		t.Errorf("Expected %d, received %d", 2, x)
+	}
+
+	// Neither are the parameter names of procedures.
+	proc := tableName + "_proc"
+	if _, err := db.Exec("create proc " + proc + " @p int as select @p * 2"); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop proc " + proc)
+
+	if err := db.QueryRow("exec "+proc+" @p = ?", 21).Scan(&x); err != nil {
+		t.Errorf("Error executing procedure with positional argument: %v", err)
+	} else if x != 42 {
+		t.Errorf("Expected %d, received %d", 42, x)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	integration.TestForEachDB("TestDuration", t, testDuration)
+}
+
+func testDuration(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec("create table " + tableName + " (a int, t time)"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	opens := 8*time.Hour + 30*time.Minute + 500*time.Millisecond
+	if _, err := db.Exec("insert into "+tableName+" values (?, ?)", 1, opens); err != nil {
+		t.Errorf("Error inserting duration: %v", err)
+		return
+	}
+
+	var recv time.Duration
+	if err := db.QueryRow("select t from "+tableName+" where a = ?", 1).Scan(AsDuration(&recv)); err != nil {
+		t.Errorf("Error scanning duration: %v", err)
+		return
+	}
+
+	if recv != opens {
+		t.Errorf("Expected %s, received %s", opens, recv)
+	}
+
+	if _, err := db.Exec("insert into "+tableName+" values (?, ?)", 2, 24*time.Hour); err == nil {
+		t.Errorf("Expected error inserting duration of 24h")
+	}
+}
+
+func TestTableColumns(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	connector, err := NewConnector(info)
+	if err != nil {
+		t.Errorf("Error creating connector: %v", err)
+		return
+	}
+	connector.(*Connector).EnableSchemaCache()
+
+	driverConn, err := connector.Connect(context.Background())
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	conn := driverConn.(*Conn)
+	defer conn.Close()
+
+	if err := conn.execNoRows(context.Background(), "create table #tablecolumns (id int identity, a varchar(10) null)"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	columns, err := conn.TableColumns(context.Background(), "#tablecolumns")
+	if err != nil {
+		t.Errorf("Error retrieving columns: %v", err)
+		return
+	}
+
+	if len(columns) != 2 || columns[0].Name != "id" || !columns[0].Identity ||
+		columns[1].Name != "a" || !columns[1].Nullable || columns[1].Length != 10 {
+		t.Errorf("Received unexpected columns: %+v", columns)
+	}
+
+	if err := conn.execNoRows(context.Background(), "alter table #tablecolumns add b int null"); err != nil {
+		t.Errorf("Error altering table: %v", err)
+		return
+	}
+
+	if columns, _ := conn.TableColumns(context.Background(), "#tablecolumns"); len(columns) != 2 {
+		t.Errorf("Expected cached columns, received %+v", columns)
+	}
+
+	connector.(*Connector).InvalidateSchemaCache("#tablecolumns")
+
+	if columns, _ := conn.TableColumns(context.Background(), "#tablecolumns"); len(columns) != 3 {
+		t.Errorf("Expected columns to be retrieved again after invalidation, received %+v", columns)
+	}
+}
+
+func TestHealthcheck(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	latency, err := conn.Healthcheck(context.Background())
+	if err != nil {
+		t.Errorf("Error running healthcheck: %v", err)
+		return
+	}
+
+	if latency <= 0 {
+		t.Errorf("Expected positive latency, received %v", latency)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	latency, err = conn.Healthcheck(ctx)
+	if err == nil {
+		t.Errorf("Expected error running healthcheck with cancelled context")
+	}
+
+	var healthcheckErr *HealthcheckError
+	if errors.As(err, &healthcheckErr) || latency != 0 {
+		t.Errorf("Expected cancelled healthcheck to fail without latency, received %v (%v)", err, latency)
+	}
+}
+
+func TestWithIdentityInsert(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(context.Background(), "create table #identityinsert (id numeric(10, 0) identity, a int)", nil); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	insert := func() error {
+		_, err := conn.ExecContext(context.Background(), "insert into #identityinsert (id, a) values (42, 1)", nil)
+		return err
+	}
+
+	if err := conn.WithIdentityInsert(context.Background(), "#identityinsert", insert); err != nil {
+		t.Errorf("Error inserting with identity_insert: %v", err)
+		return
+	}
+
+	fnErr := errors.New("callback failed")
+	err = conn.WithIdentityInsert(context.Background(), "#identityinsert", func() error { return fnErr })
+	if !errors.Is(err, fnErr) {
+		t.Errorf("Expected error of callback, received %v", err)
+	}
+
+	// identity_insert must be disabled again.
+	if err := insert(); err == nil {
+		t.Errorf("Expected error inserting explicit identity value without identity_insert")
+	}
+}
+
+func TestArithOptions(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	info.ArithAbort = "off"
+	info.ArithIgnore = "on"
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	check := func() {
+		rows, _, err := conn.DirectExec(context.Background(), "select 1 / 0")
+		if err != nil {
+			t.Errorf("Expected division by zero to be ignored, received %v", err)
+			return
+		}
+		defer rows.Close()
+
+		values := []driver.Value{0}
+		if err := rows.Next(values); err != nil {
+			t.Errorf("Error reading result: %v", err)
+			return
+		}
+
+		if values[0] != nil {
+			t.Errorf("Expected NULL for division by zero, received %v", values[0])
+		}
+	}
+
+	check()
+
+	if err := conn.execNoRows(context.Background(), "set arithabort arith_overflow on set arithignore arith_overflow off"); err != nil {
+		t.Errorf("Error changing arithmetic options: %v", err)
+		return
+	}
+
+	if err := conn.ResetSession(context.Background()); err != nil {
+		t.Errorf("Error resetting session: %v", err)
+		return
+	}
+
+	check()
+}
+
+func TestExecMulti(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	batch := `create table #execmulti (a int)
+insert into #execmulti values (1)
+insert into #execmulti select a + 1 from #execmulti union all select a + 2 from #execmulti
+update #execmulti set a = a * 10`
+
+	results, err := conn.ExecMulti(context.Background(), batch)
+	if err != nil {
+		t.Errorf("Error executing batch: %v", err)
+		return
+	}
+
+	expect := []int64{1, 2, 3}
+	if len(results) != len(expect) {
+		t.Errorf("Expected %d results, received %d", len(expect), len(results))
+		return
+	}
+
+	for i, result := range results {
+		affected, err := result.RowsAffected()
+		if err != nil {
+			t.Errorf("Error reading rows affected of statement %d: %v", i, err)
+			continue
+		}
+
+		if affected != expect[i] {
+			t.Errorf("Expected %d rows affected by statement %d, received %d", expect[i], i, affected)
+		}
+	}
+}
+
+func TestEmptyStringAsNull(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	info.EmptyStringAsNull = true
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	if err := conn.execNoRows(context.Background(), "create table #emptystringasnull (a int null, b varchar(10) null, c varchar(10) not null)"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	// The empty string bound to the column declared not null is stored
+	// as an empty string.
+	args := []driver.NamedValue{{Ordinal: 1, Value: ""}, {Ordinal: 2, Value: ""}, {Ordinal: 3, Value: ""}}
+	if _, err := conn.ExecContext(context.Background(), "insert into #emptystringasnull values (?, ?, ?)", args); err != nil {
+		t.Errorf("Error inserting empty strings: %v", err)
+		return
+	}
+
+	rows, _, err := conn.DirectExec(context.Background(), "select count(*) from #emptystringasnull where a is null and b is null and c = ''")
+	if err != nil {
+		t.Errorf("Error selecting NULL values: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		t.Errorf("Error reading count: %v", err)
+		return
+	}
+
+	if values[0] != int32(1) {
+		t.Errorf("Expected the empty strings to be stored as NULL, received count %v", values[0])
+	}
+}
+
+func TestQueryJSON(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	buf := new(strings.Builder)
+	query := "select convert(int, ?) as id, 'a' as name, convert(numeric(5, 2), 1.5) as amount, 0x0102 as data, convert(int, null) as missing"
+	if err := conn.QueryJSON(context.Background(), buf, query, 1); err != nil {
+		t.Errorf("Error querying JSON: %v", err)
+		return
+	}
+
+	expect := `[{"id":1,"name":"a","amount":1.5,"data":"AQI=","missing":null}]`
+	if buf.String() != expect {
+		t.Errorf("Expected %s, received %s", expect, buf.String())
+	}
+
+	buf.Reset()
+	if err := conn.QueryJSON(context.Background(), buf, "select 1 as a where 1 = 0"); err != nil {
+		t.Errorf("Error querying empty result as JSON: %v", err)
+		return
+	}
+
+	if buf.String() != "[]" {
+		t.Errorf("Expected empty array, received %s", buf.String())
+	}
+}
+
+func TestQueryPage(t *testing.T) {
+	integration.TestForEachDB("TestQueryPage", t, testQueryPage)
+}
+
+func testQueryPage(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec("create table " + tableName + " (a int)"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	for i := 1; i <= 25; i++ {
+		if _, err := db.Exec("insert into "+tableName+" values (?)", i); err != nil {
+			t.Errorf("Error inserting row: %v", err)
+			return
+		}
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	cases := map[string]struct {
+		offset, limit int
+		expect        []int32
+	}{
+		"first page":   {0, 10, []int32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		"second page":  {10, 10, []int32{11, 12, 13, 14, 15, 16, 17, 18, 19, 20}},
+		"partial page": {20, 10, []int32{21, 22, 23, 24, 25}},
+		"past the end": {30, 10, nil},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			err := conn.Raw(func(driverConn interface{}) error {
+				c := driverConn.(*Conn)
+
+				rows, err := c.QueryPage(context.Background(), "select a from "+tableName+" where a > ? order by a", cas.offset, cas.limit, 0)
+				if err != nil {
+					return fmt.Errorf("error querying page: %w", err)
+				}
+
+				all, err := rows.ReadAll()
+				if err != nil {
+					return fmt.Errorf("error reading page: %w", err)
+				}
+
+				var recv []int32
+				for _, row := range all {
+					recv = append(recv, row[0].(int32))
+				}
+
+				if fmt.Sprint(recv) != fmt.Sprint(cas.expect) {
+					return fmt.Errorf("expected %v, received %v", cas.expect, recv)
+				}
+
+				return nil
+			})
+			if err != nil {
+				t.Errorf("%v", err)
+			}
+		})
+	}
+}
+
+func TestTransactionState(t *testing.T) {
+	cases := map[string]struct {
+		chained bool
+		steps   []struct {
+			query  string
+			expect TxState
+		}
+	}{
+		"unchained": {
+			false,
+			[]struct {
+				query  string
+				expect TxState
+			}{
+				{"select 1", TxState{Mode: TxNone}},
+				{"begin transaction", TxState{Mode: TxExplicit, Level: 1}},
+				{"begin transaction", TxState{Mode: TxExplicit, Level: 2}},
+				{"commit transaction", TxState{Mode: TxExplicit, Level: 1}},
+				{"commit transaction", TxState{Mode: TxNone}},
+			},
+		},
+		"chained": {
+			true,
+			[]struct {
+				query  string
+				expect TxState
+			}{
+				{"insert into #txstate values (1)", TxState{Mode: TxImplicit, Level: 1}},
+				{"rollback transaction", TxState{Mode: TxNone}},
+			},
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			info, err := NewInfoWithEnv()
+			if err != nil {
+				t.Errorf("Error reading info from environment: %v", err)
+				return
+			}
+
+			info.Chained = cas.chained
+
+			conn, err := NewConn(context.Background(), info)
+			if err != nil {
+				t.Errorf("Error opening connection: %v", err)
+				return
+			}
+			defer conn.Close()
+
+			if err := conn.execNoRows(context.Background(), "create table #txstate (a int)"); err != nil {
+				t.Errorf("Error creating table: %v", err)
+				return
+			}
+
+			for _, step := range cas.steps {
+				if err := conn.execNoRows(context.Background(), step.query); err != nil {
+					t.Errorf("Error executing %q: %v", step.query, err)
+					return
+				}
+
+				state, err := conn.TransactionState(context.Background())
+				if err != nil {
+					t.Errorf("Error retrieving transaction state: %v", err)
+					return
+				}
+
+				if state != step.expect {
+					t.Errorf("Expected %+v after %q, received %+v", step.expect, step.query, state)
+				}
+			}
+
+			// Transactions started through the driver are explicit in
+			// both modes.
+			tx, err := conn.BeginTx(context.Background(), DefaultTxOptions())
+			if err != nil {
+				t.Errorf("Error beginning transaction: %v", err)
+				return
+			}
+
+			if err := conn.execNoRows(context.Background(), "insert into #txstate values (2)"); err != nil {
+				t.Errorf("Error inserting value: %v", err)
+				return
+			}
+
+			state, err := conn.TransactionState(context.Background())
+			if err != nil {
+				t.Errorf("Error retrieving transaction state: %v", err)
+				return
+			}
+
+			if expect := (TxState{Mode: TxExplicit, Level: 1}); state != expect {
+				t.Errorf("Expected %+v within transaction, received %+v", expect, state)
+			}
+
+			if err := tx.Rollback(); err != nil {
+				t.Errorf("Error rolling back transaction: %v", err)
+			}
+		})
+	}
+}
+
+func TestBigNumbers(t *testing.T) {
+	integration.TestForEachDB("TestBigNumbers", t, testBigNumbers)
+}
+
+func testBigNumbers(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec("create table " + tableName + " (a int, n numeric(38, 10))"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	huge, _ := new(big.Int).SetString("1234567890123456789012345678", 10)
+	values := map[int]interface{}{
+		1: huge,
+		2: big.NewRat(-1, 1024),
+	}
+
+	for a, value := range values {
+		if _, err := db.Exec("insert into "+tableName+" values (?, ?)", a, value); err != nil {
+			t.Errorf("Error inserting %v: %v", value, err)
+			return
+		}
+	}
+
+	for a, value := range values {
+		recv := new(big.Rat)
+		if err := db.QueryRow("select n from "+tableName+" where a = ?", a).Scan(AsRat(recv)); err != nil {
+			t.Errorf("Error scanning %v: %v", value, err)
+			return
+		}
+
+		expect, _ := bigRat(value)
+		if recv.Cmp(expect) != 0 {
+			t.Errorf("Expected %s, received %s", expect.RatString(), recv.RatString())
+		}
+	}
+
+	if _, err := db.Exec("insert into "+tableName+" values (?, ?)", 3, big.NewRat(1, 3)); err == nil {
+		t.Errorf("Expected error inserting 1/3")
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	info.TraceID = true
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	applName := func(ctx context.Context) (string, error) {
+		rows, _, err := conn.DirectExec(ctx, "select clientapplname from master..sysprocesses where spid = @@spid")
+		if err != nil {
+			return "", err
+		}
+		defer rows.Close()
+
+		values := make([]driver.Value, 1)
+		if err := rows.Next(values); err != nil {
+			return "", err
+		}
+
+		s, _ := values[0].(string)
+		return strings.TrimSpace(s), nil
+	}
+
+	name, err := applName(WithTraceID(context.Background(), "trace-1"))
+	if err != nil {
+		t.Errorf("Error selecting clientapplname: %v", err)
+		return
+	}
+
+	if name != "trace-1" {
+		t.Errorf("Expected clientapplname %q, received %q", "trace-1", name)
+	}
+
+	name, err = applName(context.Background())
+	if err != nil {
+		t.Errorf("Error selecting clientapplname: %v", err)
+		return
+	}
+
+	if name != "" {
+		t.Errorf("Expected clientapplname to be reset, received %q", name)
+	}
+}
+
+func TestNoDynamicProc(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	info.NoDynamicProc = true
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	driverStmt, err := conn.PrepareContext(context.Background(), "select ?")
+	if err != nil {
+		t.Errorf("Error preparing statement: %v", err)
+		return
+	}
+	defer driverStmt.Close()
+
+	stmt := driverStmt.(*Stmt)
+	if stmts := conn.OpenStatements(); len(stmts) != 1 || stmts[0].Name != stmt.Name() {
+		t.Errorf("Expected statement %q to be listed, received %v", stmt.Name(), stmts)
+	}
+
+	rows, _, err := stmt.DirectExec(context.Background(), 1)
+	if err != nil {
+		t.Errorf("Error executing statement: %v", err)
+		return
+	}
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		t.Errorf("Error reading row: %v", err)
+		return
+	}
+	rows.Close()
+
+	if values[0] != int64(1) && values[0] != int32(1) {
+		t.Errorf("Expected 1, received %v", values[0])
+	}
+}
+
+func TestRowCount(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+
+	if err := conn.execNoRows(ctx, "create table #rowcount (a int)"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	if err := conn.execNoRows(ctx, "insert into #rowcount values (1) insert into #rowcount values (2) insert into #rowcount values (3)"); err != nil {
+		t.Errorf("Error inserting values: %v", err)
+		return
+	}
+
+	cases := map[string]struct {
+		query  string
+		expect int64
+	}{
+		"update": {"update #rowcount set a = a + 1 where a > 1", 2},
+		"delete": {"delete from #rowcount where a = 1", 1},
+		"select": {"select a from #rowcount", 2},
+		"none":   {"update #rowcount set a = 0 where a < 0", 0},
+	}
+
+	for _, title := range []string{"update", "delete", "select", "none"} {
+		cas := cases[title]
+		t.Run(title, func(t *testing.T) {
+			rows, _, err := conn.DirectExec(ctx, cas.query)
+			if err != nil {
+				t.Errorf("Error executing %q: %v", cas.query, err)
+				return
+			}
+
+			if _, err := rows.(*Rows).ReadAll(); err != nil {
+				t.Errorf("Error reading rows: %v", err)
+				return
+			}
+			rows.Close()
+
+			count, err := conn.RowCount(ctx)
+			if err != nil {
+				t.Errorf("Error retrieving row count: %v", err)
+				return
+			}
+
+			if count != cas.expect {
+				t.Errorf("Expected row count %d, received %d", cas.expect, count)
+			}
+		})
+	}
+}
+
+func TestParamTruncated(t *testing.T) {
+	integration.TestForEachDB("TestParamTruncated", t, testParamTruncated)
+}
+
+func testParamTruncated(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec("create table " + tableName + " (a varchar(5), b varbinary(2))"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	query := "insert into " + tableName + " values (?, ?)"
+
+	if _, err := db.Exec(query, "abcde", []byte{1, 2}); err != nil {
+		t.Errorf("Error inserting values at the maximum length: %v", err)
+	}
+
+	if _, err := db.Exec(query, "abcdef", []byte{1, 2}); !errors.Is(err, ErrParamTruncated) {
+		t.Errorf("Expected ErrParamTruncated for character value, received %v", err)
+	}
+
+	if _, err := db.Exec(query, "abcde", []byte{1, 2, 3}); !errors.Is(err, ErrParamTruncated) {
+		t.Errorf("Expected ErrParamTruncated for binary value, received %v", err)
+	}
+}
+
+func TestNoCount(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+
+	if err := conn.execNoRows(ctx, "create table #nocount (a int) insert into #nocount values (1) insert into #nocount values (2)"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	update := func() (int64, error) {
+		result, err := conn.ExecContext(ctx, "update #nocount set a = a + 1", nil)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+
+	if err := conn.SetNoCount(ctx, true); err != nil {
+		t.Errorf("Error enabling nocount: %v", err)
+		return
+	}
+
+	if !conn.NoCount() {
+		t.Errorf("Expected NoCount to report nocount on")
+	}
+
+	if _, err := update(); !errors.Is(err, ErrRowsAffectedUnavailable) {
+		t.Errorf("Expected ErrRowsAffectedUnavailable, received %v", err)
+	}
+
+	if _, _, err := conn.DirectExec(ctx, "set nocount off"); err != nil {
+		t.Errorf("Error disabling nocount: %v", err)
+		return
+	}
+
+	if conn.NoCount() {
+		t.Errorf("Expected NoCount to report nocount off")
+	}
+
+	count, err := update()
+	if err != nil {
+		t.Errorf("Error updating rows: %v", err)
+		return
+	}
+
+	if count != 2 {
+		t.Errorf("Expected 2 affected rows, received %d", count)
+	}
+}
+
+func TestBulkDelete(t *testing.T) {
+	integration.TestForEachDB("TestBulkDelete", t, testBulkDelete)
+}
+
+func testBulkDelete(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec("create table " + tableName + " (a int)"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	for i := 1; i <= 25; i++ {
+		if _, err := db.Exec("insert into "+tableName+" values (?)", i); err != nil {
+			t.Errorf("Error inserting value %d: %v", i, err)
+			return
+		}
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	count := func(c *Conn) (int64, error) {
+		rows, _, err := c.DirectExec(context.Background(), "select count(*) from "+tableName)
+		if err != nil {
+			return 0, err
+		}
+		defer rows.Close()
+
+		values := make([]driver.Value, 1)
+		if err := rows.Next(values); err != nil {
+			return 0, err
+		}
+		return int64(values[0].(int32)), nil
+	}
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		deleted, err := c.BulkDelete(context.Background(), tableName, "a > ?", 10, 3)
+		if err != nil {
+			return fmt.Errorf("error deleting rows: %w", err)
+		}
+
+		if deleted != 22 {
+			return fmt.Errorf("expected 22 deleted rows, received %d", deleted)
+		}
+
+		// The row limit must be reset after the batches.
+		if n, err := count(c); err != nil || n != 3 {
+			return fmt.Errorf("expected 3 remaining rows, received %d (%v)", n, err)
+		}
+
+		if err := c.Truncate(context.Background(), tableName); err != nil {
+			return fmt.Errorf("error truncating table: %w", err)
+		}
+
+		if n, err := count(c); err != nil || n != 0 {
+			return fmt.Errorf("expected no rows after truncating, received %d (%v)", n, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestServerCharset(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	rows, _, err := conn.DirectExec(context.Background(), "select @@client_csname")
+	if err != nil {
+		t.Errorf("Error selecting client charset: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		t.Errorf("Error reading client charset: %v", err)
+		return
+	}
+
+	if charset := conn.Charset(); charset != "" && charset != values[0] {
+		t.Errorf("Expected charset %v, received %q", values[0], charset)
+	}
+
+	// The result set must be consumed before the server character set
+	// is selected.
+	rows.Close()
+
+	if charset, err := conn.ServerCharset(context.Background()); err != nil || charset == "" {
+		t.Errorf("Expected server charset to be set, received %q: %v", charset, err)
+	}
+
+	if sortOrder, err := conn.ServerSortOrder(context.Background()); err != nil || sortOrder == "" {
+		t.Errorf("Expected server sort order to be set, received %q: %v", sortOrder, err)
+	}
+}
+
+func TestInsertRows(t *testing.T) {
+	integration.TestForEachDB("TestInsertRows", t, testInsertRows)
+}
+
+func testInsertRows(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec("create table " + tableName + " (id int identity, name varchar(30), amount int null)"); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	type row struct {
+		ID      int64  `db:"id"`
+		Name    string `db:"name"`
+		Amount  *int   `db:"amount"`
+		Comment string `db:"-"`
+	}
+
+	amount := 5
+	rows := []*row{
+		{ID: 100, Name: "a", Amount: &amount, Comment: "ignored"},
+		{Name: "b"},
+		{Name: "c", Amount: &amount},
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		inserted, err := c.InsertRows(context.Background(), tableName, rows)
+		if err != nil {
+			return fmt.Errorf("error inserting rows: %w", err)
+		}
+
+		if inserted != 3 {
+			return fmt.Errorf("expected 3 inserted rows, received %d", inserted)
+		}
+
+		if _, err := c.InsertRows(context.Background(), tableName, []*row{{Name: "d"}, nil}); err == nil {
+			return fmt.Errorf("expected error inserting a nil row")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	// The identity column is generated by the server and the failed
+	// call was rolled back.
+	var ids, names string
+	err = db.QueryRow(fmt.Sprintf("select convert(varchar(10), max(id)), convert(varchar(10), count(*)) + ':' + convert(varchar(10), sum(amount)) from %s", tableName)).Scan(&ids, &names)
+	if err != nil {
+		t.Errorf("Error selecting rows: %v", err)
+		return
+	}
+
+	if ids != "3" || names != "3:10" {
+		t.Errorf("Expected max id 3 and 3 rows with a total amount of 10, received %s and %s", ids, names)
+	}
+}
+
+func TestSessionVar(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	if err := conn.SetVar(context.Background(), "textsize", 4096); err != nil {
+		t.Errorf("Error setting textsize: %v", err)
+		return
+	}
+
+	textsize, err := conn.GetVar(context.Background(), "@@textsize")
+	if err != nil {
+		t.Errorf("Error reading textsize: %v", err)
+		return
+	}
+
+	if textsize != int64(4096) {
+		t.Errorf("Expected textsize 4096, received %v (%T)", textsize, textsize)
+	}
+
+	if err := conn.SetVar(context.Background(), "clientname", "go-ase test"); err != nil {
+		t.Errorf("Error setting clientname: %v", err)
+		return
+	}
+
+	clientname, err := conn.GetVar(context.Background(), "clientname")
+	if err != nil {
+		t.Errorf("Error reading clientname: %v", err)
+		return
+	}
+
+	if clientname != "go-ase test" {
+		t.Errorf("Expected clientname %q, received %v", "go-ase test", clientname)
+	}
+}
+
+func TestAbortAll(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	tx, err := conn.NewTransaction(context.Background(), driver.TxOptions{}, "abortall")
+	if err != nil {
+		t.Errorf("Error opening transaction: %v", err)
+		return
+	}
+
+	if _, err := tx.NewTransaction(context.Background(), driver.TxOptions{}); err != nil {
+		t.Errorf("Error opening nested transaction: %v", err)
+		return
+	}
+
+	// Leave the connection mid-stream with unread rows.
+	rows, _, err := conn.DirectExec(context.Background(), "select name from sysobjects")
+	if err != nil {
+		t.Errorf("Error selecting rows: %v", err)
+		return
+	}
+
+	if err := rows.Next(make([]driver.Value, 1)); err != nil {
+		t.Errorf("Error reading row: %v", err)
+		return
+	}
+
+	if err := conn.AbortAll(context.Background()); err != nil {
+		t.Errorf("Error aborting transactions: %v", err)
+		return
+	}
+
+	if conn.InTransaction() {
+		t.Errorf("Expected no open transaction")
+	}
+
+	state, err := conn.TransactionState(context.Background())
+	if err != nil {
+		t.Errorf("Error reading transaction state: %v", err)
+		return
+	}
+
+	if state.Mode != TxNone || state.Level != 0 {
+		t.Errorf("Expected no transaction, received %+v", state)
+	}
+
+	it, err := conn.Query(context.Background(), "select convert(bigint, @@trancount)")
+	if err != nil {
+		t.Errorf("Error selecting @@trancount: %v", err)
+		return
+	}
+	defer it.Close()
+
+	var trancount int64
+	if !it.Next() {
+		t.Errorf("Error reading @@trancount: %v", it.Err())
+		return
+	}
+
+	if err := it.Scan(&trancount); err != nil {
+		t.Errorf("Error scanning @@trancount: %v", err)
+		return
+	}
+
+	if trancount != 0 {
+		t.Errorf("Expected @@trancount 0, received %d", trancount)
+	}
+}
+
+func TestExecAdmin(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	var messages []string
+	err = conn.ExecAdmin(context.Background(), "print 'step 1' waitfor delay '00:00:01' print 'step 2'",
+		func(msg *tds.EEDPackage) {
+			messages = append(messages, strings.TrimSpace(msg.Msg))
+		})
+	if err != nil {
+		t.Errorf("Error executing command: %v", err)
+		return
+	}
+
+	if len(messages) != 2 || messages[0] != "step 1" || messages[1] != "step 2" {
+		t.Errorf("Unexpected progress messages: %v", messages)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := conn.ExecAdmin(ctx, "waitfor delay '00:00:10'", nil); err == nil {
+		t.Errorf("Expected error for cancelled command")
+	}
+}
+
+func TestWithArrayParam(t *testing.T) {
+	info, err := NewInfoWithEnv()
+	if err != nil {
+		t.Errorf("Error reading info from environment: %v", err)
+		return
+	}
+
+	conn, err := NewConn(context.Background(), info)
+	if err != nil {
+		t.Errorf("Error opening connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	values := []driver.Value{int64(1), nil, int64(2), int64(40)}
+	name, cleanup, err := conn.WithArrayParam(context.Background(), values)
+	if err != nil {
+		t.Errorf("Error creating array parameter: %v", err)
+		return
+	}
+
+	it, err := conn.Query(context.Background(),
+		"select convert(bigint, count(*)), convert(bigint, sum(value)) from "+name)
+	if err != nil {
+		t.Errorf("Error selecting from %s: %v", name, err)
+		return
+	}
+
+	var count, sum int64
+	if !it.Next() {
+		t.Errorf("Expected a row, received error %v", it.Err())
+		return
+	}
+
+	if err := it.Scan(&count, &sum); err != nil {
+		t.Errorf("Error scanning row: %v", err)
+		return
+	}
+	it.Close()
+
+	if count != int64(len(values)) || sum != 43 {
+		t.Errorf("Expected %d rows with sum 43, received %d rows with sum %d", len(values), count, sum)
+	}
+
+	if err := cleanup(); err != nil {
+		t.Errorf("Error dropping %s: %v", name, err)
+		return
+	}
+
+	// The table has been dropped.
+	if err := cleanup(); err == nil {
+		t.Errorf("Expected error dropping %s again", name)
+	}
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build go1.18
+// +build go1.18
+
+package ase
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Interface satisfaction checks.
+var (
+	_ driver.Valuer = ipText{}
+	_ driver.Valuer = ipBinary{}
+	_ sql.Scanner   = (*ipScanner)(nil)
+)
+
+// ipBinaryLen is the length of addresses in packed form.
+const ipBinaryLen = 16
+
+// AsIPText returns a driver.Valuer binding addr in its text form, e.g.
+// "192.0.2.1" or "fe80::1%eth0", for varchar columns:
+//
+//	db.Exec("insert into hosts (name, addr) values (?, ?)", name, ase.AsIPText(addr))
+//
+// Zone identifiers and IPv4-mapped IPv6 addresses are preserved.
+// A column of 45 characters holds all IPv6 addresses without zone.
+// The zero netip.Addr is bound as NULL.
+func AsIPText(addr netip.Addr) driver.Valuer {
+	return ipText{addr: addr}
+}
+
+type ipText struct {
+	addr netip.Addr
+}
+
+// Value implements the driver.Valuer interface.
+func (ip ipText) Value() (driver.Value, error) {
+	if !ip.addr.IsValid() {
+		return nil, nil
+	}
+	return ip.addr.String(), nil
+}
+
+// AsIPBinary returns a driver.Valuer binding addr in packed form for
+// binary(16) columns:
+//
+//	db.Exec("insert into hosts (name, addr) values (?, ?)", name, ase.AsIPBinary(addr))
+//
+// IPv6 addresses are bound as their 16 bytes and IPv4 addresses as
+// IPv4-mapped IPv6 addresses, so that all values have the same length
+// and compare and sort consistently. Consequently IPv4-mapped IPv6
+// addresses are scanned as IPv4 addresses by AsIP.
+//
+// The packed form cannot hold zone identifiers - binding an address
+// with a zone returns an error. The zero netip.Addr is bound as NULL.
+//
+// varbinary columns are not suitable as ASE truncates trailing zeros
+// of varbinary values.
+func AsIPBinary(addr netip.Addr) driver.Valuer {
+	return ipBinary{addr: addr}
+}
+
+type ipBinary struct {
+	addr netip.Addr
+}
+
+// Value implements the driver.Valuer interface.
+func (ip ipBinary) Value() (driver.Value, error) {
+	if !ip.addr.IsValid() {
+		return nil, nil
+	}
+
+	if ip.addr.Zone() != "" {
+		return nil, fmt.Errorf("go-ase: cannot bind IP address %s with zone in packed form", ip.addr)
+	}
+
+	packed := ip.addr.As16()
+	return packed[:], nil
+}
+
+// AsIP returns a sql.Scanner scanning IP addresses bound through
+// AsIPText or AsIPBinary into addr:
+//
+//	var addr netip.Addr
+//	db.QueryRow("select addr from hosts where name = ?", name).Scan(ase.AsIP(&addr))
+//
+// Character values are parsed in text form, surrounding blanks of char
+// columns are ignored. Binary values of 16 bytes are scanned as IPv6
+// addresses, or IPv4 addresses if they are IPv4-mapped, and binary
+// values of 4 bytes as IPv4 addresses. Scanning NULL sets addr to the
+// zero netip.Addr.
+func AsIP(addr *netip.Addr) sql.Scanner {
+	return &ipScanner{addr: addr}
+}
+
+type ipScanner struct {
+	addr *netip.Addr
+}
+
+// Scan implements the sql.Scanner interface.
+func (scanner *ipScanner) Scan(src interface{}) error {
+	switch typed := src.(type) {
+	case nil:
+		*scanner.addr = netip.Addr{}
+	case string:
+		addr, err := netip.ParseAddr(strings.TrimSpace(typed))
+		if err != nil {
+			return fmt.Errorf("go-ase: cannot scan %q into netip.Addr: %w", typed, err)
+		}
+		*scanner.addr = addr
+	case []byte:
+		if len(typed) != ipBinaryLen && len(typed) != 4 {
+			return fmt.Errorf("go-ase: cannot scan %d bytes into netip.Addr, expected 4 or %d bytes", len(typed), ipBinaryLen)
+		}
+		addr, _ := netip.AddrFromSlice(typed)
+		*scanner.addr = addr.Unmap()
+	default:
+		return fmt.Errorf("go-ase: cannot scan %T into netip.Addr", src)
+	}
+
+	return nil
+}
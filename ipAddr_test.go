@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build go1.18
+// +build go1.18
+
+package ase
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"net/netip"
+	"testing"
+)
+
+func TestIPAddrValue(t *testing.T) {
+	cases := map[string]struct {
+		valuer    driver.Valuer
+		expect    driver.Value
+		expectErr bool
+	}{
+		"text ipv4":         {AsIPText(netip.MustParseAddr("192.0.2.1")), "192.0.2.1", false},
+		"text ipv6":         {AsIPText(netip.MustParseAddr("2001:db8::1")), "2001:db8::1", false},
+		"text ipv4 in ipv6": {AsIPText(netip.MustParseAddr("::ffff:192.0.2.1")), "::ffff:192.0.2.1", false},
+		"text zone":         {AsIPText(netip.MustParseAddr("fe80::1%eth0")), "fe80::1%eth0", false},
+		"text zero":         {AsIPText(netip.Addr{}), nil, false},
+		"binary ipv4": {
+			AsIPBinary(netip.MustParseAddr("192.0.2.1")),
+			[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 192, 0, 2, 1}, false,
+		},
+		"binary ipv6": {
+			AsIPBinary(netip.MustParseAddr("2001:db8::1")),
+			[]byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, false,
+		},
+		"binary zone": {AsIPBinary(netip.MustParseAddr("fe80::1%eth0")), nil, true},
+		"binary zero": {AsIPBinary(netip.Addr{}), nil, false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := cas.valuer.Value()
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if b, ok := cas.expect.([]byte); ok {
+				if recvB, ok := recv.([]byte); !ok || !bytes.Equal(recvB, b) {
+					t.Errorf("Expected %v, received %v", cas.expect, recv)
+				}
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %v, received %v", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestAsIP(t *testing.T) {
+	cases := map[string]struct {
+		src       interface{}
+		expect    netip.Addr
+		expectErr bool
+	}{
+		"null":              {nil, netip.Addr{}, false},
+		"text ipv4":         {"192.0.2.1", netip.MustParseAddr("192.0.2.1"), false},
+		"text padded":       {"192.0.2.1      ", netip.MustParseAddr("192.0.2.1"), false},
+		"text ipv4 in ipv6": {"::ffff:192.0.2.1", netip.MustParseAddr("::ffff:192.0.2.1"), false},
+		"text zone":         {"fe80::1%eth0", netip.MustParseAddr("fe80::1%eth0"), false},
+		"text invalid":      {"192.0.2", netip.Addr{}, true},
+		"binary ipv4":       {[]byte{192, 0, 2, 1}, netip.MustParseAddr("192.0.2.1"), false},
+		"binary ipv4 in ipv6": {
+			[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 192, 0, 2, 1},
+			netip.MustParseAddr("192.0.2.1"), false,
+		},
+		"binary ipv6": {
+			[]byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+			netip.MustParseAddr("2001:db8::1"), false,
+		},
+		"binary invalid length": {[]byte{0x20, 0x01}, netip.Addr{}, true},
+		"unsupported type":      {int64(1), netip.Addr{}, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			addr := netip.MustParseAddr("::1")
+			err := AsIP(&addr).Scan(cas.src)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if addr != cas.expect {
+				t.Errorf("Expected %v, received %v", cas.expect, addr)
+			}
+		})
+	}
+}
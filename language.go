@@ -13,15 +13,33 @@ import (
 	"github.com/SAP/go-dblib/tds"
 )
 
-func (c Conn) language(ctx context.Context, query string) (driver.Rows, driver.Result, error) {
+func (c *Conn) language(ctx context.Context, query string) (driver.Rows, driver.Result, error) {
+	if err := c.acquire(); err != nil {
+		return nil, nil, err
+	}
+	defer c.release()
+
+	if err := c.checkBusy(); err != nil {
+		return nil, nil, err
+	}
+
+	c.resetStats()
+	c.takeMessages()
+	c.queryLog.log(ctx, query, nil)
+
 	langPkg := &tds.LanguagePackage{
 		Status: tds.TDS_LANGUAGE_NOARGS,
 		Cmd:    query,
 	}
 
-	if err := c.Channel.SendPackage(ctx, langPkg); err != nil {
+	if err := c.sendPackage(ctx, langPkg); err != nil {
 		return nil, nil, fmt.Errorf("error sending language command: %w", err)
 	}
 
-	return c.genericResults(ctx)
+	rows, result, err := c.genericResults(ctx)
+	if err == nil {
+		c.trackNoCount(query)
+	}
+
+	return rows, result, err
 }
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+)
+
+var insertQueryRe = regexp.MustCompile(`(?i)^\s*insert\s`)
+
+// isInsertQuery reports if query is an insert statement.
+func isInsertQuery(query string) bool {
+	return insertQueryRe.MatchString(query)
+}
+
+// setLastInsertId retrieves @@identity into result if Info.LastInsertId
+// is set and query inserted a single row.
+//
+// @@identity is zero if the last insert did not insert into a table
+// with an identity column, in which case LastInsertId continues to
+// return an error.
+func (c *Conn) setLastInsertId(ctx context.Context, query string, result driver.Result) error {
+	res, ok := result.(*Result)
+	if !ok || !c.Info.LastInsertId || res.rowsAffected != 1 || !isInsertQuery(query) {
+		return nil
+	}
+
+	rows, _, err := c.language(ctx, "select convert(bigint, @@identity)")
+	if err != nil {
+		return fmt.Errorf("go-ase: error selecting @@identity: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return fmt.Errorf("go-ase: error reading @@identity: %w", err)
+	}
+
+	if id, ok := values[0].(int64); ok {
+		res.lastInsertId = id
+	}
+
+	return nil
+}
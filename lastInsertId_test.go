@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import "testing"
+
+func TestIsInsertQuery(t *testing.T) {
+	cases := map[string]struct {
+		query  string
+		expect bool
+	}{
+		"insert":        {"insert into t values (1)", true},
+		"without into":  {"insert t values (1)", true},
+		"uppercase":     {"INSERT INTO t VALUES (1)", true},
+		"leading space": {"\n\tinsert into t select * from s", true},
+		"select":        {"select * from inserted", false},
+		"update":        {"update t set a = 1", false},
+		"prefix":        {"inserting", false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if got := isInsertQuery(cas.query); got != cas.expect {
+				t.Errorf("Expected %t for %q, received %t", cas.expect, cas.query, got)
+			}
+		})
+	}
+}
+
+func TestResult_LastInsertId(t *testing.T) {
+	if _, err := (Result{}).LastInsertId(); err == nil {
+		t.Errorf("Expected error without identity value")
+	}
+
+	id, err := (Result{lastInsertId: 42}).LastInsertId()
+	if err != nil {
+		t.Errorf("Received unexpected error: %v", err)
+	}
+	if id != 42 {
+		t.Errorf("Expected 42, received %d", id)
+	}
+}
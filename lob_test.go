@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestLOBNullEmpty(t *testing.T) {
+	integration.TestForEachDB("TestLOBNullEmpty", t, testLOBNullEmpty)
+}
+
+func testLOBNullEmpty(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (id int, txt text null, img image null)", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s (id, txt, img) values (?, ?, ?)", tableName), 1, nil, nil); err != nil {
+		t.Errorf("Error inserting NULL values: %v", err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s (id, txt, img) values (2, '', 0x)", tableName)); err != nil {
+		t.Errorf("Error inserting empty values: %v", err)
+		return
+	}
+
+	cases := map[string]struct {
+		id        int
+		expectNil bool
+	}{
+		"null":  {1, true},
+		"empty": {2, false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			var txt sql.NullString
+			var img []byte
+
+			query := fmt.Sprintf("select txt, img from %s where id = ?", tableName)
+			if err := db.QueryRow(query, cas.id).Scan(&txt, &img); err != nil {
+				t.Errorf("Error selecting values: %v", err)
+				return
+			}
+
+			if txt.Valid == cas.expectNil {
+				t.Errorf("Expected text validity to be %t, received %t", !cas.expectNil, txt.Valid)
+			}
+
+			if (img == nil) != cas.expectNil {
+				t.Errorf("Expected image to be nil: %t, received %#v", cas.expectNil, img)
+			}
+		})
+	}
+}
+
+func TestLOBNullEmptyBind(t *testing.T) {
+	integration.TestForEachDB("TestLOBNullEmptyBind", t, testLOBNullEmptyBind)
+}
+
+func testLOBNullEmptyBind(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (id int, vc varchar(10) null, txt text null, img image null)", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+
+	insert := fmt.Sprintf("insert into %s (id, vc, txt, img) values (?, ?, ?, ?)", tableName)
+
+	if _, err := db.Exec(insert, 1, nil, nil, nil); err != nil {
+		t.Errorf("Error inserting NULL values: %v", err)
+		return
+	}
+
+	if _, err := db.Exec(insert, 2, "", "", []byte{}); err != nil {
+		t.Errorf("Error inserting empty values: %v", err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s (id, vc, txt, img) values (3, '', '', 0x)", tableName)); err != nil {
+		t.Errorf("Error inserting empty literals: %v", err)
+		return
+	}
+
+	query := fmt.Sprintf("select vc, txt, img from %s where id = ?", tableName)
+
+	var vc, txt sql.NullString
+	var img []byte
+	if err := db.QueryRow(query, 1).Scan(&vc, &txt, &img); err != nil {
+		t.Errorf("Error selecting NULL values: %v", err)
+		return
+	}
+
+	if vc.Valid || txt.Valid || img != nil {
+		t.Errorf("Expected NULL values, received %#v, %#v, %#v", vc, txt, img)
+	}
+
+	if err := db.QueryRow(query, 2).Scan(&vc, &txt, &img); err != nil {
+		t.Errorf("Error selecting empty values: %v", err)
+		return
+	}
+
+	if !vc.Valid || !txt.Valid || img == nil {
+		t.Errorf("Expected empty values to be bound as non-NULL, received %#v, %#v, %#v", vc, txt, img)
+		return
+	}
+
+	var literalVC, literalTxt sql.NullString
+	if err := db.QueryRow(query, 3).Scan(&literalVC, &literalTxt, &img); err != nil {
+		t.Errorf("Error selecting empty literals: %v", err)
+		return
+	}
+
+	if vc != literalVC || txt != literalTxt {
+		t.Errorf("Expected bound empty strings %#v, %#v to equal the literals %#v, %#v",
+			vc, txt, literalVC, literalTxt)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	integration.TestForEachDB("TestWriteText", t, testWriteText)
+}
+
+func testWriteText(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (id int, txt text null, img image null)", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s (id, txt, img) values (1, 'old', 0x00)", tableName)); err != nil {
+		t.Errorf("Error inserting row: %v", err)
+		return
+	}
+
+	// Larger than a single chunk, with multibyte characters crossing
+	// chunk boundaries.
+	txt := strings.Repeat("abcé", writeTextChunkSize/2)
+	img := bytes.Repeat([]byte{0x01, 0x02, 0x03}, writeTextChunkSize)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+		ctx := context.Background()
+
+		for column, data := range map[string][]byte{"txt": []byte(txt), "img": img} {
+			ptr, err := c.TextPtr(ctx, tableName, column, "id = 1")
+			if err != nil {
+				return fmt.Errorf("error retrieving text pointer of %s: %w", column, err)
+			}
+
+			if err := c.WriteText(ctx, tableName, column, ptr, bytes.NewReader(data)); err != nil {
+				return fmt.Errorf("error writing %s: %w", column, err)
+			}
+
+			if err := c.AppendText(ctx, tableName, column, ptr, bytes.NewReader(data[:3])); err != nil {
+				return fmt.Errorf("error appending to %s: %w", column, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	var recvTxt string
+	var recvImg []byte
+	if err := db.QueryRow(fmt.Sprintf("select txt, img from %s where id = 1", tableName)).Scan(&recvTxt, &recvImg); err != nil {
+		t.Errorf("Error selecting values: %v", err)
+		return
+	}
+
+	if expect := txt + txt[:3]; recvTxt != expect {
+		t.Errorf("Expected text of length %d, received length %d", len(expect), len(recvTxt))
+	}
+
+	if expect := append(img, img[:3]...); !bytes.Equal(recvImg, expect) {
+		t.Errorf("Expected image of length %d, received length %d", len(expect), len(recvImg))
+	}
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRowLimitExceeded is returned when a result set returns more rows
+// than allowed by the property maxrows or WithMaxRows.
+var ErrRowLimitExceeded = errors.New("go-ase: result set exceeds the maximum number of rows")
+
+// maxRowsKey is the context key of the maximum number of rows set by
+// WithMaxRows.
+type maxRowsKey struct{}
+
+// WithMaxRows returns a copy of ctx overriding the property maxrows
+// for commands executed with the returned context. A limit of zero
+// disables the check.
+//
+// For queries executed through cursors the limit of the context passed
+// to the query applies.
+func WithMaxRows(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, maxRowsKey{}, n)
+}
+
+// maxRows returns the maximum number of rows per result set for
+// commands executed with ctx.
+func (c *Conn) maxRows(ctx context.Context) int {
+	if n, ok := ctx.Value(maxRowsKey{}).(int); ok {
+		return n
+	}
+	return c.Info.MaxRows
+}
@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConn_maxRows(t *testing.T) {
+	cases := map[string]struct {
+		info   int
+		ctx    context.Context
+		expect int
+	}{
+		"unset":    {0, context.Background(), 0},
+		"info":     {10, context.Background(), 10},
+		"context":  {10, WithMaxRows(context.Background(), 5), 5},
+		"disabled": {10, WithMaxRows(context.Background(), 0), 0},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{Info: &Info{MaxRows: cas.info}}
+			if n := c.maxRows(cas.ctx); n != cas.expect {
+				t.Errorf("Expected %d, received %d", cas.expect, n)
+			}
+		})
+	}
+}
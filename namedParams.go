@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMixedPlaceholders is returned when a statement uses both
+// positional (?) and named (@name) placeholders.
+var ErrMixedPlaceholders = errors.New("go-ase: positional (?) and named (@name) placeholders cannot be mixed")
+
+// parseNamedParams replaces the named placeholders (@name) in query
+// with positional placeholders and returns the rewritten query and the
+// names in the order of their positions.
+//
+// String literals, quoted identifiers, comments and global variables
+// (@@name) are skipped. Local variables declared with `declare` and
+// the parameter names of procedures in `exec proc @name = value` are
+// not placeholders either. If query does not contain named
+// placeholders it is returned as-is with nil names.
+func parseNamedParams(query string) (string, []string, error) {
+	var (
+		b          strings.Builder
+		names      []string
+		positional bool
+
+		// declaring is set while the variables of a declare
+		// statement are listed, executing while the arguments of an
+		// exec statement are listed.
+		declaring, executing bool
+		declared             = map[string]bool{}
+	)
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case c == '\'' || c == '"':
+			j := skipPast(query, i+1, string(c))
+			b.WriteString(query[i:j])
+			i = j - 1
+		case c == '[':
+			j := skipPast(query, i+1, "]")
+			b.WriteString(query[i:j])
+			i = j - 1
+		case strings.HasPrefix(query[i:], "--"):
+			j := skipPast(query, i+2, "\n")
+			b.WriteString(query[i:j])
+			i = j - 1
+		case strings.HasPrefix(query[i:], "/*"):
+			j := skipPast(query, i+2, "*/")
+			b.WriteString(query[i:j])
+			i = j - 1
+		case c == '?':
+			positional = true
+			b.WriteByte(c)
+		case c == ';':
+			declaring, executing = false, false
+			b.WriteByte(c)
+		case c == '@':
+			j := i + 1
+			global := j < len(query) && query[j] == '@'
+			if global {
+				j++
+			}
+			for j < len(query) && isIdentifierByte(query[j]) {
+				j++
+			}
+			name := query[i+1 : j]
+
+			if declaring && !global {
+				declared[name] = true
+			}
+
+			if global || j == i+1 || declared[name] || (executing && isFollowedByAssignment(query, j)) {
+				b.WriteString(query[i:j])
+			} else {
+				names = append(names, name)
+				b.WriteByte('?')
+			}
+			i = j - 1
+		case isIdentifierByte(c) && (i == 0 || !isIdentifierByte(query[i-1])):
+			j := i + 1
+			for j < len(query) && isIdentifierByte(query[j]) {
+				j++
+			}
+
+			word := strings.ToLower(query[i:j])
+			if statementKeywords[word] {
+				declaring = word == "declare"
+				executing = word == "exec" || word == "execute"
+			}
+
+			b.WriteString(query[i:j])
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	if len(names) == 0 {
+		return query, nil, nil
+	}
+
+	if positional {
+		return "", nil, ErrMixedPlaceholders
+	}
+
+	return b.String(), names, nil
+}
+
+// statementKeywords are the keywords beginning a statement, which end
+// the variable list of a declare and the argument list of an exec
+// statement.
+var statementKeywords = map[string]bool{
+	"begin": true, "commit": true, "declare": true, "delete": true,
+	"else": true, "end": true, "exec": true, "execute": true,
+	"if": true, "insert": true, "print": true, "return": true,
+	"rollback": true, "select": true, "set": true, "update": true,
+	"while": true,
+}
+
+// isFollowedByAssignment reports if the next character in s at or after
+// from that is not a whitespace is an equals sign.
+func isFollowedByAssignment(s string, from int) bool {
+	rest := strings.TrimLeft(s[from:], " \t\r\n")
+	return strings.HasPrefix(rest, "=")
+}
+
+// hasNamedArgs reports if any of args is named, e.g. passed with
+// sql.Named.
+func hasNamedArgs(args []driver.NamedValue) bool {
+	for _, arg := range args {
+		if arg.Name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdentifierByte reports if c is valid in an ASE identifier after
+// the first character.
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '#' || c == '$' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+		c >= 0x80
+}
+
+// skipPast returns the index in s after the first occurrence of end
+// at or after from or the length of s if end does not occur.
+func skipPast(s string, from int, end string) int {
+	i := strings.Index(s[from:], end)
+	if i < 0 {
+		return len(s)
+	}
+	return from + i + len(end)
+}
+
+// numNamedParams returns the number of distinct names in names.
+func numNamedParams(names []string) int {
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		seen[name] = struct{}{}
+	}
+	return len(seen)
+}
+
+// bindNamedParams returns the arguments in the order of the positions
+// of names. Arguments bound to names used multiple times are repeated.
+//
+// All arguments must be named.
+func bindNamedParams(names []string, args []driver.NamedValue) ([]driver.NamedValue, error) {
+	byName := make(map[string]driver.NamedValue, len(args))
+	for _, arg := range args {
+		if arg.Name == "" {
+			return nil, fmt.Errorf("go-ase: argument %d must be named, the statement uses named placeholders", arg.Ordinal)
+		}
+		if !containsString(names, arg.Name) {
+			return nil, fmt.Errorf("go-ase: argument %q does not match a placeholder", arg.Name)
+		}
+		byName[arg.Name] = arg
+	}
+
+	bound := make([]driver.NamedValue, len(names))
+	for i, name := range names {
+		arg, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("go-ase: no argument passed for placeholder @%s", name)
+		}
+		bound[i] = driver.NamedValue{Ordinal: i + 1, Value: arg.Value}
+	}
+
+	return bound, nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
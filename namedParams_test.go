@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseNamedParams(t *testing.T) {
+	cases := map[string]struct {
+		query       string
+		expectQuery string
+		expectNames []string
+		expectErr   error
+	}{
+		"positional": {
+			"select * from tab where a = ? and b = ?",
+			"select * from tab where a = ? and b = ?",
+			nil, nil,
+		},
+		"named": {
+			"select * from tab where a = @p1 and b = @p2",
+			"select * from tab where a = ? and b = ?",
+			[]string{"p1", "p2"}, nil,
+		},
+		"named repeated": {
+			"select * from tab where a = @p1 or b = @p1",
+			"select * from tab where a = ? or b = ?",
+			[]string{"p1", "p1"}, nil,
+		},
+		"global variable": {
+			"select @@version, @p1",
+			"select @@version, ?",
+			[]string{"p1"}, nil,
+		},
+		"literals and identifiers": {
+			"select '@a', \"@b?\", [@c], 'it''s @d' from tab where e = @e",
+			"select '@a', \"@b?\", [@c], 'it''s @d' from tab where e = ?",
+			[]string{"e"}, nil,
+		},
+		"comments": {
+			"select @a -- @b ?\n, @c /* @d ? */ from tab",
+			"select ? -- @b ?\n, ? /* @d ? */ from tab",
+			[]string{"a", "c"}, nil,
+		},
+		"unterminated literal": {
+			"select @a, '@b",
+			"select ?, '@b",
+			[]string{"a"}, nil,
+		},
+		"lone at": {
+			"select @ from tab",
+			"select @ from tab",
+			nil, nil,
+		},
+		"mixed": {
+			"select * from tab where a = ? and b = @p1",
+			"", nil, ErrMixedPlaceholders,
+		},
+		"exec positional": {
+			"exec myproc @a = ?, @b = ?",
+			"exec myproc @a = ?, @b = ?",
+			nil, nil,
+		},
+		"exec named": {
+			"execute myproc @a = @p1, @b=@p2",
+			"execute myproc @a = ?, @b=?",
+			[]string{"p1", "p2"}, nil,
+		},
+		"exec followed by statement": {
+			"exec myproc @a = 1 select * from tab where @p1 = a",
+			"exec myproc @a = 1 select * from tab where ? = a",
+			[]string{"p1"}, nil,
+		},
+		"declare": {
+			"declare @x int, @y varchar(10) select @x = ?, @y = ? select @x, @y",
+			"declare @x int, @y varchar(10) select @x = ?, @y = ? select @x, @y",
+			nil, nil,
+		},
+		"declare named": {
+			"declare @x int\nselect @x = @p1\nselect @x, @p1",
+			"declare @x int\nselect @x = ?\nselect @x, ?",
+			[]string{"p1", "p1"}, nil,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			query, names, err := parseNamedParams(cas.query)
+			if !errors.Is(err, cas.expectErr) {
+				t.Errorf("Expected error %v, received %v", cas.expectErr, err)
+				return
+			}
+
+			if query != cas.expectQuery {
+				t.Errorf("Expected query %q, received %q", cas.expectQuery, query)
+			}
+
+			if !reflect.DeepEqual(names, cas.expectNames) {
+				t.Errorf("Expected names %v, received %v", cas.expectNames, names)
+			}
+		})
+	}
+}
+
+func TestBindNamedParams(t *testing.T) {
+	cases := map[string]struct {
+		names     []string
+		args      []driver.NamedValue
+		expect    []driver.Value
+		expectErr bool
+	}{
+		"in order": {
+			[]string{"a", "b"},
+			[]driver.NamedValue{{Name: "a", Ordinal: 1, Value: 1}, {Name: "b", Ordinal: 2, Value: 2}},
+			[]driver.Value{1, 2}, false,
+		},
+		"reordered": {
+			[]string{"a", "b"},
+			[]driver.NamedValue{{Name: "b", Ordinal: 1, Value: 2}, {Name: "a", Ordinal: 2, Value: 1}},
+			[]driver.Value{1, 2}, false,
+		},
+		"repeated": {
+			[]string{"a", "b", "a"},
+			[]driver.NamedValue{{Name: "a", Ordinal: 1, Value: 1}, {Name: "b", Ordinal: 2, Value: 2}},
+			[]driver.Value{1, 2, 1}, false,
+		},
+		"unnamed": {
+			[]string{"a"},
+			[]driver.NamedValue{{Ordinal: 1, Value: 1}},
+			nil, true,
+		},
+		"missing": {
+			[]string{"a", "b"},
+			[]driver.NamedValue{{Name: "a", Ordinal: 1, Value: 1}},
+			nil, true,
+		},
+		"unknown": {
+			[]string{"a"},
+			[]driver.NamedValue{{Name: "a", Ordinal: 1, Value: 1}, {Name: "c", Ordinal: 2, Value: 3}},
+			nil, true,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			bound, err := bindNamedParams(cas.names, cas.args)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			for i, arg := range bound {
+				if arg.Ordinal != i+1 {
+					t.Errorf("Expected ordinal %d, received %d", i+1, arg.Ordinal)
+				}
+				if arg.Value != cas.expect[i] {
+					t.Errorf("Expected value %v at position %d, received %v", cas.expect[i], i, arg.Value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+// Normalizer normalizes the Unicode representation of strings.
+//
+// The normalization forms of golang.org/x/text/unicode/norm implement
+// Normalizer, e.g. norm.NFC.
+type Normalizer interface {
+	String(s string) string
+}
+
+// SetNormalization sets the normalizer applied to string parameters
+// on connections opened by the connector, e.g. norm.NFC to bind
+// composed and decomposed representations of the same text to the same
+// value in comparisons with unichar and univarchar columns.
+//
+// The normalizer must be set before the connector is used. Passing nil
+// disables normalization.
+func (c *Connector) SetNormalization(n Normalizer) {
+	c.normalizer = n
+}
+
+// normalize returns value normalized with the normalizer of the
+// connection if value is a string.
+func (c *Conn) normalize(value interface{}) interface{} {
+	if c.normalizer == nil {
+		return value
+	}
+
+	if s, ok := value.(string); ok {
+		return c.normalizer.String(s)
+	}
+
+	return value
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"strings"
+	"testing"
+)
+
+// composeNormalizer composes a decomposed "e\u0301" for testing.
+type composeNormalizer struct{}
+
+func (composeNormalizer) String(s string) string {
+	return strings.ReplaceAll(s, "e\u0301", "\u00e9")
+}
+
+func TestConn_normalize(t *testing.T) {
+	cases := map[string]struct {
+		normalizer Normalizer
+		value      interface{}
+		expect     interface{}
+	}{
+		"disabled":   {nil, "cafe\u0301", "cafe\u0301"},
+		"decomposed": {composeNormalizer{}, "cafe\u0301", "caf\u00e9"},
+		"composed":   {composeNormalizer{}, "caf\u00e9", "caf\u00e9"},
+		"non-string": {composeNormalizer{}, int64(1), int64(1)},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{normalizer: cas.normalizer}
+			if recv := c.normalize(cas.value); recv != cas.expect {
+				t.Errorf("Expected %q, received %q", cas.expect, recv)
+			}
+		})
+	}
+}
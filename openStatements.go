@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import "sort"
+
+// StmtInfo describes a dynamic statement allocated on the server.
+type StmtInfo struct {
+	// Name is the name the statement is allocated with.
+	Name string
+	// Query is the query the statement was prepared with.
+	Query string
+}
+
+// CursorInfo describes a cursor allocated on the server.
+type CursorInfo struct {
+	// ID is the ID assigned to the cursor by ASE.
+	ID int
+	// Name is the name the cursor is declared with.
+	Name string
+	// Query is the query the cursor was declared with.
+	Query string
+}
+
+// OpenStatements returns the dynamic statements allocated on the
+// server through the connection that have not been closed, sorted by
+// name.
+//
+// Statements the application does not close stay allocated on the
+// server until the connection is closed, which makes OpenStatements
+// useful to find such leaks.
+func (c *Conn) OpenStatements() []StmtInfo {
+	c.stmtLock.RLock()
+	defer c.stmtLock.RUnlock()
+
+	infos := make([]StmtInfo, 0, len(c.stmts))
+	for name, stmt := range c.stmts {
+		infos = append(infos, StmtInfo{Name: name, Query: stmt.query})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+// OpenCursors returns the cursors allocated on the server through the
+// connection that have not been closed, sorted by name.
+//
+// Cursors with arguments are additionally listed by OpenStatements
+// as their query is prepared as a dynamic statement.
+func (c *Conn) OpenCursors() []CursorInfo {
+	c.stmtLock.RLock()
+	defer c.stmtLock.RUnlock()
+
+	infos := make([]CursorInfo, 0, len(c.cursors))
+	for name, cursor := range c.cursors {
+		infos = append(infos, CursorInfo{
+			ID:    int(cursor.cursorID),
+			Name:  name,
+			Query: cursor.query,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+func (c *Conn) trackStmt(stmt *Stmt) {
+	c.stmtLock.Lock()
+	defer c.stmtLock.Unlock()
+	c.stmts[stmt.pkg.ID] = stmt
+}
+
+func (c *Conn) untrackStmt(stmt *Stmt) {
+	c.stmtLock.Lock()
+	defer c.stmtLock.Unlock()
+	delete(c.stmts, stmt.pkg.ID)
+}
+
+func (c *Conn) trackCursor(cursor *Cursor) {
+	c.stmtLock.Lock()
+	defer c.stmtLock.Unlock()
+	c.cursors[cursor.poolName.String()] = cursor
+}
+
+func (c *Conn) untrackCursor(cursor *Cursor) {
+	c.stmtLock.Lock()
+	defer c.stmtLock.Unlock()
+	delete(c.cursors, cursor.poolName.String())
+}
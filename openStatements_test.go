@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestOpenStatements(t *testing.T) {
+	conn := &Conn{
+		stmts:    map[string]*Stmt{},
+		cursors:  map[string]*Cursor{},
+		stmtLock: &sync.RWMutex{},
+	}
+
+	newStmt := func(name, query string) *Stmt {
+		stmt := &Stmt{conn: conn, query: query, pkg: tds.NewDynamicPackage(true)}
+		stmt.pkg.ID = name
+		return stmt
+	}
+
+	b := newStmt("stmt2", "select b from tab")
+	conn.trackStmt(b)
+	conn.trackStmt(newStmt("stmt1", "select a from tab"))
+
+	expect := []StmtInfo{
+		{Name: "stmt1", Query: "select a from tab"},
+		{Name: "stmt2", Query: "select b from tab"},
+	}
+	if recv := conn.OpenStatements(); !reflect.DeepEqual(recv, expect) {
+		t.Errorf("Expected %v, received %v", expect, recv)
+	}
+
+	conn.untrackStmt(b)
+
+	expect = expect[:1]
+	if recv := conn.OpenStatements(); !reflect.DeepEqual(recv, expect) {
+		t.Errorf("Expected %v after closing, received %v", expect, recv)
+	}
+
+	if recv := conn.OpenCursors(); len(recv) != 0 {
+		t.Errorf("Expected no cursors, received %v", recv)
+	}
+}
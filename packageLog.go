@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// ErrUnhandledPackage is returned when the server sends a package the
+// driver does not expect or support at this point of the
+// communication.
+//
+// If the property debugpackages is set the last packages of the
+// connection are available through Conn.LastPackages.
+var ErrUnhandledPackage = errors.New("unhandled package type")
+
+// maxDebugPackages is the maximum number of packages retained with the
+// property debugpackages.
+const maxDebugPackages = 1000
+
+// PackageRecord is a TDS package retained for diagnostics, see
+// Conn.LastPackages.
+type PackageRecord struct {
+	// Sent is set for packages sent to the server and unset for
+	// packages received from the server.
+	Sent bool
+	// Type is the Go type of the package, e.g. *tds.RowFmtPackage.
+	Type string
+	// Package is the string representation of the package.
+	Package string
+	// Data are the raw bytes of packages go-dblib could not parse,
+	// starting with the token.
+	Data []byte
+}
+
+// String implements the fmt.Stringer interface.
+func (record PackageRecord) String() string {
+	direction := "RX"
+	if record.Sent {
+		direction = "TX"
+	}
+
+	s := fmt.Sprintf("%s %s: %s", direction, record.Type, record.Package)
+	if len(record.Data) > 0 {
+		s += "\n" + hex.Dump(record.Data)
+	}
+	return s
+}
+
+// packageRing retains the last packages of a connection.
+type packageRing struct {
+	lock    *sync.Mutex
+	records []PackageRecord
+	next    int
+	full    bool
+}
+
+// newPackageRing returns a ring retaining n packages, at most
+// maxDebugPackages, or nil if n is not positive.
+func newPackageRing(n int) *packageRing {
+	if n <= 0 {
+		return nil
+	}
+
+	if n > maxDebugPackages {
+		n = maxDebugPackages
+	}
+
+	return &packageRing{
+		lock:    &sync.Mutex{},
+		records: make([]PackageRecord, n),
+	}
+}
+
+// add records pkg, replacing the oldest record if the ring is full.
+// add is a no-op on a nil ring.
+func (ring *packageRing) add(sent bool, pkg tds.Package) {
+	if ring == nil {
+		return
+	}
+
+	record := PackageRecord{
+		Sent:    sent,
+		Type:    fmt.Sprintf("%T", pkg),
+		Package: fmt.Sprintf("%v", pkg),
+	}
+
+	if tokenless, ok := pkg.(*tds.TokenlessPackage); ok && tokenless.Data != nil {
+		record.Data = append([]byte{}, tokenless.Data.Bytes()...)
+	}
+
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+
+	ring.records[ring.next] = record
+	ring.next = (ring.next + 1) % len(ring.records)
+	if ring.next == 0 {
+		ring.full = true
+	}
+}
+
+// list returns the records from oldest to newest.
+func (ring *packageRing) list() []PackageRecord {
+	if ring == nil {
+		return nil
+	}
+
+	ring.lock.Lock()
+	defer ring.lock.Unlock()
+
+	if !ring.full {
+		return append([]PackageRecord{}, ring.records[:ring.next]...)
+	}
+
+	list := make([]PackageRecord, 0, len(ring.records))
+	list = append(list, ring.records[ring.next:]...)
+	return append(list, ring.records[:ring.next]...)
+}
+
+// LastPackages returns the last packages sent to and received from
+// the server, oldest first, if the property debugpackages is set.
+//
+// Packages are recorded as go-dblib parsed them, raw bytes are only
+// available for packages it could not parse. Values of parameters
+// and rows are included, hence the records may contain sensitive
+// data.
+func (c *Conn) LastPackages() []PackageRecord {
+	return c.packages.list()
+}
+
+// unhandledPackage returns the error for the unexpected package pkg.
+//
+// The retained packages are not included as they may contain
+// sensitive data, the error only refers to Conn.LastPackages.
+func (c *Conn) unhandledPackage(pkg tds.Package) error {
+	err := fmt.Errorf("go-ase: %w %T: %v", ErrUnhandledPackage, pkg, pkg)
+
+	if n := len(c.packages.list()); n > 0 {
+		return fmt.Errorf("%w (last %d packages retained, see Conn.LastPackages)", err, n)
+	}
+
+	return err
+}
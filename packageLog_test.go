@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestPackageRing(t *testing.T) {
+	cases := map[string]struct {
+		size     int
+		added    []uint32
+		expected []uint32
+	}{
+		"disabled":   {0, []uint32{1, 2}, nil},
+		"empty":      {3, nil, []uint32{}},
+		"partial":    {3, []uint32{1, 2}, []uint32{1, 2}},
+		"full":       {3, []uint32{1, 2, 3}, []uint32{1, 2, 3}},
+		"wrapped":    {3, []uint32{1, 2, 3, 4, 5}, []uint32{3, 4, 5}},
+		"wrapped 2x": {2, []uint32{1, 2, 3, 4, 5, 6}, []uint32{5, 6}},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			ring := newPackageRing(cas.size)
+			for _, count := range cas.added {
+				ring.add(false, &tds.DonePackage{Count: int32(count)})
+			}
+
+			records := ring.list()
+			if cas.expected == nil {
+				if records != nil {
+					t.Errorf("Expected no records, received %v", records)
+				}
+				return
+			}
+
+			if len(records) != len(cas.expected) {
+				t.Fatalf("Expected %d records, received %d", len(cas.expected), len(records))
+			}
+
+			for i, count := range cas.expected {
+				expected := (&tds.DonePackage{Count: int32(count)}).String()
+				if records[i].Package != expected {
+					t.Errorf("Expected record %d to be %q, received %q", i, expected, records[i].Package)
+				}
+			}
+		})
+	}
+}
+
+func TestConn_unhandledPackage(t *testing.T) {
+	pkg := &tds.TokenlessPackage{Data: bytes.NewBuffer([]byte{0xd3, 0x01, 0x02})}
+
+	c := &Conn{}
+	err := c.unhandledPackage(pkg)
+	if !errors.Is(err, ErrUnhandledPackage) {
+		t.Errorf("Expected error to match ErrUnhandledPackage, received %v", err)
+	}
+
+	if strings.Contains(err.Error(), "LastPackages") {
+		t.Errorf("Expected no reference to retained packages without debugpackages, received %v", err)
+	}
+
+	c.packages = newPackageRing(2)
+	c.packages.add(true, &tds.LanguagePackage{Cmd: "select 1"})
+	c.packages.add(false, pkg)
+
+	records := c.LastPackages()
+	if len(records) != 2 || !records[0].Sent || records[1].Sent {
+		t.Fatalf("Unexpected records: %v", records)
+	}
+
+	if !bytes.Equal(records[1].Data, []byte{0xd3, 0x01, 0x02}) {
+		t.Errorf("Expected raw data of the tokenless package, received %x", records[1].Data)
+	}
+
+	err = c.unhandledPackage(pkg)
+	if !errors.Is(err, ErrUnhandledPackage) {
+		t.Errorf("Expected error to match ErrUnhandledPackage, received %v", err)
+	}
+
+	// The retained packages may contain sensitive data and are only
+	// available through LastPackages.
+	msg := err.Error()
+	if !strings.Contains(msg, "last 2 packages retained") {
+		t.Errorf("Expected error to refer to the retained packages, received %q", msg)
+	}
+
+	if strings.Contains(msg, "select 1") || strings.Contains(msg, "LanguagePackage") {
+		t.Errorf("Expected error not to contain the retained packages, received %q", msg)
+	}
+}
+
+func TestNewPackageRing_Max(t *testing.T) {
+	ring := newPackageRing(maxDebugPackages + 1)
+	if len(ring.records) != maxDebugPackages {
+		t.Errorf("Expected ring to retain %d packages, retains %d", maxDebugPackages, len(ring.records))
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+)
+
+// QueryFuture is the pending result of a query started by QueryAsync.
+type QueryFuture struct {
+	done chan struct{}
+	rows *sql.Rows
+	err  error
+}
+
+// QueryAsync executes query with args on a connection of db's pool in
+// a new goroutine and returns immediately, allowing to run several
+// independent queries concurrently and gather their results:
+//
+//	users := ase.QueryAsync(ctx, db, "select * from users")
+//	orders := ase.QueryAsync(ctx, db, "select * from orders where day = ?", day)
+//
+//	userRows, err := users.Wait()
+//	...
+//	orderRows, err := orders.Wait()
+//
+// Each query occupies its own connection - TDS does not multiplex
+// commands on a single connection. The connection is returned to the
+// pool when the rows returned by Wait are closed, or by database/sql
+// when ctx ends. Callers must close the rows of every future they
+// started, also if they are not interested in the result.
+//
+// Cancelling ctx cancels the query as with db.QueryContext.
+func QueryAsync(ctx context.Context, db *sql.DB, query string, args ...interface{}) *QueryFuture {
+	future := &QueryFuture{done: make(chan struct{})}
+
+	go func() {
+		defer close(future.done)
+		future.rows, future.err = db.QueryContext(ctx, query, args...)
+	}()
+
+	return future
+}
+
+// Wait blocks until the query has been executed and returns its rows
+// or the error of the execution. Wait may be called multiple times and
+// from multiple goroutines, all calls return the same rows.
+func (future *QueryFuture) Wait() (*sql.Rows, error) {
+	<-future.done
+	return future.rows, future.err
+}
+
+// Done returns a channel that is closed once the query has been
+// executed, e.g. to wait for the first of multiple futures in
+// a select statement.
+func (future *QueryFuture) Done() <-chan struct{} {
+	return future.done
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestQueryAsync(t *testing.T) {
+	integration.TestForEachDB("TestQueryAsync", t, testQueryAsync)
+}
+
+func testQueryAsync(t *testing.T, db *sql.DB, tableName string) {
+	ctx := context.Background()
+
+	futures := make([]*QueryFuture, 3)
+	for i := range futures {
+		futures[i] = QueryAsync(ctx, db, "waitfor delay '00:00:01' select ?", i)
+	}
+
+	for i, future := range futures {
+		rows, err := future.Wait()
+		if err != nil {
+			t.Errorf("Error executing query %d: %v", i, err)
+			continue
+		}
+
+		var received int
+		if !rows.Next() {
+			t.Errorf("Expected a row for query %d: %v", i, rows.Err())
+		} else if err := rows.Scan(&received); err != nil {
+			t.Errorf("Error scanning row of query %d: %v", i, err)
+		} else if received != i {
+			t.Errorf("Expected %d, received %d", i, received)
+		}
+
+		if err := rows.Close(); err != nil {
+			t.Errorf("Error closing rows of query %d: %v", i, err)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if _, err := QueryAsync(cancelled, db, "select 1").Wait(); err == nil {
+		t.Errorf("Expected error for cancelled context")
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+// QueryJSON executes query and streams the rows of the first result
+// set to w as a JSON array of objects keyed by the column names.
+//
+// Each row is written as it is read, without building the whole
+// document in memory. Values are written with the JSON type matching
+// their Go type: Integers, floats and numeric, decimal and money
+// values as numbers, bits as booleans, character values as strings,
+// binary values as base64 encoded strings and date and time values as
+// RFC 3339 strings. NULL values are written as null.
+//
+// If an error occurs after the first row was written w holds
+// a truncated document.
+func (c *Conn) QueryJSON(ctx context.Context, w io.Writer, query string, args ...interface{}) error {
+	driverRows, _, err := c.DirectExec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("go-ase: error executing query: %w", err)
+	}
+
+	rows := driverRows.(*Rows)
+	if err := writeJSONRows(w, rows); err != nil {
+		rows.Close()
+		return err
+	}
+
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("go-ase: error closing rows: %w", err)
+	}
+
+	return nil
+}
+
+// writeJSONRows writes the rows of the current result set of rows to
+// w as a JSON array of objects.
+func writeJSONRows(w io.Writer, rows *Rows) error {
+	columns := rows.Columns()
+
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return fmt.Errorf("go-ase: error encoding column name %q: %w", column, err)
+		}
+		keys[i] = key
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('[')
+
+	values := make([]driver.Value, len(columns))
+	for n := 0; ; n++ {
+		if err := rows.Next(values); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("go-ase: error reading row: %w", err)
+		}
+
+		if n > 0 {
+			bw.WriteByte(',')
+		}
+
+		if err := writeJSONObject(bw, keys, values); err != nil {
+			return fmt.Errorf("go-ase: error encoding row %d: %w", n+1, err)
+		}
+	}
+
+	bw.WriteByte(']')
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("go-ase: error writing JSON: %w", err)
+	}
+
+	return nil
+}
+
+// writeJSONObject writes values as a JSON object with the encoded keys.
+func writeJSONObject(bw *bufio.Writer, keys [][]byte, values []driver.Value) error {
+	bw.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+
+		encoded, err := jsonValue(value)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", keys[i], err)
+		}
+
+		bw.Write(keys[i])
+		bw.WriteByte(':')
+		bw.Write(encoded)
+	}
+	bw.WriteByte('}')
+
+	return nil
+}
+
+// jsonValue returns the JSON encoding of a value returned by Rows.Next.
+func jsonValue(value driver.Value) ([]byte, error) {
+	switch typed := value.(type) {
+	case nil:
+		return []byte("null"), nil
+	case *asetypes.Decimal:
+		if typed == nil {
+			return []byte("null"), nil
+		}
+		return []byte(typed.String()), nil
+	}
+
+	// encoding/json writes byte slices base64 encoded and time values
+	// in RFC 3339 format.
+	return json.Marshal(value)
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+func TestJSONValue(t *testing.T) {
+	dec, err := asetypes.NewDecimalString(10, 2, "-12.5")
+	if err != nil {
+		t.Errorf("Error creating decimal: %v", err)
+		return
+	}
+
+	cases := map[string]struct {
+		value  driver.Value
+		expect string
+	}{
+		"nil":         {nil, "null"},
+		"nil decimal": {(*asetypes.Decimal)(nil), "null"},
+		"decimal":     {dec, "-12.5"},
+		"int":         {int32(-5), "-5"},
+		"uint":        {uint64(18446744073709551615), "18446744073709551615"},
+		"float":       {float64(1.5), "1.5"},
+		"bool":        {true, "true"},
+		"string":      {"a \"b\"", `"a \"b\""`},
+		"bytes":       {[]byte{0xde, 0xad}, `"3q0="`},
+		"time":        {time.Date(2021, 2, 1, 13, 14, 15, 500000000, time.UTC), `"2021-02-01T13:14:15.5Z"`},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := jsonValue(cas.value)
+			if err != nil {
+				t.Errorf("Error encoding value: %v", err)
+				return
+			}
+
+			if string(recv) != cas.expect {
+				t.Errorf("Expected %s, received %s", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestWriteJSONObject(t *testing.T) {
+	buf := new(bytes.Buffer)
+	bw := bufio.NewWriter(buf)
+
+	keys := [][]byte{[]byte(`"id"`), []byte(`"name"`)}
+	if err := writeJSONObject(bw, keys, []driver.Value{int64(1), nil}); err != nil {
+		t.Errorf("Error writing object: %v", err)
+		return
+	}
+	bw.Flush()
+
+	if expect := `{"id":1,"name":null}`; buf.String() != expect {
+		t.Errorf("Expected %s, received %s", expect, buf.String())
+	}
+}
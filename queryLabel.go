@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+)
+
+// maxLabelLength is the maximum length of a label passed to
+// ExecLabeled, longer labels are truncated.
+const maxLabelLength = 64
+
+// ExecLabeled executes query like DirectExec with the label prepended
+// as a leading comment, e.g. `/* label: nightly-report */ select ...`.
+// The comment is part of the SQL text recorded by the server, allowing
+// DBAs to find the queries of an application in monitoring tables such
+// as monSysSQLText and the output of sp_showplan.
+//
+// The label is sanitized: characters other than letters, digits, '-',
+// '_', '.', ':' and spaces are replaced by '_' and the label is
+// truncated to 64 bytes. An empty label executes query unchanged.
+func (c *Conn) ExecLabeled(ctx context.Context, label, query string, args ...interface{}) (driver.Rows, driver.Result, error) {
+	return c.DirectExec(ctx, labelQuery(label, query), args...)
+}
+
+// labelQuery returns query with the sanitized label prepended as
+// comment.
+func labelQuery(label, query string) string {
+	label = sanitizeLabel(label)
+	if label == "" {
+		return query
+	}
+
+	return "/* label: " + label + " */ " + query
+}
+
+// sanitizeLabel returns label with all characters that could end the
+// comment or otherwise alter the query replaced.
+func sanitizeLabel(label string) string {
+	if len(label) > maxLabelLength {
+		label = label[:maxLabelLength]
+	}
+
+	label = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '-', r == '_', r == '.', r == ':', r == ' ':
+			return r
+		}
+		return '_'
+	}, label)
+
+	return strings.TrimSpace(label)
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabelQuery(t *testing.T) {
+	cases := map[string]struct {
+		label, query, expected string
+	}{
+		"plain": {
+			"nightly-report", "select 1",
+			"/* label: nightly-report */ select 1",
+		},
+		"empty": {
+			"", "select 1",
+			"select 1",
+		},
+		"comment terminator": {
+			"x */ drop table t /*", "select 1",
+			"/* label: x __ drop table t __ */ select 1",
+		},
+		"quotes and parameters": {
+			"a'b\"c?@d;", "select 1",
+			"/* label: a_b_c__d_ */ select 1",
+		},
+		"newline": {
+			"a\nb", "select 1",
+			"/* label: a_b */ select 1",
+		},
+		"multibyte": {
+			"käse", "select 1",
+			"/* label: k_se */ select 1",
+		},
+		"long": {
+			strings.Repeat("a", 100), "select 1",
+			"/* label: " + strings.Repeat("a", maxLabelLength) + " */ select 1",
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			received := labelQuery(cas.label, cas.query)
+			if received != cas.expected {
+				t.Errorf("Expected %q, received %q", cas.expected, received)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// RedactedValue replaces values masked by RedactParams.
+const RedactedValue = "<redacted>"
+
+// QueryLogger is called with every statement executed on
+// a connection and the values bound to its parameters.
+type QueryLogger func(query string, args []driver.NamedValue)
+
+// ContextQueryLogger is called like a QueryLogger with the context
+// the statement is executed with, e.g. to log the trace ID set with
+// WithTraceID.
+type ContextQueryLogger func(ctx context.Context, query string, args []driver.NamedValue)
+
+// QueryRedactor returns the value passed to the QueryLogger for
+// a parameter of the passed statement.
+type QueryRedactor func(query string, arg driver.NamedValue) driver.Value
+
+// RedactParams returns a QueryRedactor masking the values of the named
+// parameters with RedactedValue. Names are compared with and without
+// the leading '@'.
+func RedactParams(names ...string) QueryRedactor {
+	redact := make(map[string]bool, len(names))
+	for _, name := range names {
+		redact[trimParamName(name)] = true
+	}
+
+	return func(query string, arg driver.NamedValue) driver.Value {
+		if redact[trimParamName(arg.Name)] {
+			return RedactedValue
+		}
+		return arg.Value
+	}
+}
+
+func trimParamName(name string) string {
+	if len(name) > 0 && name[0] == '@' {
+		return name[1:]
+	}
+	return name
+}
+
+// queryLog passes executed statements to a QueryLogger.
+type queryLog struct {
+	logger    QueryLogger
+	ctxLogger ContextQueryLogger
+	redactor  QueryRedactor
+}
+
+// log passes the statement and its redacted arguments to the loggers.
+// Values marked by Sensitive are always redacted.
+// It is a no-op if no logger is set.
+func (l *queryLog) log(ctx context.Context, query string, args []driver.NamedValue) {
+	if l == nil || (l.logger == nil && l.ctxLogger == nil) {
+		return
+	}
+
+	if len(args) > 0 && (l.redactor != nil || hasSensitive(args)) {
+		redacted := make([]driver.NamedValue, len(args))
+		for i, arg := range args {
+			redacted[i] = arg
+			switch {
+			case isSensitive(arg.Value):
+				redacted[i].Value = RedactedValue
+			case l.redactor != nil:
+				redacted[i].Value = l.redactor(query, arg)
+			}
+		}
+		args = redacted
+	}
+
+	if l.logger != nil {
+		l.logger(query, args)
+	}
+	if l.ctxLogger != nil {
+		l.ctxLogger(ctx, query, args)
+	}
+}
+
+func isSensitive(value interface{}) bool {
+	_, ok := value.(SensitiveValue)
+	return ok
+}
+
+func hasSensitive(args []driver.NamedValue) bool {
+	for _, arg := range args {
+		if isSensitive(arg.Value) {
+			return true
+		}
+	}
+	return false
+}
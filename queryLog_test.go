@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestQueryLog(t *testing.T) {
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: "user"},
+		{Name: "password", Ordinal: 2, Value: "secret"},
+		{Name: "@pin", Ordinal: 3, Value: int64(1234)},
+	}
+
+	cases := map[string]struct {
+		redactor QueryRedactor
+		expect   []driver.Value
+	}{
+		"no redactor": {
+			nil,
+			[]driver.Value{"user", "secret", int64(1234)},
+		},
+		"redact params": {
+			RedactParams("@password", "pin"),
+			[]driver.Value{"user", RedactedValue, RedactedValue},
+		},
+		"redact ordinal": {
+			func(query string, arg driver.NamedValue) driver.Value {
+				if arg.Ordinal == 1 {
+					return "***"
+				}
+				return arg.Value
+			},
+			[]driver.Value{"***", "secret", int64(1234)},
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			var recvQuery string
+			var recv []driver.Value
+
+			l := &queryLog{
+				logger: func(query string, args []driver.NamedValue) {
+					recvQuery = query
+					for _, arg := range args {
+						recv = append(recv, arg.Value)
+					}
+				},
+				redactor: cas.redactor,
+			}
+
+			l.log(context.Background(), "select ?", args)
+
+			if recvQuery != "select ?" {
+				t.Errorf("Expected query %q, received %q", "select ?", recvQuery)
+			}
+
+			if !reflect.DeepEqual(recv, cas.expect) {
+				t.Errorf("Expected values %v, received %v", cas.expect, recv)
+			}
+
+			if args[1].Value != "secret" {
+				t.Errorf("Redaction modified the passed arguments")
+			}
+		})
+	}
+}
+
+func TestQueryLog_Unset(t *testing.T) {
+	var l *queryLog
+	l.log(context.Background(), "select 1", nil)
+
+	l = &queryLog{redactor: RedactParams("password")}
+	l.log(context.Background(), "select 1", nil)
+}
+
+func TestQueryLog_Sensitive(t *testing.T) {
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: "user"},
+		{Ordinal: 2, Value: Sensitive("secret")},
+	}
+
+	cases := map[string]struct {
+		redactor QueryRedactor
+	}{
+		"no redactor": {nil},
+		"redactor":    {func(query string, arg driver.NamedValue) driver.Value { return arg.Value }},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			var recv []driver.Value
+
+			l := &queryLog{
+				logger: func(query string, args []driver.NamedValue) {
+					for _, arg := range args {
+						recv = append(recv, arg.Value)
+					}
+				},
+				redactor: cas.redactor,
+			}
+
+			l.log(context.Background(), "select ?, ?", args)
+
+			expect := []driver.Value{"user", RedactedValue}
+			if !reflect.DeepEqual(recv, expect) {
+				t.Errorf("Expected values %v, received %v", expect, recv)
+			}
+		})
+	}
+}
+
+func TestQueryLog_Context(t *testing.T) {
+	var recvID string
+	var recvArgs []driver.NamedValue
+
+	l := &queryLog{
+		ctxLogger: func(ctx context.Context, query string, args []driver.NamedValue) {
+			recvID, _ = TraceIDFromContext(ctx)
+			recvArgs = args
+		},
+		redactor: RedactParams("password"),
+	}
+
+	args := []driver.NamedValue{{Name: "password", Ordinal: 1, Value: "secret"}}
+	l.log(WithTraceID(context.Background(), "req-1"), "select ?", args)
+
+	if recvID != "req-1" {
+		t.Errorf("Expected trace ID %q, received %q", "req-1", recvID)
+	}
+
+	if len(recvArgs) != 1 || recvArgs[0].Value != RedactedValue {
+		t.Errorf("Expected redacted arguments, received %v", recvArgs)
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// QueryPage executes query and returns the rows of the page of at
+// most limit rows starting after the first offset rows of the result
+// set, e.g. for offset 20 and limit 10 the rows 21 to 30.
+//
+// ASE does not support an OFFSET clause, hence the page is emulated
+// the same way for all releases: The result set is limited to
+// offset+limit rows through `set rowcount`, see ExecWithRowLimit, and
+// the first offset rows are read and discarded before the rows are
+// returned. Skipped rows are still transmitted by the server - for
+// large offsets keyset pagination, i.e. `where id > ?` with the last
+// key of the previous page, is considerably cheaper.
+//
+// The query should be ordered by a unique key, otherwise the server
+// may return rows in a different order for each page.
+func (c *Conn) QueryPage(ctx context.Context, query string, offset, limit int, args ...interface{}) (*Rows, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("go-ase: invalid page offset %d", offset)
+	}
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("go-ase: invalid page limit %d", limit)
+	}
+
+	driverRows, _, err := c.ExecWithRowLimit(ctx, query, offset+limit, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, ok := driverRows.(*Rows)
+	if !ok {
+		driverRows.Close()
+		return nil, fmt.Errorf("go-ase: query returned %T instead of rows", driverRows)
+	}
+
+	if err := skipRows(rows, offset); err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// skipRows reads and discards up to n rows of the current result set.
+func skipRows(rows *Rows, n int) error {
+	values := make([]driver.Value, len(rows.Columns()))
+	for i := 0; i < n; i++ {
+		if err := rows.Next(values); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("go-ase: error skipping row %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import "strings"
+
+// QuoteIdentifier quotes an identifier for use in dynamically built
+// SQL statements, e.g. for table or column names, which cannot be
+// passed as parameters.
+//
+// The identifier is enclosed in brackets and closing brackets in the
+// identifier are doubled. Brackets are recognized by ASE independent
+// of the quoted_identifier setting of the connection.
+//
+// Only a single identifier is quoted - the parts of multi-part names
+// must be quoted separately:
+//
+//	ase.QuoteIdentifier("dbo") + "." + ase.QuoteIdentifier("my table")
+func QuoteIdentifier(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// QuoteLiteral quotes a string literal for use in dynamically built
+// SQL statements.
+//
+// The literal is enclosed in single quotes and single quotes in the
+// literal are doubled.
+//
+// Whenever possible values should be passed as parameters instead.
+func QuoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import "testing"
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"table":         "[table]",
+		"my table":      "[my table]",
+		"tab]le":        "[tab]]le]",
+		"[table]":       "[[table]]]",
+		`tab"le`:        `[tab"le]`,
+		"t]; drop x --": "[t]]; drop x --]",
+	}
+
+	for input, expect := range cases {
+		if recv := QuoteIdentifier(input); recv != expect {
+			t.Errorf("Expected QuoteIdentifier(%q) to return %q, received %q", input, expect, recv)
+		}
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	cases := map[string]string{
+		"":                    "''",
+		"value":               "'value'",
+		"O'Brien":             "'O''Brien'",
+		"''":                  "''''''",
+		"x'; drop table y --": "'x''; drop table y --'",
+	}
+
+	for input, expect := range cases {
+		if recv := QuoteLiteral(input); recv != expect {
+			t.Errorf("Expected QuoteLiteral(%q) to return %q, received %q", input, expect, recv)
+		}
+	}
+}
@@ -8,22 +8,175 @@ package ase
 import (
 	"database/sql/driver"
 	"errors"
+	"fmt"
+
+	"github.com/SAP/go-dblib/tds"
 )
 
 // Interface satisfaction checks
-var _ driver.Result = (*Result)(nil)
+var (
+	_ driver.Result = (*Result)(nil)
+
+	// ErrRowsAffectedUnavailable is returned by RowsAffected if the
+	// server did not report the number of affected rows because
+	// `set nocount on` is active, see Conn.NoCount.
+	ErrRowsAffectedUnavailable = errors.New("go-ase: number of affected rows is unavailable while nocount is on")
+)
 
 // Result implements the driver.Result interface.
 type Result struct {
 	rowsAffected int64
+	// countUnavailable is set if no count was reported while nocount
+	// was on.
+	countUnavailable bool
+	lastInsertId int64
+	// outputParams are the output parameters in the order they were
+	// received, with the name sent by the server.
+	outputParams []driver.NamedValue
+	// declaredOutputs are the output parameters passed to SendRPC,
+	// with the ordinal of their position in the passed parameters.
+	declaredOutputs []driver.NamedValue
+	// outputDests are the destinations output parameters are written
+	// to, see Conn.CallProc.
+	outputDests map[string]interface{}
 }
 
 // LastInsertId implements the driver.Result interface.
+//
+// The identity value of an inserted row is only available if the
+// property lastinsertid is set.
 func (result Result) LastInsertId() (int64, error) {
+	if result.lastInsertId != 0 {
+		return result.lastInsertId, nil
+	}
 	return -1, errors.New("not supported")
 }
 
 // RowsAffected implements the driver.Result interface.
+//
+// ErrRowsAffectedUnavailable is returned if the command did not report
+// a count while `set nocount on` was active.
 func (result Result) RowsAffected() (int64, error) {
+	if result.countUnavailable {
+		return 0, ErrRowsAffectedUnavailable
+	}
 	return result.rowsAffected, nil
 }
+
+// OutputParams returns the output parameters returned by a stored
+// procedure in the order they were sent by the server, with the
+// parameter names including the leading @.
+//
+// For procedures invoked with SendRPC or CallProc the ordinals are the
+// positions of the parameters in the passed parameters. Output
+// parameters the server returns without a name, e.g. when they were
+// passed by position, are matched to the passed output parameters by
+// their position.
+//
+// Output parameters are sent after all result sets, hence they are
+// only available once the rows were consumed or closed.
+func (result Result) OutputParams() []driver.NamedValue {
+	params := make([]driver.NamedValue, len(result.outputParams))
+	for i := range result.outputParams {
+		params[i] = result.outputParam(i)
+	}
+	return params
+}
+
+// OutputParam returns the value of the output parameter name. The
+// leading @ is optional.
+func (result Result) OutputParam(name string) (driver.Value, bool) {
+	name = procParamName(name)
+
+	for i := range result.outputParams {
+		if param := result.outputParam(i); param.Name == name {
+			return param.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// outputParam returns the i-th received output parameter matched to
+// the declared output parameters.
+func (result Result) outputParam(i int) driver.NamedValue {
+	param := result.outputParams[i]
+
+	if param.Name == "" {
+		if i < len(result.declaredOutputs) {
+			param.Name = result.declaredOutputs[i].Name
+			param.Ordinal = result.declaredOutputs[i].Ordinal
+		}
+		return param
+	}
+
+	for _, declared := range result.declaredOutputs {
+		if declared.Name == param.Name {
+			param.Ordinal = declared.Ordinal
+			break
+		}
+	}
+
+	return param
+}
+
+// addOutputParams records the values of output parameters and writes
+// them to their destinations.
+func (result *Result) addOutputParams(info *Info, params *tds.ParamsPackage) error {
+	for _, field := range params.DataFields {
+		result.outputParams = append(result.outputParams, driver.NamedValue{
+			Name:    field.Format().Name(),
+			Ordinal: len(result.outputParams) + 1,
+			Value:   resultValue(info, field),
+		})
+
+		param := result.outputParam(len(result.outputParams) - 1)
+		if dest, ok := result.outputDests[param.Name]; ok {
+			if err := assignOutput(dest, param.Value); err != nil {
+				return fmt.Errorf("go-ase: error writing output parameter %s: %w", param.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// declareOutputParams records the output parameters passed with the
+// command.
+func (result *Result) declareOutputParams(params []Param) {
+	result.declaredOutputs = nil
+
+	for i, param := range params {
+		if !param.Output {
+			continue
+		}
+
+		name := ""
+		if param.Name != "" {
+			name = procParamName(param.Name)
+		}
+
+		result.declaredOutputs = append(result.declaredOutputs, driver.NamedValue{Name: name, Ordinal: i + 1})
+	}
+}
+
+// setOutputDests sets the destinations of output parameters and
+// writes the values of already received output parameters.
+func (result *Result) setOutputDests(dests map[string]interface{}) error {
+	result.outputDests = dests
+
+	for i := range result.outputParams {
+		param := result.outputParam(i)
+
+		dest, ok := dests[param.Name]
+		if !ok {
+			continue
+		}
+
+		if err := assignOutput(dest, param.Value); err != nil {
+			return fmt.Errorf("error writing output parameter %s: %w", param.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ResultIterator iterates over the rows of a result set like
+// *sql.Rows, for code using an *ase.Conn directly, e.g. through
+// sql.Conn.Raw:
+//
+//	it, err := conn.Query(ctx, "select a, b from tab where a > ?", 10)
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//
+//	for it.Next() {
+//		var a int
+//		var b string
+//		if err := it.Scan(&a, &b); err != nil {
+//			return err
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type ResultIterator struct {
+	rows   driver.Rows
+	values []driver.Value
+	err    error
+	closed bool
+}
+
+// NewResultIterator returns an iterator over rows.
+func NewResultIterator(rows driver.Rows) *ResultIterator {
+	return &ResultIterator{
+		rows:   rows,
+		values: make([]driver.Value, len(rows.Columns())),
+	}
+}
+
+// Query executes query with args and returns an iterator over the rows
+// of the first result set, see ResultIterator.
+func (c *Conn) Query(ctx context.Context, query string, args ...interface{}) (*ResultIterator, error) {
+	rows, _, err := c.DirectExec(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResultIterator(rows), nil
+}
+
+// Next reads the next row for Scan. It returns false when no rows are
+// left or an error occurred, see Err. The iterator is closed once
+// Next returns false.
+func (it *ResultIterator) Next() bool {
+	if it.closed {
+		return false
+	}
+
+	if err := it.rows.Next(it.values); err != nil {
+		if !errors.Is(err, io.EOF) {
+			it.err = err
+		}
+		it.Close()
+		return false
+	}
+
+	return true
+}
+
+// Columns returns the names of the columns.
+func (it *ResultIterator) Columns() []string {
+	return it.rows.Columns()
+}
+
+// Scan copies the values of the current row into dest like
+// sql.Rows.Scan. dest must have as many elements as the row has
+// columns.
+//
+// Supported destinations are implementations of sql.Scanner and
+// pointers to *interface{}, strings, byte slices, integers, floats,
+// bools and time.Time. Pointers to pointers are set to nil for NULL
+// values.
+func (it *ResultIterator) Scan(dest ...interface{}) error {
+	if it.closed {
+		return errors.New("go-ase: Scan called on closed iterator")
+	}
+
+	if len(dest) != len(it.values) {
+		return fmt.Errorf("go-ase: expected %d destination arguments in Scan, received %d", len(it.values), len(dest))
+	}
+
+	for i, value := range it.values {
+		if err := scanValue(dest[i], value); err != nil {
+			return fmt.Errorf("go-ase: error scanning column %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Err returns the error that ended the iteration, if any.
+func (it *ResultIterator) Err() error {
+	return it.err
+}
+
+// Close closes the rows. Calling Close again is a no-op.
+func (it *ResultIterator) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+
+	if err := it.rows.Close(); err != nil {
+		if it.err == nil {
+			it.err = err
+		}
+		return err
+	}
+
+	return nil
+}
+
+// scanValue writes value to dest.
+func scanValue(dest interface{}, value driver.Value) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("destination must be a non-nil pointer, received %T", dest)
+	}
+	dv = dv.Elem()
+
+	switch d := dest.(type) {
+	case *interface{}:
+		if b, ok := value.([]byte); ok {
+			value = append([]byte{}, b...)
+		}
+		*d = value
+		return nil
+	case *[]byte:
+		switch v := value.(type) {
+		case nil:
+			*d = nil
+			return nil
+		case []byte:
+			*d = append([]byte{}, v...)
+			return nil
+		case string:
+			*d = []byte(v)
+			return nil
+		}
+	case *time.Time:
+		if t, ok := value.(time.Time); ok {
+			*d = t
+			return nil
+		}
+	}
+
+	if dv.Kind() == reflect.Ptr {
+		if value == nil {
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+
+		target := reflect.New(dv.Type().Elem())
+		if err := scanValue(target.Interface(), value); err != nil {
+			return err
+		}
+		dv.Set(target)
+		return nil
+	}
+
+	if value == nil {
+		return fmt.Errorf("cannot scan NULL into %T", dest)
+	}
+
+	s := scanString(value)
+
+	switch dv.Kind() {
+	case reflect.String:
+		dv.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, dv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot scan %v (type %T) into %T: %w", value, value, dest, err)
+		}
+		dv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, dv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot scan %v (type %T) into %T: %w", value, value, dest, err)
+		}
+		dv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, dv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot scan %v (type %T) into %T: %w", value, value, dest, err)
+		}
+		dv.SetFloat(f)
+		return nil
+	case reflect.Bool:
+		b, err := driver.Bool.ConvertValue(value)
+		if err != nil {
+			return fmt.Errorf("cannot scan %v (type %T) into %T: %w", value, value, dest, err)
+		}
+		dv.SetBool(b.(bool))
+		return nil
+	}
+
+	return fmt.Errorf("cannot scan %v (type %T) into %T", value, value, dest)
+}
+
+// scanString returns the string representation of value used to
+// convert it to the type of a destination.
+func scanString(value driver.Value) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	return fmt.Sprint(value)
+}
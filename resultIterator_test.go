@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	err     error
+	closed  int
+}
+
+func (rows *fakeRows) Columns() []string {
+	return rows.columns
+}
+
+func (rows *fakeRows) Close() error {
+	rows.closed++
+	return nil
+}
+
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if len(rows.rows) == 0 {
+		if rows.err != nil {
+			return rows.err
+		}
+		return io.EOF
+	}
+
+	copy(dest, rows.rows[0])
+	rows.rows = rows.rows[1:]
+	return nil
+}
+
+func TestResultIterator(t *testing.T) {
+	rows := &fakeRows{
+		columns: []string{"a", "b"},
+		rows:    [][]driver.Value{{int64(1), "one"}, {int64(2), "two"}},
+	}
+
+	it := NewResultIterator(rows)
+
+	var as []int
+	var bs []string
+	for it.Next() {
+		var a int
+		var b string
+		if err := it.Scan(&a, &b); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		as = append(as, a)
+		bs = append(bs, b)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(as) != 2 || as[0] != 1 || as[1] != 2 || bs[0] != "one" || bs[1] != "two" {
+		t.Errorf("Unexpected values: %v %v", as, bs)
+	}
+
+	if rows.closed != 1 {
+		t.Errorf("Expected rows to be closed once, closed %d times", rows.closed)
+	}
+
+	if err := it.Close(); err != nil || rows.closed != 1 {
+		t.Errorf("Expected repeated Close to be a no-op")
+	}
+
+	if err := it.Scan(new(int), new(string)); err == nil {
+		t.Errorf("Expected error scanning closed iterator")
+	}
+}
+
+func TestResultIterator_Err(t *testing.T) {
+	expected := errors.New("read failed")
+	rows := &fakeRows{
+		columns: []string{"a"},
+		rows:    [][]driver.Value{{int64(1)}},
+		err:     expected,
+	}
+
+	it := NewResultIterator(rows)
+	n := 0
+	for it.Next() {
+		n++
+	}
+
+	if n != 1 {
+		t.Errorf("Expected 1 row, received %d", n)
+	}
+
+	if !errors.Is(it.Err(), expected) {
+		t.Errorf("Expected error %v, received %v", expected, it.Err())
+	}
+
+	if rows.closed != 1 {
+		t.Errorf("Expected rows to be closed")
+	}
+}
+
+func TestResultIterator_Scan(t *testing.T) {
+	now := time.Now()
+
+	cases := map[string]struct {
+		value    driver.Value
+		dest     func() interface{}
+		expected interface{}
+		err      bool
+	}{
+		"int64 to int": {
+			value:    int64(5),
+			dest:     func() interface{} { return new(int) },
+			expected: 5,
+		},
+		"int64 overflowing int8": {
+			value: int64(300),
+			dest:  func() interface{} { return new(int8) },
+			err:   true,
+		},
+		"string to int": {
+			value:    "42",
+			dest:     func() interface{} { return new(int32) },
+			expected: int32(42),
+		},
+		"negative to uint": {
+			value: int64(-1),
+			dest:  func() interface{} { return new(uint) },
+			err:   true,
+		},
+		"float64 to float32": {
+			value:    float64(1.5),
+			dest:     func() interface{} { return new(float32) },
+			expected: float32(1.5),
+		},
+		"int to string": {
+			value:    int32(7),
+			dest:     func() interface{} { return new(string) },
+			expected: "7",
+		},
+		"bytes to string": {
+			value:    []byte("abc"),
+			dest:     func() interface{} { return new(string) },
+			expected: "abc",
+		},
+		"string to bytes": {
+			value:    "abc",
+			dest:     func() interface{} { return new([]byte) },
+			expected: []byte("abc"),
+		},
+		"bool": {
+			value:    true,
+			dest:     func() interface{} { return new(bool) },
+			expected: true,
+		},
+		"time": {
+			value:    now,
+			dest:     func() interface{} { return new(time.Time) },
+			expected: now,
+		},
+		"interface": {
+			value:    int16(3),
+			dest:     func() interface{} { return new(interface{}) },
+			expected: int16(3),
+		},
+		"scanner": {
+			value:    "abc",
+			dest:     func() interface{} { return new(sql.NullString) },
+			expected: sql.NullString{String: "abc", Valid: true},
+		},
+		"null to scanner": {
+			value:    nil,
+			dest:     func() interface{} { return new(sql.NullString) },
+			expected: sql.NullString{},
+		},
+		"null to pointer": {
+			value:    nil,
+			dest:     func() interface{} { p := new(int); return &p },
+			expected: (*int)(nil),
+		},
+		"value to pointer": {
+			value:    int64(9),
+			dest:     func() interface{} { return new(*int) },
+			expected: 9,
+		},
+		"null to int": {
+			value: nil,
+			dest:  func() interface{} { return new(int) },
+			err:   true,
+		},
+		"no pointer": {
+			value: int64(1),
+			dest:  func() interface{} { return 0 },
+			err:   true,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			it := NewResultIterator(&fakeRows{
+				columns: []string{"a"},
+				rows:    [][]driver.Value{{cas.value}},
+			})
+			defer it.Close()
+
+			if !it.Next() {
+				t.Fatalf("Expected a row: %v", it.Err())
+			}
+
+			dest := cas.dest()
+			err := it.Scan(dest)
+			if cas.err {
+				if err == nil {
+					t.Errorf("Expected error, received nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			received := deref(dest)
+			if !equalValues(received, cas.expected) {
+				t.Errorf("Expected %#v, received %#v", cas.expected, received)
+			}
+		})
+	}
+}
+
+// deref returns the value dest points to. Non-nil pointers to pointers
+// are dereferenced twice.
+func deref(dest interface{}) interface{} {
+	switch d := dest.(type) {
+	case *int:
+		return *d
+	case **int:
+		if *d == nil {
+			return (*int)(nil)
+		}
+		return **d
+	case *int8:
+		return *d
+	case *int32:
+		return *d
+	case *uint:
+		return *d
+	case *float32:
+		return *d
+	case *string:
+		return *d
+	case *[]byte:
+		return *d
+	case *bool:
+		return *d
+	case *time.Time:
+		return *d
+	case *interface{}:
+		return *d
+	case *sql.NullString:
+		return *d
+	}
+	return dest
+}
+
+func equalValues(a, b interface{}) bool {
+	if ab, ok := a.([]byte); ok {
+		bb, ok := b.([]byte)
+		return ok && string(ab) == string(bb)
+	}
+	if at, ok := a.(time.Time); ok {
+		bt, ok := b.(time.Time)
+		return ok && at.Equal(bt)
+	}
+	return a == b
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestResult_OutputParams(t *testing.T) {
+	cases := map[string]struct {
+		declared []Param
+		received []driver.NamedValue
+		expect   []driver.NamedValue
+	}{
+		"language command": {
+			received: []driver.NamedValue{{Name: "@a", Ordinal: 1, Value: 1}, {Name: "@b", Ordinal: 2, Value: 2}},
+			expect:   []driver.NamedValue{{Name: "@a", Ordinal: 1, Value: 1}, {Name: "@b", Ordinal: 2, Value: 2}},
+		},
+		"by name": {
+			declared: []Param{{Name: "in", Value: 1}, {Name: "c", Output: true}, {Name: "@a", Output: true}, {Name: "b", Output: true}},
+			received: []driver.NamedValue{{Name: "@a", Ordinal: 1, Value: 1}, {Name: "@b", Ordinal: 2, Value: 2}, {Name: "@c", Ordinal: 3, Value: 3}},
+			expect:   []driver.NamedValue{{Name: "@a", Ordinal: 3, Value: 1}, {Name: "@b", Ordinal: 4, Value: 2}, {Name: "@c", Ordinal: 2, Value: 3}},
+		},
+		"by position": {
+			declared: []Param{{Value: 1}, {Output: true}, {Name: "@b", Output: true}},
+			received: []driver.NamedValue{{Ordinal: 1, Value: 1}, {Ordinal: 2, Value: 2}},
+			expect:   []driver.NamedValue{{Ordinal: 2, Value: 1}, {Name: "@b", Ordinal: 3, Value: 2}},
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			result := &Result{outputParams: cas.received}
+			result.declareOutputParams(cas.declared)
+
+			if recv := result.OutputParams(); !reflect.DeepEqual(recv, cas.expect) {
+				t.Errorf("Expected %v, received %v", cas.expect, recv)
+			}
+
+			for _, param := range cas.expect {
+				if param.Name == "" {
+					continue
+				}
+
+				value, ok := result.OutputParam(param.Name[1:])
+				if !ok || value != param.Value {
+					t.Errorf("Expected %s to be %v, received %v", param.Name, param.Value, value)
+				}
+			}
+		})
+	}
+}
+
+func TestResult_RowsAffected(t *testing.T) {
+	cases := map[string]struct {
+		result    Result
+		expect    int64
+		expectErr error
+	}{
+		"count":       {Result{rowsAffected: 3}, 3, nil},
+		"zero":        {Result{}, 0, nil},
+		"unavailable": {Result{countUnavailable: true}, 0, ErrRowsAffectedUnavailable},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			count, err := cas.result.RowsAffected()
+			if !errors.Is(err, cas.expectErr) {
+				t.Errorf("Expected error %v, received %v", cas.expectErr, err)
+				return
+			}
+
+			if count != cas.expect {
+				t.Errorf("Expected %d, received %d", cas.expect, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetryPolicy defines how often and after which delay statements
+// failing with a retryable error are executed again, see
+// Connector.SetRetryableErrors.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of executions of a statement,
+	// including the first. Values below 2 disable retries.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It is doubled for
+	// each following retry.
+	Backoff time.Duration
+	// MaxBackoff limits the delay between retries if greater than
+	// zero.
+	MaxBackoff time.Duration
+}
+
+// backoff returns the delay before the retry following attempt.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.Backoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if policy.MaxBackoff > 0 && delay >= policy.MaxBackoff {
+			break
+		}
+	}
+
+	if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+		return policy.MaxBackoff
+	}
+	return delay
+}
+
+// retryConfig holds the retryable errors set on a connector.
+type retryConfig struct {
+	msgNumbers map[uint32]bool
+	policy     RetryPolicy
+}
+
+// SetRetryableErrors makes connections opened by the connector execute
+// statements that failed with one of the passed message numbers again
+// according to policy, e.g. for deadlocks (1205), lock shortages
+// (1204) or databases that are temporarily unavailable (921):
+//
+//	connector.SetRetryableErrors([]int32{1205, 1204, 921}, ase.RetryPolicy{
+//		MaxAttempts: 3,
+//		Backoff:     100 * time.Millisecond,
+//	})
+//
+// Statements are only retried if no transaction was open when they
+// were executed, as the server may have rolled back the preceding
+// statements of the transaction. Connections in chained mode are
+// never retried for the same reason. Only errors reported before the
+// first row of a result set are retried, errors returned while reading
+// rows are not.
+//
+// The retryable errors must be set before the connector is used.
+// Passing no message numbers disables retries.
+func (c *Connector) SetRetryableErrors(msgNumbers []int32, policy RetryPolicy) {
+	if len(msgNumbers) == 0 {
+		c.retry = nil
+		return
+	}
+
+	c.retry = &retryConfig{
+		msgNumbers: make(map[uint32]bool, len(msgNumbers)),
+		policy:     policy,
+	}
+
+	for _, msgNumber := range msgNumbers {
+		c.retry.msgNumbers[uint32(msgNumber)] = true
+	}
+}
+
+// isRetryable reports if err was reported by the server with one of the
+// retryable message numbers.
+func (config *retryConfig) isRetryable(err error) bool {
+	var aseErr *Error
+	if !errors.As(err, &aseErr) {
+		return false
+	}
+
+	for _, msg := range aseErr.Messages {
+		if config.msgNumbers[msg.MsgNumber] {
+			return true
+		}
+	}
+	return config.msgNumbers[aseErr.MsgNumber]
+}
+
+// canRetry reports if statements may be retried on the connection,
+// which is only the case outside of transactions.
+func (c *Conn) canRetry() bool {
+	if c.retry == nil || c.retry.policy.MaxAttempts < 2 || c.Info.Chained {
+		return false
+	}
+
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	return !c.inTransaction && c.txDepth == 0 && !c.txAborted
+}
+
+// withRetry calls exec and calls it again according to the retry
+// policy of the connection while it fails with a retryable error.
+func (c *Conn) withRetry(ctx context.Context, exec func() (driver.Rows, driver.Result, error)) (driver.Rows, driver.Result, error) {
+	if !c.canRetry() {
+		return exec()
+	}
+
+	for attempt := 1; ; attempt++ {
+		rows, result, err := exec()
+		if err != nil {
+			// No transaction was open, hence a rollback after
+			// a deadlock must not be reported to the next
+			// transaction and must not prevent further retries.
+			c.takeTxAborted()
+		}
+
+		if err == nil || attempt >= c.retry.policy.MaxAttempts || !c.retry.isRetryable(err) {
+			return rows, result, err
+		}
+
+		timer := time.NewTimer(c.retry.policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, fmt.Errorf("go-ase: context ended before retrying after %d attempts (%v): %w", attempt, err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	cases := map[string]struct {
+		policy  RetryPolicy
+		attempt int
+		expect  time.Duration
+	}{
+		"first":     {RetryPolicy{Backoff: time.Second}, 1, time.Second},
+		"doubled":   {RetryPolicy{Backoff: time.Second}, 3, 4 * time.Second},
+		"limited":   {RetryPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second}, 3, 3 * time.Second},
+		"unlimited": {RetryPolicy{Backoff: time.Second, MaxBackoff: 0}, 5, 16 * time.Second},
+		"zero":      {RetryPolicy{}, 3, 0},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := cas.policy.backoff(cas.attempt); recv != cas.expect {
+				t.Errorf("Expected %v, received %v", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestConn_withRetry(t *testing.T) {
+	deadlock := &Error{MsgNumber: msgDeadlockVictim}
+	lockShortage := fmt.Errorf("go-ase: error executing statement: %w",
+		&Error{MsgNumber: 3621, Messages: []*tds.EEDPackage{{MsgNumber: 1204}, {MsgNumber: 3621}}})
+	syntaxErr := &Error{MsgNumber: 102}
+
+	cases := map[string]struct {
+		errs          []error
+		maxAttempts   int
+		chained       bool
+		inTransaction bool
+		expectCalls   int
+		expectErr     error
+	}{
+		"success":            {[]error{nil}, 3, false, false, 1, nil},
+		"retried":            {[]error{deadlock, nil}, 3, false, false, 2, nil},
+		"additional message": {[]error{lockShortage, nil}, 3, false, false, 2, nil},
+		"not retryable":      {[]error{syntaxErr, nil}, 3, false, false, 1, syntaxErr},
+		"attempts exceeded":  {[]error{deadlock, deadlock, deadlock, nil}, 3, false, false, 3, deadlock},
+		"disabled":           {[]error{deadlock, nil}, 1, false, false, 1, deadlock},
+		"chained":            {[]error{deadlock, nil}, 3, true, false, 1, deadlock},
+		"in transaction":     {[]error{deadlock, nil}, 3, false, true, 1, deadlock},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			connector := &Connector{}
+			connector.SetRetryableErrors([]int32{1205, 1204}, RetryPolicy{MaxAttempts: cas.maxAttempts, Backoff: time.Millisecond})
+
+			c := &Conn{
+				Info:          &Info{Chained: cas.chained},
+				sessionLock:   &sync.Mutex{},
+				inTransaction: cas.inTransaction,
+				retry:         connector.retry,
+			}
+
+			calls := 0
+			_, _, err := c.withRetry(context.Background(), func() (driver.Rows, driver.Result, error) {
+				err := cas.errs[calls]
+				calls++
+				return nil, nil, err
+			})
+
+			if !errors.Is(err, cas.expectErr) {
+				t.Errorf("Expected error %v, received %v", cas.expectErr, err)
+			}
+
+			if calls != cas.expectCalls {
+				t.Errorf("Expected %d calls, received %d", cas.expectCalls, calls)
+			}
+		})
+	}
+}
+
+func TestConn_withRetry_Context(t *testing.T) {
+	connector := &Connector{}
+	connector.SetRetryableErrors([]int32{1205}, RetryPolicy{MaxAttempts: 3, Backoff: time.Hour})
+
+	c := &Conn{Info: &Info{}, sessionLock: &sync.Mutex{}, retry: connector.retry}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	_, _, err := c.withRetry(ctx, func() (driver.Rows, driver.Result, error) {
+		calls++
+		return nil, nil, &Error{MsgNumber: msgDeadlockVictim}
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, received %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call, received %d", calls)
+	}
+}
+
+func TestConn_withRetry_TxAborted(t *testing.T) {
+	connector := &Connector{}
+	connector.SetRetryableErrors([]int32{1205}, RetryPolicy{MaxAttempts: 2})
+
+	c := &Conn{Info: &Info{}, sessionLock: &sync.Mutex{}, retry: connector.retry}
+
+	exec := func() (driver.Rows, driver.Result, error) {
+		c.abortTransaction()
+		return nil, nil, &Error{MsgNumber: msgDeadlockVictim, Messages: []*tds.EEDPackage{{MsgNumber: msgDeadlockVictim}}}
+	}
+
+	for i := 0; i < 2; i++ {
+		if !c.canRetry() {
+			t.Errorf("Expected statement %d to be retryable", i+1)
+		}
+
+		if _, _, err := c.withRetry(context.Background(), exec); !errors.Is(err, ErrDeadlockVictim) {
+			t.Errorf("Expected ErrDeadlockVictim, received %v", err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+)
+
+// RowCount returns @@rowcount, the number of rows affected or returned
+// by the last statement executed on the connection, e.g. after
+// a query whose driver.Result is not available.
+//
+// @@rowcount is a session variable - RowCount must be called on the
+// same connection directly after the statement, with database/sql
+// through sql.Conn.Raw. Rows of a query must be read completely and
+// closed before. Any command executed in between overwrites the value,
+// including commands the driver sends on its own, e.g. for the
+// properties lastinsertid and traceid or to reset a row limit set by
+// ExecWithRowLimit.
+func (c *Conn) RowCount(ctx context.Context) (int64, error) {
+	rows, _, err := c.language(ctx, "select convert(bigint, @@rowcount)")
+	if err != nil {
+		return 0, fmt.Errorf("go-ase: error selecting @@rowcount: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return 0, fmt.Errorf("go-ase: error reading @@rowcount: %w", err)
+	}
+
+	count, ok := values[0].(int64)
+	if !ok {
+		return 0, fmt.Errorf("go-ase: received unexpected @@rowcount %v of type %T", values[0], values[0])
+	}
+
+	return count, nil
+}
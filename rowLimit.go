@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+)
+
+// ExecWithRowLimit executes query like DirectExec with the number of
+// rows affected by it limited to limit through `set rowcount`, e.g.
+// to preview the first rows of a result set. A limit of zero does not
+// limit the rows.
+//
+// The limit is reset once the returned rows are closed, or right away
+// if the command fails or does not return rows.
+func (c *Conn) ExecWithRowLimit(ctx context.Context, query string, limit int, args ...interface{}) (driver.Rows, driver.Result, error) {
+	if limit < 0 {
+		return nil, nil, fmt.Errorf("go-ase: invalid row limit %d", limit)
+	}
+
+	if err := c.setRowCount(ctx, limit); err != nil {
+		return nil, nil, err
+	}
+	c.rowLimit = limit > 0
+
+	rows, result, err := c.DirectExec(ctx, query, args...)
+	if err != nil {
+		if resetErr := c.resetRowLimit(ctx); resetErr != nil {
+			return nil, nil, fmt.Errorf("%w (%v)", err, resetErr)
+		}
+		return nil, nil, err
+	}
+
+	if typed, ok := rows.(*Rows); ok && typed.finished {
+		if err := c.resetRowLimit(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return rows, result, nil
+}
+
+// resetRowLimit resets the row limit set by ExecWithRowLimit.
+func (c *Conn) resetRowLimit(ctx context.Context) error {
+	if !c.rowLimit {
+		return nil
+	}
+
+	// Closing the rows of `set rowcount` resets the row limit as
+	// well, hence the flag is cleared beforehand.
+	c.rowLimit = false
+	if err := c.setRowCount(ctx, 0); err != nil {
+		c.rowLimit = true
+		return err
+	}
+
+	return nil
+}
+
+// setRowCount executes `set rowcount` with count.
+func (c *Conn) setRowCount(ctx context.Context, count int) error {
+	rows, _, err := c.language(ctx, fmt.Sprintf("set rowcount %d", count))
+	if err != nil {
+		return fmt.Errorf("go-ase: error setting rowcount: %w", err)
+	}
+
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("go-ase: error closing rows from setting rowcount: %w", err)
+	}
+
+	return nil
+}
@@ -11,7 +11,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf16"
 
+	"github.com/SAP/go-dblib/asetypes"
 	"github.com/SAP/go-dblib/tds"
 )
 
@@ -21,6 +26,12 @@ var (
 	_ driver.RowsNextResultSet              = (*Rows)(nil)
 	_ driver.RowsColumnTypeLength           = (*Rows)(nil)
 	_ driver.RowsColumnTypeDatabaseTypeName = (*Rows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*Rows)(nil)
+
+	// ErrComputeUnsupported is returned for commands with compute
+	// clauses. The command is aborted and the connection is marked as
+	// broken.
+	ErrComputeUnsupported = errors.New("compute clauses are not supported")
 )
 
 // Rows implements the driver.Rows interface.
@@ -28,7 +39,68 @@ type Rows struct {
 	Conn   *Conn
 	RowFmt *tds.RowFmtPackage
 
+	// ctx is the context the command was executed with. Reading rows
+	// is aborted once it ends.
+	ctx context.Context
+
+	// resultSetIndex is the index of the current result set.
+	resultSetIndex int
+	// nextRowFmt is the format of the next result set, received by
+	// Next at the end of the current result set.
+	nextRowFmt       *tds.RowFmtPackage
 	hasNextResultSet bool
+	// values are the values of the row last read by Next.
+	values []driver.Value
+	// fields are the data fields of the row last read by Next.
+	fields []tds.FieldData
+	// intoBufs are the byte slices NextInto copies values into.
+	// into is set while NextInto reads a row, intoDone marks the
+	// columns whose value was appended to their buffer while decoding.
+	intoBufs [][]byte
+	into     bool
+	intoDone []bool
+	// columnDecoders are the decoders of the columns of the current
+	// result set set by SetColumnCharset.
+	columnDecoders map[int]CharsetDecoder
+	// maxRows is the maximum number of rows per result set, see
+	// WithMaxRows. rowCount is the number of rows read from the
+	// current result set.
+	maxRows  int
+	rowCount int
+	// affectedCounts are the counts of affected rows reported by the
+	// statements of the command so far, see AffectedCounts.
+	affectedCounts []int64
+	// returnStatuses are the return statuses of the procedures of the
+	// command so far, see ReturnStatuses.
+	returnStatuses []int32
+	// peeked is set when the next row was read by Peek. peekValues,
+	// peekFields and peekErr are the row and error returned by the
+	// following call to Next.
+	peeked     bool
+	peekValues []driver.Value
+	peekFields []tds.FieldData
+	peekErr    error
+	// finished is set once all packages of the communication have
+	// been consumed.
+	finished bool
+
+	stats *ExecStats
+
+	// result receives the output parameters sent after the result
+	// sets.
+	result *Result
+
+	// adHocStmt is the statement prepared to execute the command with
+	// arguments, which is deallocated when the rows are closed.
+	adHocStmt *Stmt
+}
+
+// context returns the context the command was executed with.
+func (rows *Rows) context() context.Context {
+	if rows.ctx == nil {
+		return context.Background()
+	}
+	return rows.ctx
 }
 
 // Columns implements the driver.Rows interface.
@@ -51,6 +123,50 @@ func (rows Rows) Columns() []string {
 
 // Close implements the driver.Rows interface.
 func (rows *Rows) Close() error {
+	err := rows.close()
+	if err != nil && !rows.finished {
+		// The row limit cannot be reset while result sets are
+		// pending, it stays flagged for ResetSession.
+		return err
+	}
+
+	// The row limit is reset as well if consuming the result sets
+	// failed, e.g. due to an error of a later statement.
+	if resetErr := rows.Conn.resetRowLimit(context.Background()); resetErr != nil {
+		if err != nil {
+			return fmt.Errorf("%w (%v)", err, resetErr)
+		}
+		return fmt.Errorf("go-ase: error resetting row limit: %w", resetErr)
+	}
+
+	if rows.adHocStmt != nil {
+		stmt := rows.adHocStmt
+		rows.adHocStmt = nil
+		if closeErr := stmt.close(context.Background()); closeErr != nil {
+			if err != nil {
+				return fmt.Errorf("%w (%v)", err, closeErr)
+			}
+			return fmt.Errorf("go-ase: error deallocating statement: %w", closeErr)
+		}
+	}
+
+	return err
+}
+
+// close consumes or cancels the remaining result sets.
+func (rows *Rows) close() error {
+	if rows.finished {
+		return nil
+	}
+
+	if rows.Conn.Info.CloseMode == CloseModeCancel {
+		rows.finished = true
+		if err := rows.Conn.sendAttention(context.Background()); err != nil {
+			return fmt.Errorf("go-ase: error cancelling result sets: %w", err)
+		}
+		return nil
+	}
+
 	for {
 		if err := rows.NextResultSet(); err != nil {
 			if errors.Is(err, io.EOF) {
@@ -64,42 +180,261 @@ func (rows *Rows) Close() error {
 }
 
 // Next implements the driver.Rows interface.
+//
+// If the result set returns more rows than the limit set through the
+// property maxrows or WithMaxRows the command is aborted and
+// ErrRowLimitExceeded is returned.
+//
+// If a statement fails while its rows are read, e.g. through
+// a conversion error, Next returns the error once at the failing row
+// and io.EOF afterwards. The rows read before stay valid. If the
+// command contains further statements their result sets can still be
+// read through NextResultSet - database/sql closes the rows instead.
+//
+// If the next row was read by Peek it is returned without reading
+// from the server.
 func (rows *Rows) Next(dst []driver.Value) error {
-	if rows.RowFmt == nil && len(dst) == 0 {
+	if rows.peeked {
+		rows.peeked = false
+		if rows.peekErr != nil {
+			return rows.peekErr
+		}
+
+		if len(dst) != len(rows.peekValues) {
+			return fmt.Errorf("go-ase: received invalid number of destinations, expecting %d destinations, got %d", len(rows.peekValues), len(dst))
+		}
+		copy(dst, rows.peekValues)
+		rows.values = dst
+		rows.fields = rows.peekFields
+		return nil
+	}
+
+	return rows.next(dst)
+}
+
+// NextInto reads the next row into dst like Next, but stores binary
+// values in the byte slices held by dst, e.g. allocated once with
+// make([]byte, 0, 256), instead of storing the slices of the decoded
+// row. Character values and values returned as strings, e.g. with the
+// property uint64asstring, are stored as well if dst holds a byte slice
+// at their index, in which case they are returned as []byte.
+//
+// The values are appended to the byte slices while the row is decoded,
+// without converting them to a driver.Value first. Values of columns
+// with a decoder set by SetColumnCharset and rows buffered by Peek are
+// copied after decoding.
+//
+// The storage of the byte slices is reused by the following calls to
+// NextInto as long as its capacity suffices, also if a NULL value was
+// read in between. Values that must be retained must be copied before
+// the next call.
+func (rows *Rows) NextInto(dst []driver.Value) error {
+	if len(rows.intoBufs) != len(dst) {
+		rows.intoBufs = make([][]byte, len(dst))
+		rows.intoDone = make([]bool, len(dst))
+	}
+
+	for i, value := range dst {
+		if buf, ok := value.([]byte); ok && cap(buf) > 0 {
+			rows.intoBufs[i] = buf
+		}
+		rows.intoDone[i] = false
+	}
+
+	rows.into = true
+	err := rows.Next(dst)
+	rows.into = false
+	if err != nil {
+		return err
+	}
+
+	for i, buf := range rows.intoBufs {
+		if buf == nil || rows.intoDone[i] {
+			continue
+		}
+
+		switch value := dst[i].(type) {
+		case []byte:
+			rows.intoBufs[i] = append(buf[:0], value...)
+			dst[i] = rows.intoBufs[i]
+		case string:
+			rows.intoBufs[i] = append(buf[:0], value...)
+			dst[i] = rows.intoBufs[i]
+		}
+	}
+
+	return nil
+}
+
+// decodeRow stores the values of fields in dst.
+//
+// While NextInto reads the row the values of columns with a buffer are
+// appended to it instead.
+func (rows *Rows) decodeRow(dst []driver.Value, fields []tds.FieldData) error {
+	for i, field := range fields {
+		if rows.into && rows.intoBufs[i] != nil {
+			if buf, ok := rows.appendValue(rows.intoBufs[i][:0], i, field); ok {
+				rows.intoBufs[i] = buf
+				rows.intoDone[i] = true
+				// Storing a slice in dst allocates, which is
+				// skipped if dst already holds the same slice,
+				// e.g. for values of fixed length.
+				if current, ok := dst[i].([]byte); !ok || !sameSlice(current, buf) {
+					dst[i] = buf
+				}
+				continue
+			}
+		}
+
+		dst[i] = resultValue(rows.Conn.Info, field)
+	}
+
+	return rows.decodeColumns(dst)
+}
+
+// sameSlice reports if a and b share their storage, length and
+// capacity.
+func sameSlice(a, b []byte) bool {
+	return len(a) == len(b) && cap(a) == cap(b) && (cap(a) == 0 || &a[:1][0] == &b[:1][0])
+}
+
+// appendValue appends the value of field, the value of the column
+// index, to buf as resultValue would return it. ok is false if the
+// value is not returned as string or byte slice or if it must be
+// decoded by a decoder set by SetColumnCharset.
+func (rows *Rows) appendValue(buf []byte, index int, field tds.FieldData) ([]byte, bool) {
+	if _, ok := rows.columnDecoders[index]; ok {
+		return nil, false
+	}
+
+	switch value := field.Value().(type) {
+	case string:
+		if rows.Conn.Info.TrimChar && isCharColumn(field.Format()) {
+			value = strings.TrimRight(value, " ")
+		}
+		return append(buf, value...), true
+	case []byte:
+		// NULL is returned as nil by resultValue.
+		if value == nil {
+			return nil, false
+		}
+		return append(buf, value...), true
+	case uint64:
+		if rows.Conn.Info.Uint64AsString {
+			return strconv.AppendUint(buf, value, 10), true
+		}
+	}
+
+	return nil, false
+}
+
+// Peek reads the next row of the current result set into dst without
+// consuming it - the following call to Next returns the same row, or
+// the same error. Repeated calls to Peek return the same row as well.
+//
+// The row is buffered until it is returned by Next, hence only one row
+// of lookahead is supported. Values and ColumnValueLength keep
+// reporting the row last read by Next. Rows buffered by Peek are
+// discarded by NextResultSet.
+func (rows *Rows) Peek(dst []driver.Value) error {
+	if !rows.peeked {
+		values, fields := rows.values, rows.fields
+
+		rows.peekValues = make([]driver.Value, len(rows.Columns()))
+		rows.peekErr = rows.next(rows.peekValues)
+		rows.peekFields = rows.fields
+		rows.peeked = true
+
+		rows.values, rows.fields = values, fields
+	}
+
+	if rows.peekErr != nil {
+		return rows.peekErr
+	}
+
+	if len(dst) != len(rows.peekValues) {
+		return fmt.Errorf("go-ase: received invalid number of destinations, expecting %d destinations, got %d", len(rows.peekValues), len(dst))
+	}
+	copy(dst, rows.peekValues)
+	return nil
+}
+
+// next reads the next row from the server.
+func (rows *Rows) next(dst []driver.Value) error {
+	if rows.finished || rows.hasNextResultSet || (rows.RowFmt == nil && len(dst) == 0) {
 		return io.EOF
 	}
 
-	_, err := rows.Conn.Channel.NextPackageUntil(context.Background(), true,
+	limitExceeded := false
+	var statementErr error
+
+	pkg, err := rows.Conn.nextPackageUntil(rows.context(), true,
 		func(pkg tds.Package) (bool, error) {
 			switch typed := pkg.(type) {
 			case *tds.RowPackage:
+				if rows.maxRows > 0 && rows.rowCount >= rows.maxRows {
+					limitExceeded = true
+					return true, nil
+				}
 				if len(dst) != len(typed.DataFields) {
 					return true, fmt.Errorf("go-ase: received invalid number of destinations, expecting %d destinations, got %d", len(typed.DataFields), len(dst))
 				}
-				for i := range typed.DataFields {
-					dst[i] = typed.DataFields[i].Value()
+				if err := rows.decodeRow(dst, typed.DataFields); err != nil {
+					return true, err
 				}
+				rows.values = dst
+				rows.fields = typed.DataFields
+				rows.rowCount++
 				return true, nil
 			case *tds.RowFmtPackage:
-				rows.RowFmt = typed
+				rows.nextRowFmt = typed
 				rows.hasNextResultSet = true
 				return false, io.EOF
-			case *tds.OrderByPackage:
+			case *tds.OrderByPackage, *tds.OrderBy2Package:
 				return false, nil
 			case *tds.DonePackage:
+				if typed.Status&tds.TDS_DONE_ATTN == tds.TDS_DONE_ATTN {
+					return true, nil
+				}
+				rows.addAffectedCount(typed)
+
+				// A failed statement followed by further
+				// statements only ends the result set, the
+				// following result sets stay available.
+				if typed.Status&tds.TDS_DONE_ERROR == tds.TDS_DONE_ERROR && typed.Status&tds.TDS_DONE_MORE == tds.TDS_DONE_MORE {
+					statementErr = rows.Conn.statementError(typed)
+					return true, nil
+				}
+
 				ok, err := handleDonePackage(typed)
 				if err != nil {
+					// go-dblib consumes the remaining packages of
+					// the command on errors.
+					rows.finished = true
 					return true, fmt.Errorf("go-ase: %w", err)
 				}
 
 				return ok, nil
 			case *tds.ReturnStatusPackage:
-				if typed.ReturnValue != 0 {
-					return true, fmt.Errorf("go-ase: query failed with return status %d", typed.ReturnValue)
+				if err := rows.addReturnStatus(typed); err != nil {
+					return true, err
 				}
 				return false, nil
+			case *tds.ParamFmtPackage:
+				return false, nil
+			case *tds.ParamsPackage:
+				if err := rows.addOutputParams(typed); err != nil {
+					return true, err
+				}
+				return false, nil
+			case *tds.TokenlessPackage:
+				if isComputePackage(typed) {
+					rows.finished = true
+					return true, rows.Conn.abortCompute()
+				}
+				return true, rows.Conn.unhandledPackage(pkg)
 			default:
-				return true, fmt.Errorf("unhandled package type %T: %v", pkg, pkg)
+				return true, rows.Conn.unhandledPackage(pkg)
 			}
 		},
 	)
@@ -110,55 +445,267 @@ func (rows *Rows) Next(dst []driver.Value) error {
 		if errors.Is(err, io.EOF) {
 			return io.EOF
 		}
+		if ctxErr := rows.context().Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			rows.finished = true
+			return rows.Conn.abortOnContext(rows.context(), err)
+		}
 		return fmt.Errorf("go-ase: error reading next row package: %w", err)
 	}
 
-	return nil
+	if rows.Conn.attentionAcknowledged(pkg) {
+		rows.finished = true
+		return ErrCancelled
+	}
+
+	if limitExceeded {
+		rows.finished = true
+		if err := rows.Conn.sendAttention(context.Background()); err != nil {
+			return fmt.Errorf("go-ase: error cancelling command exceeding the row limit: %w", err)
+		}
+		return ErrRowLimitExceeded
+	}
+
+	return statementErr
+}
+
+// Values returns the values of the row last read by Next.
+func (rows *Rows) Values() []driver.Value {
+	return rows.values
+}
+
+// ColumnValueLength returns the length in bytes of the value of the
+// column at index in the row last read by Next.
+//
+// The length is reported for character, binary and LOB columns and is
+// the length as transmitted by the server, before conversions like
+// trimchar are applied. For character columns this is the length in
+// the character set of the connection - with multibyte character sets
+// such as utf8 the number of characters may be smaller, use
+// utf8.RuneCountInString on the value to count characters. unitext
+// values are transmitted as UTF-16.
+//
+// NULL values have a length of zero. The second return value is false
+// if no row has been read, the index is out of range or the column is
+// not of a variable-length type. ColumnTypeLength reports the declared
+// length of the column.
+func (rows *Rows) ColumnValueLength(index int) (int, bool) {
+	if index < 0 || index >= len(rows.fields) {
+		return 0, false
+	}
+
+	field := rows.fields[index]
+	switch value := field.Value().(type) {
+	case string:
+		if field.Format().DataType() == asetypes.UNITEXT {
+			return 2 * len(utf16.Encode([]rune(value))), true
+		}
+		return len(value), true
+	case []byte:
+		return len(value), true
+	default:
+		return 0, false
+	}
+}
+
+// AffectedCounts returns the counts of affected rows reported by the
+// statements of the command that were processed so far, in the order
+// of the statements - e.g. one count per DML statement of a stored
+// procedure. Queries report the number of selected rows as well.
+//
+// Counts are reported as the result sets are consumed. After the rows
+// are closed the counts of all statements are available, unless the
+// remaining result sets were cancelled, see CloseModeCancel.
+func (rows *Rows) AffectedCounts() []int64 {
+	return append([]int64{}, rows.affectedCounts...)
+}
+
+// addAffectedCount records the count of affected rows reported by done.
+func (rows *Rows) addAffectedCount(done *tds.DonePackage) {
+	if count, ok := affectedCount(done); ok {
+		rows.affectedCounts = append(rows.affectedCounts, count)
+	}
+}
+
+// ReturnStatuses returns the return statuses of the procedures executed
+// by the command that were received so far, in the order they were
+// sent by the server - e.g. the status of a procedure called by
+// another procedure before the status of the calling procedure.
+//
+// Return statuses other than zero are not treated as errors unless the
+// property returnstatuserror is set. After the rows are closed the
+// statuses of all procedures are available, unless the remaining
+// result sets were cancelled, see CloseModeCancel.
+func (rows *Rows) ReturnStatuses() []int32 {
+	return append([]int32{}, rows.returnStatuses...)
+}
+
+// addReturnStatus records the return status of a procedure.
+func (rows *Rows) addReturnStatus(status *tds.ReturnStatusPackage) error {
+	rows.returnStatuses = append(rows.returnStatuses, status.ReturnValue)
+	return returnStatusError(rows.Conn.Info, status)
+}
+
+// addOutputParams passes output parameters to the result of the
+// command.
+func (rows *Rows) addOutputParams(params *tds.ParamsPackage) error {
+	if rows.result == nil {
+		return nil
+	}
+	return rows.result.addOutputParams(rows.Conn.Info, params)
+}
+
+// ReadAll reads the remaining rows of the current result set.
+//
+// ReadAll stops at the end of the current result set. If another
+// result set follows it can be read after calling NextResultSet,
+// otherwise the rows are closed.
+func (rows *Rows) ReadAll() ([][]driver.Value, error) {
+	all := [][]driver.Value{}
+
+	for {
+		values := make([]driver.Value, len(rows.Columns()))
+		if err := rows.Next(values); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+
+		all = append(all, values)
+	}
+
+	if !rows.HasNextResultSet() {
+		if err := rows.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return all, nil
 }
 
 // HasNextResultSet implements the driver.RowsNextResultSet interface.
+//
+// It reports if the format of another result set was received at the
+// end of the current result set.
 func (rows *Rows) HasNextResultSet() bool {
-	if !rows.hasNextResultSet {
-		return false
+	return rows.hasNextResultSet
+}
+
+// ResultSetIndex returns the zero-based index of the current result
+// set or -1 if the command did not return a result set.
+//
+// The number of result sets of e.g. stored procedures is not known in
+// advance - the result sets are counted as they are reached through
+// NextResultSet. After NextResultSet returned io.EOF the index of the
+// last result set plus one is the total number of result sets.
+func (rows *Rows) ResultSetIndex() int {
+	if rows.RowFmt == nil {
+		return -1
 	}
-	rows.hasNextResultSet = false
-	return true
+	return rows.resultSetIndex
 }
 
 // NextResultSet implements the driver.RowsNextResultSet interface.
+//
+// Remaining rows of the current result set are discarded, including
+// a row buffered by Peek.
 func (rows *Rows) NextResultSet() error {
+	rows.peeked = false
+	rows.peekValues = nil
+	rows.peekFields = nil
+	rows.peekErr = nil
+
+	if rows.hasNextResultSet {
+		rows.switchResultSet(rows.nextRowFmt)
+		return nil
+	}
+
+	if rows.finished {
+		return io.EOF
+	}
+
 	// discard all RowPackage until either end of communication or next
 	// RowFmtPackage
-	_, err := rows.Conn.Channel.NextPackageUntil(context.Background(), false,
+	pkg, err := rows.Conn.nextPackageUntil(rows.context(), true,
 		func(pkg tds.Package) (bool, error) {
 			switch typed := pkg.(type) {
 			case *tds.RowFmtPackage:
-				rows.RowFmt = typed
-				rows.hasNextResultSet = true
-				return false, nil
-			case *tds.RowPackage, *tds.OrderByPackage:
+				rows.switchResultSet(typed)
 				return true, nil
+			case *tds.RowPackage, *tds.OrderByPackage, *tds.OrderBy2Package:
+				return false, nil
+			case *tds.ParamFmtPackage:
+				return false, nil
+			case *tds.ReturnStatusPackage:
+				if err := rows.addReturnStatus(typed); err != nil {
+					return true, err
+				}
+				return false, nil
+			case *tds.ParamsPackage:
+				if err := rows.addOutputParams(typed); err != nil {
+					return true, err
+				}
+				return false, nil
 			case *tds.DonePackage:
+				if typed.Status&tds.TDS_DONE_ATTN == tds.TDS_DONE_ATTN {
+					return true, nil
+				}
+				rows.addAffectedCount(typed)
+				if typed.Status&tds.TDS_DONE_ERROR == tds.TDS_DONE_ERROR {
+					_, err := handleDonePackage(typed)
+					rows.finished = true
+					return true, fmt.Errorf("go-ase: %w", err)
+				}
 				if typed.Status&tds.TDS_DONE_MORE == tds.TDS_DONE_MORE {
 					return false, nil
 				}
 				return true, fmt.Errorf("go-ase: no next result set: %w", io.EOF)
+			case *tds.TokenlessPackage:
+				if isComputePackage(typed) {
+					rows.finished = true
+					return true, rows.Conn.abortCompute()
+				}
+				return false, rows.Conn.unhandledPackage(pkg)
 			default:
-				return false, fmt.Errorf("unhandled package type %T: %v", pkg, pkg)
+				return false, rows.Conn.unhandledPackage(pkg)
 			}
 		},
 	)
 
 	if err != nil {
 		if errors.Is(err, tds.ErrNoPackageReady) || errors.Is(err, io.EOF) {
+			rows.finished = true
 			return io.EOF
 		}
+		if ctxErr := rows.context().Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			rows.finished = true
+			return rows.Conn.abortOnContext(rows.context(), err)
+		}
 		return fmt.Errorf("go-ase: error reading next package: %w", err)
 	}
 
+	if rows.Conn.attentionAcknowledged(pkg) {
+		rows.finished = true
+		return io.EOF
+	}
+
 	return nil
 }
 
+// switchResultSet makes rowFmt the format of the current result set.
+func (rows *Rows) switchResultSet(rowFmt *tds.RowFmtPackage) {
+	if rows.RowFmt != nil {
+		rows.resultSetIndex++
+	}
+
+	rows.RowFmt = rowFmt
+	rows.nextRowFmt = nil
+	rows.hasNextResultSet = false
+	rows.fields = nil
+	rows.columnDecoders = nil
+	rows.rowCount = 0
+}
+
 // ColumnTypeLength implements the driver.RowsColumnTypeLength interface.
 func (rows Rows) ColumnTypeLength(index int) (int64, bool) {
 	if index >= len(rows.RowFmt.Fmts) {
@@ -173,5 +720,32 @@ func (rows Rows) ColumnTypeDatabaseTypeName(index int) string {
 	if index >= len(rows.RowFmt.Fmts) {
 		return ""
 	}
-	return string(rows.RowFmt.Fmts[index].DataType())
+	return databaseTypeName(rows.RowFmt.Fmts[index])
+}
+
+// ColumnTypeScanType implements the driver.RowsColumnTypeScanType
+// interface.
+func (rows Rows) ColumnTypeScanType(index int) reflect.Type {
+	if index >= len(rows.RowFmt.Fmts) {
+		return nil
+	}
+	return scanType(rows.Conn.Info, rows.RowFmt.Fmts[index])
+}
+
+// ColumnCollation returns the locale information the server sent for
+// the column index in the row format, which describes the collation
+// of character columns, e.g. to compare values client-side the same
+// way as the server or to generate DDL.
+//
+// The information is returned as sent by the server. ok is false if
+// the format carries no locale information for the column. Servers
+// usually omit it, tools must then fall back to the sort order of the
+// server, see ServerSortOrder.
+func (rows Rows) ColumnCollation(index int) (string, bool) {
+	if rows.RowFmt == nil || index < 0 || index >= len(rows.RowFmt.Fmts) {
+		return "", false
+	}
+
+	collation := strings.TrimRight(rows.RowFmt.Fmts[index].LocaleInfo(), "\x00")
+	return collation, collation != ""
 }
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestRowsColumnCollation(t *testing.T) {
+	rowFmt := &tds.RowFmtPackage{}
+	for _, localeInfo := range []string{"", "sortorder=nocase_iso_1", "binary\x00"} {
+		fieldFmt, err := tds.LookupFieldFmt(asetypes.VARCHAR)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		fieldFmt.SetLocaleInfo(localeInfo)
+		rowFmt.Fmts = append(rowFmt.Fmts, fieldFmt)
+	}
+
+	rows := Rows{RowFmt: rowFmt}
+
+	cases := map[string]struct {
+		index    int
+		expected string
+		ok       bool
+	}{
+		"no locale info": {0, "", false},
+		"locale info":    {1, "sortorder=nocase_iso_1", true},
+		"trailing nul":   {2, "binary", true},
+		"negative index": {-1, "", false},
+		"invalid index":  {3, "", false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			collation, ok := rows.ColumnCollation(cas.index)
+			if collation != cas.expected || ok != cas.ok {
+				t.Errorf("Expected %q, %t, received %q, %t", cas.expected, cas.ok, collation, ok)
+			}
+		})
+	}
+
+	if _, ok := (Rows{}).ColumnCollation(0); ok {
+		t.Errorf("Expected no collation without row format")
+	}
+}
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestRowsNextInto(t *testing.T) {
+	buf := make([]byte, 0, 16)
+	rows := &Rows{}
+
+	read := func(values ...driver.Value) []driver.Value {
+		rows.peeked = true
+		rows.peekValues = values
+
+		dst := []driver.Value{int32(0), buf, buf[:0:0]}
+		if err := rows.NextInto(dst); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return dst
+	}
+
+	dst := read(int32(1), []byte("abc"), "def")
+	if dst[0] != int32(1) {
+		t.Errorf("Expected 1, received %v", dst[0])
+	}
+
+	received, ok := dst[1].([]byte)
+	if !ok || string(received) != "abc" {
+		t.Errorf("Expected []byte abc, received %#v", dst[1])
+	} else if &received[0] != &buf[:1][0] {
+		t.Errorf("Expected value to be copied into the provided buffer")
+	}
+
+	// Destinations without capacity are not used as buffer.
+	if dst[2] != "def" {
+		t.Errorf("Expected def, received %#v", dst[2])
+	}
+
+	// The buffer is kept for the following rows if NULL was read.
+	dst = read(int32(2), nil, "ghi")
+	if dst[1] != nil {
+		t.Errorf("Expected nil, received %#v", dst[1])
+	}
+
+	rows.peeked = true
+	rows.peekValues = []driver.Value{int32(3), "jkl", nil}
+	dst = []driver.Value{nil, nil, nil}
+	if err := rows.NextInto(dst); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	received, ok = dst[1].([]byte)
+	if !ok || string(received) != "jkl" {
+		t.Errorf("Expected []byte jkl, received %#v", dst[1])
+	} else if &received[0] != &buf[:1][0] {
+		t.Errorf("Expected value to be copied into the previous buffer")
+	}
+}
+
+// newRowFields returns the fields of a row with an unsigned bigint,
+// a varchar and a varbinary value.
+func newRowFields(tb testing.TB) []tds.FieldData {
+	values := []struct {
+		dataType asetypes.DataType
+		value    interface{}
+	}{
+		{asetypes.UINT8, uint64(math.MaxUint64)},
+		{asetypes.VARCHAR, "abc"},
+		{asetypes.VARBINARY, []byte{1, 2, 3}},
+	}
+
+	fields := make([]tds.FieldData, len(values))
+	for i, value := range values {
+		fieldFmt, err := newFieldFmt(value.dataType, 30)
+		if err != nil {
+			tb.Fatalf("Error creating field format: %v", err)
+		}
+
+		fields[i], err = tds.LookupFieldData(fieldFmt)
+		if err != nil {
+			tb.Fatalf("Error looking up field data: %v", err)
+		}
+		fields[i].SetValue(value.value)
+	}
+
+	return fields
+}
+
+func TestRows_decodeRow(t *testing.T) {
+	fields := newRowFields(t)
+	rows := &Rows{Conn: &Conn{Info: &Info{Uint64AsString: true}}}
+
+	dst := make([]driver.Value, len(fields))
+	if err := rows.decodeRow(dst, fields); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []driver.Value{"18446744073709551615", "abc", []byte{1, 2, 3}}
+	if !reflect.DeepEqual(dst, expected) {
+		t.Errorf("Expected %#v, received %#v", expected, dst)
+	}
+
+	// While NextInto reads a row the values are appended to the
+	// buffers.
+	bufs := [][]byte{make([]byte, 0, 32), make([]byte, 0, 32), make([]byte, 0, 32)}
+	rows.into = true
+	rows.intoBufs = append([][]byte{}, bufs...)
+	rows.intoDone = make([]bool, len(fields))
+	if err := rows.decodeRow(dst, fields); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i, value := range []string{"18446744073709551615", "abc", "\x01\x02\x03"} {
+		received, ok := dst[i].([]byte)
+		if !ok || string(received) != value {
+			t.Errorf("Expected []byte %q at %d, received %#v", value, i, dst[i])
+			continue
+		}
+
+		if &received[0] != &bufs[i][:1][0] {
+			t.Errorf("Expected value %d to be appended to the provided buffer", i)
+		}
+
+		if !rows.intoDone[i] {
+			t.Errorf("Expected value %d to be marked as appended", i)
+		}
+	}
+}
+
+// BenchmarkRows_decodeRow compares appending the values of a row to
+// the buffers of NextInto while decoding with copying the decoded
+// values into the buffers.
+func BenchmarkRows_decodeRow(b *testing.B) {
+	fields := newRowFields(b)
+
+	newBufs := func() [][]byte {
+		return [][]byte{make([]byte, 0, 32), make([]byte, 0, 32), make([]byte, 0, 32)}
+	}
+
+	b.Run("copy", func(b *testing.B) {
+		rows := &Rows{Conn: &Conn{Info: &Info{Uint64AsString: true}}}
+		dst := make([]driver.Value, len(fields))
+		bufs := newBufs()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := rows.decodeRow(dst, fields); err != nil {
+				b.Fatalf("Unexpected error: %v", err)
+			}
+
+			for j, value := range dst {
+				switch typed := value.(type) {
+				case string:
+					bufs[j] = append(bufs[j][:0], typed...)
+				case []byte:
+					bufs[j] = append(bufs[j][:0], typed...)
+				}
+				dst[j] = bufs[j]
+			}
+		}
+	})
+
+	b.Run("append", func(b *testing.B) {
+		rows := &Rows{Conn: &Conn{Info: &Info{Uint64AsString: true}}}
+		dst := make([]driver.Value, len(fields))
+		rows.into = true
+		rows.intoBufs = newBufs()
+		rows.intoDone = make([]bool, len(fields))
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := rows.decodeRow(dst, fields); err != nil {
+				b.Fatalf("Unexpected error: %v", err)
+			}
+		}
+	})
+}
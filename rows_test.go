@@ -0,0 +1,1037 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestRowsResultSets(t *testing.T) {
+	integration.TestForEachDB("TestRowsResultSets", t, testRowsResultSets)
+}
+
+func testRowsResultSets(t *testing.T, db *sql.DB, tableName string) {
+	procName := tableName + "_proc"
+
+	proc := fmt.Sprintf(`create procedure %s @n int
+as
+begin
+	select 1
+	if @n > 1 select 2, 'two'
+	if @n > 2 select 3
+end`, procName)
+
+	if _, err := db.Exec(proc); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	for _, n := range []int{1, 2, 3} {
+		err := conn.Raw(func(driverConn interface{}) error {
+			c := driverConn.(*Conn)
+
+			driverRows, _, err := c.DirectExec(context.Background(), fmt.Sprintf("exec %s %d", procName, n))
+			if err != nil {
+				return fmt.Errorf("error executing procedure: %w", err)
+			}
+			rows := driverRows.(*Rows)
+			defer rows.Close()
+
+			var firstValues []string
+			for {
+				if index := rows.ResultSetIndex(); index != len(firstValues) {
+					return fmt.Errorf("expected result set index %d, received %d", len(firstValues), index)
+				}
+
+				values := make([]driver.Value, len(rows.Columns()))
+				if err := rows.Next(values); err != nil {
+					return fmt.Errorf("error reading first row of result set %d: %w", len(firstValues), err)
+				}
+				firstValues = append(firstValues, fmt.Sprint(values[0]))
+
+				if err := rows.NextResultSet(); err != nil {
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					return fmt.Errorf("error advancing to next result set: %w", err)
+				}
+			}
+
+			expect := []string{"1", "2", "3"}[:n]
+			if !reflect.DeepEqual(firstValues, expect) {
+				return fmt.Errorf("expected first values %v, received %v", expect, firstValues)
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Errorf("n=%d: %v", n, err)
+		}
+	}
+}
+
+func TestRowsCompute(t *testing.T) {
+	integration.TestForEachDB("TestRowsCompute", t, testRowsCompute)
+}
+
+func testRowsCompute(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int)", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (1) insert into %s values (2)", tableName, tableName)); err != nil {
+		t.Errorf("Error inserting values: %v", err)
+		return
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		rows, _, err := c.DirectExec(context.Background(), fmt.Sprintf("select a from %s order by a compute sum(a)", tableName))
+		if err == nil {
+			values := make([]driver.Value, 1)
+			for err == nil {
+				err = rows.Next(values)
+			}
+		}
+
+		// The compute row must not be reported as the end of the
+		// result set or as another result set.
+		if !errors.Is(err, ErrComputeUnsupported) {
+			return fmt.Errorf("expected ErrComputeUnsupported, received %v", err)
+		}
+
+		// The remaining packages of the command cannot be consumed,
+		// the connection must be discarded.
+		if c.IsValid() {
+			return errors.New("expected connection to be marked as broken")
+		}
+
+		if _, _, err := c.DirectExec(context.Background(), "select 1"); !errors.Is(err, driver.ErrBadConn) {
+			return fmt.Errorf("expected driver.ErrBadConn executing command after compute, received %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	// database/sql replaces the broken connection.
+	var one int
+	if err := db.QueryRow("select 1").Scan(&one); err != nil {
+		t.Errorf("Error executing command on the pool after compute: %v", err)
+	}
+}
+
+func TestExecWithRowLimit(t *testing.T) {
+	integration.TestForEachDB("TestExecWithRowLimit", t, testExecWithRowLimit)
+}
+
+func testExecWithRowLimit(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int)", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (1) insert into %s values (2) insert into %s values (3)", tableName, tableName, tableName)); err != nil {
+		t.Errorf("Error inserting values: %v", err)
+		return
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	countRows := func(rows driver.Rows) (int, error) {
+		count := 0
+		values := make([]driver.Value, 1)
+		for {
+			if err := rows.Next(values); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return 0, err
+			}
+			count++
+		}
+		return count, rows.Close()
+	}
+
+	query := fmt.Sprintf("select a from %s", tableName)
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		rows, _, err := c.ExecWithRowLimit(context.Background(), query, 2)
+		if err != nil {
+			return fmt.Errorf("error executing with row limit: %w", err)
+		}
+
+		if count, err := countRows(rows); err != nil || count != 2 {
+			return fmt.Errorf("expected 2 rows with row limit, received %d: %v", count, err)
+		}
+
+		if _, _, err := c.ExecWithRowLimit(context.Background(), "select * from does_not_exist", 1); err == nil {
+			return fmt.Errorf("expected error for invalid query")
+		}
+
+		// The limit is reset when closing the rows fails due to an
+		// error in a later statement.
+		rows, _, err = c.ExecWithRowLimit(context.Background(), query+" select convert(int, 'x')", 1)
+		if err != nil {
+			return fmt.Errorf("error executing with row limit: %w", err)
+		}
+
+		if err := rows.Close(); err == nil {
+			return fmt.Errorf("expected error closing rows of failing statement")
+		}
+
+		rows, _, err = c.DirectExec(context.Background(), query)
+		if err != nil {
+			return fmt.Errorf("error executing without row limit: %w", err)
+		}
+
+		if count, err := countRows(rows); err != nil || count != 3 {
+			return fmt.Errorf("expected 3 rows after row limit, received %d: %v", count, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestRowsReadAll(t *testing.T) {
+	integration.TestForEachDB("TestRowsReadAll", t, testRowsReadAll)
+}
+
+func testRowsReadAll(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		driverRows, _, err := c.DirectExec(context.Background(), "select 1, 'one' union all select 2, 'two' select 3")
+		if err != nil {
+			return fmt.Errorf("error executing statement: %w", err)
+		}
+		rows := driverRows.(*Rows)
+		defer rows.Close()
+
+		all, err := rows.ReadAll()
+		if err != nil {
+			return fmt.Errorf("error reading first result set: %w", err)
+		}
+
+		expect := [][]driver.Value{{int32(1), "one"}, {int32(2), "two"}}
+		if !reflect.DeepEqual(all, expect) {
+			return fmt.Errorf("expected first result set %v, received %v", expect, all)
+		}
+
+		if err := rows.NextResultSet(); err != nil {
+			return fmt.Errorf("error advancing to second result set: %w", err)
+		}
+
+		all, err = rows.ReadAll()
+		if err != nil {
+			return fmt.Errorf("error reading second result set: %w", err)
+		}
+
+		expect = [][]driver.Value{{int32(3)}}
+		if !reflect.DeepEqual(all, expect) {
+			return fmt.Errorf("expected second result set %v, received %v", expect, all)
+		}
+
+		if !rows.finished {
+			return fmt.Errorf("expected rows to be closed after the last result set")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestComputedColumnTypes(t *testing.T) {
+	integration.TestForEachDB("TestComputedColumnTypes", t, testComputedColumnTypes)
+}
+
+func testComputedColumnTypes(t *testing.T, db *sql.DB, tableName string) {
+	query := "select datediff(day, '20210101', '20210111')," +
+		" datediff(day, null, '20210111')," +
+		" convert(bigint, datediff(ss, '20210101', '20210102')) * 1000," +
+		" 1.5 * datediff(hh, '20210101', '20210102')," +
+		" 0.5e0 * datediff(mi, '20210101', '20210101 00:03')"
+
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Errorf("Error executing query: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Errorf("Error reading column types: %v", err)
+		return
+	}
+
+	if !rows.Next() {
+		t.Errorf("Expected a row, received none: %v", rows.Err())
+		return
+	}
+
+	var (
+		days     int32
+		nullDays sql.NullInt32
+		millis   int64
+		hours    *asetypes.Decimal
+		minutes  float64
+	)
+	if err := rows.Scan(&days, &nullDays, &millis, &hours, &minutes); err != nil {
+		t.Errorf("Error scanning row: %v", err)
+		return
+	}
+
+	if days != 10 {
+		t.Errorf("Expected 10 days, received %d", days)
+	}
+
+	if nullDays.Valid {
+		t.Errorf("Expected datediff with NULL operand to be NULL, received %d", nullDays.Int32)
+	}
+
+	if millis != 86400000 {
+		t.Errorf("Expected 86400000 milliseconds, received %d", millis)
+	}
+
+	if hours == nil {
+		t.Errorf("Expected 36.0 hours, received nil")
+	} else if s := hours.String(); s != "36.0" {
+		t.Errorf("Expected 36.0 hours, received %s", s)
+	}
+
+	if minutes != 1.5 {
+		t.Errorf("Expected 1.5 minutes, received %f", minutes)
+	}
+
+	expect := []reflect.Type{
+		reflect.TypeOf(int32(0)),
+		reflect.TypeOf(int32(0)),
+		reflect.TypeOf(int64(0)),
+		reflect.TypeOf(&asetypes.Decimal{}),
+		reflect.TypeOf(float64(0)),
+	}
+	for i, colType := range colTypes {
+		if colType.ScanType() != expect[i] {
+			t.Errorf("Expected scan type %v for column %d, received %v", expect[i], i, colType.ScanType())
+		}
+	}
+}
+
+func TestDateFunctions(t *testing.T) {
+	integration.TestForEachDB("TestDateFunctions", t, testDateFunctions)
+}
+
+func testDateFunctions(t *testing.T, db *sql.DB, tableName string) {
+	query := "select datepart(year, getdate()), datename(month, '20210301')," +
+		" dateadd(day, 1, convert(bigdatetime, '20210228'))," +
+		" dateadd(day, 1, getdate())"
+
+	rows, err := db.Query(query)
+	if err != nil {
+		t.Errorf("Error executing query: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Errorf("Error reading column types: %v", err)
+		return
+	}
+
+	if !rows.Next() {
+		t.Errorf("Expected a row, received none: %v", rows.Err())
+		return
+	}
+
+	var (
+		year     int32
+		month    string
+		nextDay  time.Time
+		tomorrow interface{}
+	)
+	if err := rows.Scan(&year, &month, &nextDay, &tomorrow); err != nil {
+		t.Errorf("Error scanning row: %v", err)
+		return
+	}
+
+	if year < 2021 {
+		t.Errorf("Expected current year, received %d", year)
+	}
+
+	if month != "March" {
+		t.Errorf("Expected month name March, received %q", month)
+	}
+
+	if expect := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC); !nextDay.Equal(expect) {
+		t.Errorf("Expected %v, received %v", expect, nextDay)
+	}
+
+	// dateadd returns the type of its date operand, which is
+	// transmitted as the nullable datetime type for datetime
+	// values. go-dblib does not decode nullable datetime values.
+	switch typeName := colTypes[3].DatabaseTypeName(); typeName {
+	case "DATETIME":
+		if _, ok := tomorrow.(time.Time); !ok {
+			t.Errorf("Expected time.Time for dateadd, received %T", tomorrow)
+		}
+	case "DATETIMEN":
+		t.Logf("dateadd on datetime is transmitted as DATETIMEN, which is not decoded")
+	default:
+		t.Errorf("Expected datetime type for dateadd, received %s", typeName)
+	}
+
+	expect := []reflect.Type{
+		reflect.TypeOf(int32(0)),
+		reflect.TypeOf(""),
+		reflect.TypeOf(time.Time{}),
+		reflect.TypeOf(time.Time{}),
+	}
+	for i, colType := range colTypes {
+		if colType.ScanType() != expect[i] {
+			t.Errorf("Expected scan type %v for column %d, received %v", expect[i], i, colType.ScanType())
+		}
+	}
+}
+
+func TestRowsColumnValueLength(t *testing.T) {
+	integration.TestForEachDB("TestRowsColumnValueLength", t, testRowsColumnValueLength)
+}
+
+func testRowsColumnValueLength(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		driverRows, _, err := c.DirectExec(context.Background(),
+			"select convert(varchar(10), 'abc'), convert(char(10), 'abc'), convert(varbinary(10), 0x0102), 1")
+		if err != nil {
+			return fmt.Errorf("error executing statement: %w", err)
+		}
+		rows := driverRows.(*Rows)
+		defer rows.Close()
+
+		if _, ok := rows.ColumnValueLength(0); ok {
+			return fmt.Errorf("expected no value length before Next")
+		}
+
+		values := make([]driver.Value, len(rows.Columns()))
+		if err := rows.Next(values); err != nil {
+			return fmt.Errorf("error reading row: %w", err)
+		}
+
+		expect := []struct {
+			length int
+			ok     bool
+		}{{3, true}, {10, true}, {2, true}, {0, false}}
+
+		for i, exp := range expect {
+			length, ok := rows.ColumnValueLength(i)
+			if length != exp.length || ok != exp.ok {
+				return fmt.Errorf("expected value length (%d, %t) for column %d, received (%d, %t)",
+					exp.length, exp.ok, i, length, ok)
+			}
+		}
+
+		if declared, _ := rows.ColumnTypeLength(1); declared != 10 {
+			return fmt.Errorf("expected declared length 10, received %d", declared)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestRowsPeek(t *testing.T) {
+	integration.TestForEachDB("TestRowsPeek", t, testRowsPeek)
+}
+
+func testRowsPeek(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		driverRows, _, err := c.DirectExec(context.Background(),
+			"select 1 union all select 2 order by 1 select 3")
+		if err != nil {
+			return fmt.Errorf("error executing statement: %w", err)
+		}
+		rows := driverRows.(*Rows)
+		defer rows.Close()
+
+		peeked := make([]driver.Value, 1)
+		values := make([]driver.Value, 1)
+
+		for _, expect := range []int32{1, 2} {
+			for i := 0; i < 2; i++ {
+				if err := rows.Peek(peeked); err != nil {
+					return fmt.Errorf("error peeking row: %w", err)
+				}
+				if peeked[0] != expect {
+					return fmt.Errorf("expected peeked value %d, received %v", expect, peeked[0])
+				}
+			}
+
+			if err := rows.Next(values); err != nil {
+				return fmt.Errorf("error reading row: %w", err)
+			}
+			if values[0] != expect {
+				return fmt.Errorf("expected value %d, received %v", expect, values[0])
+			}
+		}
+
+		if err := rows.Peek(peeked); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected io.EOF peeking past the result set, received %v", err)
+		}
+
+		if err := rows.Next(values); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected io.EOF after peeking past the result set, received %v", err)
+		}
+
+		if err := rows.NextResultSet(); err != nil {
+			return fmt.Errorf("error switching result set: %w", err)
+		}
+
+		if err := rows.Peek(peeked); err != nil {
+			return fmt.Errorf("error peeking row of second result set: %w", err)
+		}
+
+		// The buffered row is discarded with its result set.
+		if err := rows.NextResultSet(); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected io.EOF switching past the last result set, received %v", err)
+		}
+
+		if err := rows.Next(values); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected io.EOF after discarding the peeked row, received %v", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestSysname(t *testing.T) {
+	integration.TestForEachDB("TestSysname", t, testSysname)
+}
+
+func testSysname(t *testing.T, db *sql.DB, tableName string) {
+	rows, err := db.Query("select name from sysobjects where name = 'sysobjects'")
+	if err != nil {
+		t.Errorf("Error querying sysobjects: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		t.Errorf("Error reading column types: %v", err)
+		return
+	}
+
+	if name := colTypes[0].DatabaseTypeName(); name != "SYSNAME" && name != "LONGSYSNAME" {
+		t.Errorf("Expected database type name SYSNAME or LONGSYSNAME, received %q", name)
+	}
+
+	if scanType := colTypes[0].ScanType(); scanType != reflect.TypeOf("") {
+		t.Errorf("Expected scan type string, received %v", scanType)
+	}
+
+	if !rows.Next() {
+		t.Errorf("Expected a row, received none: %v", rows.Err())
+		return
+	}
+
+	var name string
+	if err := rows.Scan(&name); err != nil {
+		t.Errorf("Error scanning name: %v", err)
+		return
+	}
+
+	if name != "sysobjects" {
+		t.Errorf("Expected %q, received %q", "sysobjects", name)
+	}
+}
+
+func TestRowsAdminProcs(t *testing.T) {
+	integration.TestForEachDB("TestRowsAdminProcs", t, testRowsAdminProcs)
+}
+
+func testRowsAdminProcs(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int primary key, b varchar(30) null)", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	procName := tableName + "_proc"
+	if _, err := db.Exec(fmt.Sprintf("create procedure %s as select a, b from %s", procName, tableName)); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	cases := map[string]struct {
+		query string
+		check func([][][]driver.Value) error
+	}{
+		"sp_help": {
+			"exec sp_help " + tableName,
+			func(resultSets [][][]driver.Value) error {
+				if len(resultSets) < 2 {
+					return fmt.Errorf("expected at least 2 result sets, received %d", len(resultSets))
+				}
+				return nil
+			},
+		},
+		"sp_helptext": {
+			"exec sp_helptext " + procName,
+			func(resultSets [][][]driver.Value) error {
+				for _, resultSet := range resultSets {
+					for _, row := range resultSet {
+						if s, ok := row[0].(string); ok && strings.Contains(s, "select a, b") {
+							return nil
+						}
+					}
+				}
+				return fmt.Errorf("expected text of procedure in result sets %v", resultSets)
+			},
+		},
+		"sp_columns": {
+			"exec sp_columns " + tableName,
+			func(resultSets [][][]driver.Value) error {
+				if len(resultSets) < 1 || len(resultSets[0]) != 2 {
+					return fmt.Errorf("expected 2 columns in first result set, received %v", resultSets)
+				}
+				return nil
+			},
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			err := conn.Raw(func(driverConn interface{}) error {
+				c := driverConn.(*Conn)
+
+				driverRows, _, err := c.DirectExec(context.Background(), cas.query)
+				if err != nil {
+					return fmt.Errorf("error executing %q: %w", cas.query, err)
+				}
+				rows := driverRows.(*Rows)
+				defer rows.Close()
+
+				var resultSets [][][]driver.Value
+				for {
+					all, err := rows.ReadAll()
+					if err != nil {
+						return fmt.Errorf("error reading result set %d: %w", len(resultSets), err)
+					}
+					resultSets = append(resultSets, all)
+
+					if err := rows.NextResultSet(); err != nil {
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						return fmt.Errorf("error advancing to next result set: %w", err)
+					}
+				}
+
+				return cas.check(resultSets)
+			})
+			if err != nil {
+				t.Errorf("%v", err)
+			}
+		})
+	}
+}
+
+func TestRowsMaxRows(t *testing.T) {
+	integration.TestForEachDB("TestRowsMaxRows", t, testRowsMaxRows)
+}
+
+func testRowsMaxRows(t *testing.T, db *sql.DB, tableName string) {
+	const query = "select 1 union all select 2 union all select 3"
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		driverRows, _, err := c.DirectExec(WithMaxRows(context.Background(), 2), query)
+		if err != nil {
+			return fmt.Errorf("error executing statement: %w", err)
+		}
+		defer driverRows.Close()
+
+		values := make([]driver.Value, 1)
+		for i := 0; i < 2; i++ {
+			if err := driverRows.Next(values); err != nil {
+				return fmt.Errorf("error reading row %d within the limit: %w", i, err)
+			}
+		}
+
+		if err := driverRows.Next(values); !errors.Is(err, ErrRowLimitExceeded) {
+			return fmt.Errorf("expected ErrRowLimitExceeded, received %v", err)
+		}
+
+		// The connection must be usable after the command was aborted.
+		if err := c.Ping(context.Background()); err != nil {
+			return fmt.Errorf("error pinging after exceeding the row limit: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	// database/sql queries use cursors by default.
+	rows, err := db.QueryContext(WithMaxRows(context.Background(), 2), query)
+	if err != nil {
+		t.Errorf("Error querying: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		n++
+	}
+
+	if n != 2 {
+		t.Errorf("Expected 2 rows within the limit, received %d", n)
+	}
+
+	if err := rows.Err(); !errors.Is(err, ErrRowLimitExceeded) {
+		t.Errorf("Expected ErrRowLimitExceeded, received %v", err)
+	}
+}
+
+func TestRowsAffectedCounts(t *testing.T) {
+	integration.TestForEachDB("TestRowsAffectedCounts", t, testRowsAffectedCounts)
+}
+
+func testRowsAffectedCounts(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int)", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	procName := tableName + "_proc"
+	proc := fmt.Sprintf(`create procedure %s as
+	insert into %s values (1)
+	insert into %s select a + 1 from %s
+	update %s set a = a * 10
+	select a from %s
+	delete from %s where a = 10`,
+		procName, tableName, tableName, tableName, tableName, tableName, tableName)
+	if _, err := db.Exec(proc); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		driverRows, _, err := c.DirectExec(context.Background(), "exec "+procName)
+		if err != nil {
+			return fmt.Errorf("error executing procedure: %w", err)
+		}
+		rows := driverRows.(*Rows)
+
+		if _, err := rows.ReadAll(); err != nil {
+			return fmt.Errorf("error reading rows: %w", err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		expect := []int64{1, 1, 2, 2, 1}
+		if recv := rows.AffectedCounts(); !reflect.DeepEqual(recv, expect) {
+			return fmt.Errorf("expected affected counts %v, received %v", expect, recv)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestRowsReturnStatuses(t *testing.T) {
+	integration.TestForEachDB("TestRowsReturnStatuses", t, testRowsReturnStatuses)
+}
+
+func testRowsReturnStatuses(t *testing.T, db *sql.DB, tableName string) {
+	innerName := tableName + "_inner"
+	if _, err := db.Exec(fmt.Sprintf("create procedure %s as return 5", innerName)); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + innerName)
+
+	outerName := tableName + "_outer"
+	proc := fmt.Sprintf(`create procedure %s as
+	exec %s
+	select 1
+	return 7`, outerName, innerName)
+	if _, err := db.Exec(proc); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + outerName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		driverRows, _, err := c.DirectExec(context.Background(), "exec "+outerName)
+		if err != nil {
+			return fmt.Errorf("error executing procedure: %w", err)
+		}
+		rows := driverRows.(*Rows)
+
+		if _, err := rows.ReadAll(); err != nil {
+			return fmt.Errorf("error reading rows: %w", err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		expect := []int32{5, 7}
+		if recv := rows.ReturnStatuses(); !reflect.DeepEqual(recv, expect) {
+			return fmt.Errorf("expected return statuses %v, received %v", expect, recv)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestSelectInto(t *testing.T) {
+	integration.TestForEachDB("TestSelectInto", t, testSelectInto)
+}
+
+func testSelectInto(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	var expect int64
+	if err := conn.QueryRowContext(context.Background(), "select count(*) from sysobjects").Scan(&expect); err != nil {
+		t.Errorf("Error counting sysobjects: %v", err)
+		return
+	}
+
+	cases := map[string]struct {
+		query string
+		args  []interface{}
+	}{
+		"language": {"select * into #select_into_language from sysobjects", nil},
+		"dynamic":  {"select * into #select_into_dynamic from sysobjects where id > ?", []interface{}{-1 << 31}},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			result, err := conn.ExecContext(context.Background(), cas.query, cas.args...)
+			if err != nil {
+				t.Errorf("Error executing select into: %v", err)
+				return
+			}
+
+			affected, err := result.RowsAffected()
+			if err != nil {
+				t.Errorf("Error retrieving affected rows: %v", err)
+				return
+			}
+
+			if affected != expect {
+				t.Errorf("Expected %d affected rows, received %d", expect, affected)
+			}
+		})
+	}
+
+	var count int64
+	if err := conn.QueryRowContext(context.Background(), "select count(*) from #select_into_language").Scan(&count); err != nil {
+		t.Errorf("Error counting rows of the created table: %v", err)
+		return
+	}
+
+	if count != expect {
+		t.Errorf("Expected %d rows in the created table, received %d", expect, count)
+	}
+}
+
+func TestRowsStatementError(t *testing.T) {
+	integration.TestForEachDB("TestRowsStatementError", t, testRowsStatementError)
+}
+
+func testRowsStatementError(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int primary key, b varchar(10))", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (1, '1') insert into %s values (2, '2') insert into %s values (3, 'x') insert into %s values (4, '4')",
+		tableName, tableName, tableName, tableName)); err != nil {
+		t.Errorf("Error inserting rows: %v", err)
+		return
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		driverRows, _, err := c.DirectExec(context.Background(), fmt.Sprintf("select convert(int, b) from %s", tableName))
+		if err != nil {
+			return fmt.Errorf("error executing query: %w", err)
+		}
+		rows := driverRows.(*Rows)
+		defer rows.Close()
+
+		values := make([]driver.Value, 1)
+		for i := int32(1); i <= 2; i++ {
+			if err := rows.Next(values); err != nil {
+				return fmt.Errorf("error reading row %d: %w", i, err)
+			}
+
+			if values[0] != i {
+				return fmt.Errorf("expected %d in row %d, received %v", i, i, values[0])
+			}
+		}
+
+		err = rows.Next(values)
+		var aseErr *Error
+		if !errors.As(err, &aseErr) {
+			return fmt.Errorf("expected *Error at the failing row, received %v", err)
+		}
+
+		if err := rows.Next(values); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected io.EOF after the error, received %v", err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	var one int
+	if err := conn.QueryRowContext(context.Background(), "select 1").Scan(&one); err != nil {
+		t.Errorf("Error using connection after the failed statement: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// Interface satisfaction checks.
+var (
+	_ driver.Valuer = (*RowVersion)(nil)
+	_ sql.Scanner   = (*RowVersion)(nil)
+)
+
+// userTypeTimestamp is the usertype ASE reports for columns of type
+// timestamp. On the wire these columns are transmitted as
+// varbinary(8).
+const userTypeTimestamp = 80
+
+// RowVersion is the value of an ASE timestamp column.
+//
+// Despite its name the ASE timestamp is not related to date or time
+// - it is an eight byte binary value the server increments every time
+// the row is modified. It is commonly used for optimistic concurrency
+// control:
+//
+//	var version ase.RowVersion
+//	db.QueryRow("select ts from tab where id = ?", id).Scan(&version)
+//	...
+//	res, err := db.Exec("update tab set a = ? where id = ? and ts = ?", a, id, version)
+//
+// If the update reports zero affected rows the row was modified by
+// another connection in the meantime.
+type RowVersion []byte
+
+// Value implements the driver.Valuer interface.
+func (rv RowVersion) Value() (driver.Value, error) {
+	if rv == nil {
+		return nil, nil
+	}
+	return []byte(rv), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (rv *RowVersion) Scan(src interface{}) error {
+	switch typed := src.(type) {
+	case nil:
+		*rv = nil
+	case []byte:
+		*rv = append(RowVersion{}, typed...)
+	default:
+		return fmt.Errorf("go-ase: cannot scan %T into RowVersion", src)
+	}
+
+	return nil
+}
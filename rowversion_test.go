@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestRowVersion(t *testing.T) {
+	integration.TestForEachDB("TestRowVersion", t, testRowVersion)
+}
+
+func testRowVersion(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (id int, a int, ts timestamp)", tableName)); err != nil {
+		t.Errorf("Error creating table %s: %v", tableName, err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s (id, a) values (1, 1)", tableName)); err != nil {
+		t.Errorf("Error inserting row: %v", err)
+		return
+	}
+
+	var version RowVersion
+	if err := db.QueryRow(fmt.Sprintf("select ts from %s where id = 1", tableName)).Scan(&version); err != nil {
+		t.Errorf("Error selecting timestamp: %v", err)
+		return
+	}
+
+	if len(version) != 8 {
+		t.Errorf("Expected timestamp of length 8, received %d: %v", len(version), version)
+		return
+	}
+
+	update := fmt.Sprintf("update %s set a = ? where id = 1 and ts = ?", tableName)
+
+	res, err := db.Exec(update, 2, version)
+	if err != nil {
+		t.Errorf("Error updating row with timestamp: %v", err)
+		return
+	}
+
+	if affected, _ := res.RowsAffected(); affected != 1 {
+		t.Errorf("Expected update with current timestamp to affect 1 row, affected %d", affected)
+		return
+	}
+
+	// The first update changed the timestamp, the second update with
+	// the outdated timestamp must not affect any rows.
+	res, err = db.Exec(update, 3, version)
+	if err != nil {
+		t.Errorf("Error updating row with outdated timestamp: %v", err)
+		return
+	}
+
+	if affected, _ := res.RowsAffected(); affected != 0 {
+		t.Errorf("Expected update with outdated timestamp to affect 0 rows, affected %d", affected)
+	}
+}
@@ -0,0 +1,431 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// Interface satisfaction checks.
+var (
+	_ tds.Package = (*rpcPackage)(nil)
+)
+
+// rpcOption is a bitmask of options of the TDS_DBRPC token.
+type rpcOption uint16
+
+const (
+	rpcUnused    rpcOption = 0x0
+	rpcRecompile rpcOption = 0x1
+	rpcParams    rpcOption = 0x2
+)
+
+// rpcPackage is the TDS_DBRPC token, which invokes a stored procedure.
+// If the rpcParams option is set the package must be followed by
+// a ParamFmtPackage and a ParamsPackage.
+type rpcPackage struct {
+	Name    string
+	Options rpcOption
+}
+
+// ReadFrom implements the tds.Package interface.
+func (pkg *rpcPackage) ReadFrom(ch tds.BytesChannel) error {
+	if _, err := ch.Uint16(); err != nil {
+		return tds.ErrNotEnoughBytes
+	}
+
+	nameLength, err := ch.Uint8()
+	if err != nil {
+		return tds.ErrNotEnoughBytes
+	}
+
+	pkg.Name, err = ch.String(int(nameLength))
+	if err != nil {
+		return tds.ErrNotEnoughBytes
+	}
+
+	options, err := ch.Uint16()
+	if err != nil {
+		return tds.ErrNotEnoughBytes
+	}
+	pkg.Options = rpcOption(options)
+
+	return nil
+}
+
+// WriteTo implements the tds.Package interface.
+func (pkg rpcPackage) WriteTo(ch tds.BytesChannel) error {
+	if len(pkg.Name) > 255 {
+		return fmt.Errorf("procedure name exceeds 255 bytes: %s", pkg.Name)
+	}
+
+	if err := ch.WriteByte(byte(tds.TDS_DBRPC)); err != nil {
+		return fmt.Errorf("failed to write TDS token %s: %w", tds.TDS_DBRPC, err)
+	}
+
+	// 1 name length, x name, 2 options
+	if err := ch.WriteUint16(uint16(1 + len(pkg.Name) + 2)); err != nil {
+		return fmt.Errorf("failed to write length: %w", err)
+	}
+
+	if err := ch.WriteUint8(uint8(len(pkg.Name))); err != nil {
+		return fmt.Errorf("failed to write name length: %w", err)
+	}
+
+	if err := ch.WriteString(pkg.Name); err != nil {
+		return fmt.Errorf("failed to write name: %w", err)
+	}
+
+	if err := ch.WriteUint16(uint16(pkg.Options)); err != nil {
+		return fmt.Errorf("failed to write options: %w", err)
+	}
+
+	return nil
+}
+
+func (pkg rpcPackage) String() string {
+	return fmt.Sprintf("%T(%d): %s", pkg, pkg.Options, pkg.Name)
+}
+
+// Param is a parameter of a stored procedure invoked with SendRPC.
+type Param struct {
+	// Name is the name of the parameter. The leading @ is optional.
+	// Parameters without name are passed by position.
+	Name string
+	// Value is the value of the parameter.
+	// For output parameters the type of Value also defines the type of
+	// the returned value.
+	Value interface{}
+	// Output marks the parameter as output parameter.
+	Output bool
+}
+
+// rpcOutputLength is the maximum length of string and byte slice
+// output parameters.
+const rpcOutputLength = 16384
+
+// SendLanguage sends sql as a language command to the server and
+// returns the response.
+//
+// Contrary to the database/sql methods sql is not passed through
+// prepared statements or cursors.
+// The rows must be closed before the next command can be sent.
+func (c *Conn) SendLanguage(ctx context.Context, sql string) (*Rows, *Result, error) {
+	rows, result, err := c.language(ctx, sql)
+	if err != nil {
+		return nil, nil, fmt.Errorf("go-ase: error executing language command: %w", err)
+	}
+
+	return rows.(*Rows), result.(*Result), nil
+}
+
+// SendRPC invokes the stored procedure proc with the passed parameters
+// and returns the response.
+//
+// Contrary to `exec proc` sent as language command the parameters are
+// transmitted typed and do not need to be quoted. Values returned in
+// output parameters are available with Result.OutputParam and
+// Result.OutputParams after the rows were consumed or closed.
+// The rows must be closed before the next command can be sent.
+func (c *Conn) SendRPC(ctx context.Context, proc string, params []Param) (*Rows, *Result, error) {
+	if err := c.propagateTraceID(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.acquire(); err != nil {
+		return nil, nil, err
+	}
+	defer c.release()
+
+	if err := c.checkBusy(); err != nil {
+		return nil, nil, err
+	}
+
+	c.resetStats()
+	c.takeMessages()
+
+	if c.queryLog != nil {
+		args := make([]driver.NamedValue, len(params))
+		for i, param := range params {
+			args[i] = driver.NamedValue{Name: param.Name, Ordinal: i + 1, Value: param.Value}
+		}
+		c.queryLog.log(ctx, proc, args)
+	}
+
+	rpc := &rpcPackage{Name: proc, Options: rpcUnused}
+
+	if len(params) == 0 {
+		if err := c.sendPackage(ctx, rpc); err != nil {
+			return nil, nil, fmt.Errorf("go-ase: error sending RPC: %w", err)
+		}
+	} else {
+		fieldFmts, fieldData, err := c.rpcParamFields(params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("go-ase: error preparing RPC parameters: %w", err)
+		}
+
+		rpc.Options |= rpcParams
+		if err := c.queuePackage(ctx, rpc); err != nil {
+			return nil, nil, fmt.Errorf("go-ase: error queueing RPC: %w", err)
+		}
+
+		if err := c.queuePackage(ctx, tds.NewParamFmtPackage(false, fieldFmts...)); err != nil {
+			return nil, nil, fmt.Errorf("go-ase: error queueing RPC parameter format: %w", err)
+		}
+
+		if err := c.sendPackage(ctx, tds.NewParamsPackage(fieldData...)); err != nil {
+			return nil, nil, fmt.Errorf("go-ase: error sending RPC parameters: %w", err)
+		}
+	}
+
+	rows, result, err := c.genericResults(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("go-ase: error executing RPC: %w", err)
+	}
+
+	result.(*Result).declareOutputParams(params)
+
+	return rows.(*Rows), result.(*Result), nil
+}
+
+// rpcParamFields returns the formats and data of the passed parameters.
+func (c *Conn) rpcParamFields(params []Param) ([]tds.FieldFmt, []tds.FieldData, error) {
+	fieldFmts := make([]tds.FieldFmt, len(params))
+	fieldData := make([]tds.FieldData, len(params))
+
+	for i, param := range params {
+		value, _ := unwrapSensitive(param.Value)
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return nil, nil, fmt.Errorf("error calling Value on parameter %d: %w", i+1, err)
+			}
+			value = v
+		}
+		// Parameters of procedures accept NULL.
+		value = c.emptyStringAsNull(c.normalize(value), true)
+
+		fieldFmt, value, err := rpcParamFmt(value, param.Output)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error preparing parameter %d: %w", i+1, err)
+		}
+
+		if param.Name != "" && !strings.HasPrefix(param.Name, "@") {
+			param.Name = "@" + param.Name
+		}
+		fieldFmt.SetName(param.Name)
+
+		status := tds.TDS_PARAM_NOSTATUS
+		if param.Output {
+			status |= tds.TDS_PARAM_RETURN
+		}
+		fieldFmt.SetStatus(uint(status))
+
+		data, err := tds.LookupFieldData(fieldFmt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error looking up field data for parameter %d: %w", i+1, err)
+		}
+		data.SetValue(value)
+
+		fieldFmts[i] = fieldFmt
+		fieldData[i] = data
+	}
+
+	return fieldFmts, fieldData, nil
+}
+
+// rpcParamFmt returns the format to transmit value with and value
+// converted to the type expected by the format.
+func rpcParamFmt(value interface{}, output bool) (tds.FieldFmt, interface{}, error) {
+	var fieldFmt tds.FieldFmt
+	var err error
+
+	switch typed := value.(type) {
+	case nil:
+		fieldFmt, err = newFieldFmt(asetypes.VARCHAR, 255)
+		value = []byte{}
+	case bool:
+		fieldFmt, err = newFieldFmt(asetypes.BIT, 0)
+	case int:
+		fieldFmt, err = newFieldFmt(asetypes.INTN, 8)
+		value = int64(typed)
+	case int8:
+		fieldFmt, err = newFieldFmt(asetypes.INTN, 8)
+		value = int64(typed)
+	case int16:
+		fieldFmt, err = newFieldFmt(asetypes.INTN, 8)
+		value = int64(typed)
+	case int32:
+		fieldFmt, err = newFieldFmt(asetypes.INTN, 8)
+		value = int64(typed)
+	case int64:
+		fieldFmt, err = newFieldFmt(asetypes.INTN, 8)
+	case uint:
+		fieldFmt, err = newFieldFmt(asetypes.UINTN, 8)
+		value = uint64(typed)
+	case uint8:
+		fieldFmt, err = newFieldFmt(asetypes.UINTN, 8)
+		value = uint64(typed)
+	case uint16:
+		fieldFmt, err = newFieldFmt(asetypes.UINTN, 8)
+		value = uint64(typed)
+	case uint32:
+		fieldFmt, err = newFieldFmt(asetypes.UINTN, 8)
+		value = uint64(typed)
+	case uint64:
+		fieldFmt, err = newFieldFmt(asetypes.UINTN, 8)
+	case float32:
+		fieldFmt, err = newFieldFmt(asetypes.FLTN, 4)
+	case float64:
+		fieldFmt, err = newFieldFmt(asetypes.FLTN, 8)
+	case Real:
+		fieldFmt, err = newFieldFmt(asetypes.FLTN, 4)
+		value = float32(typed)
+	case Float:
+		fieldFmt, err = newFieldFmt(asetypes.FLTN, 8)
+		value = float64(typed)
+	case string:
+		fieldFmt, err = newFieldFmt(asetypes.LONGCHAR, rpcLength(len(typed), output))
+		if err == nil {
+			value = emptyValue(fieldFmt, []byte(typed))
+		}
+	case []byte:
+		fieldFmt, err = newFieldFmt(asetypes.LONGBINARY, rpcLength(len(typed), output))
+		if err == nil {
+			value = emptyValue(fieldFmt, typed)
+		}
+	case time.Time:
+		fieldFmt, err = newFieldFmt(asetypes.BIGDATETIMEN, 8)
+	case time.Duration:
+		fieldFmt, err = newFieldFmt(asetypes.BIGTIMEN, 8)
+		if err == nil {
+			value, err = timeOfDay(typed)
+		}
+	case *asetypes.Decimal:
+		fieldFmt, err = newFieldFmt(asetypes.DECN, int64(typed.ByteSize()),
+			byte(typed.Precision), byte(typed.Scale))
+	case *big.Int, *big.Rat:
+		dec, decErr := bigDecimalExact(typed)
+		if decErr != nil {
+			return nil, nil, decErr
+		}
+		return rpcParamFmt(dec, output)
+	default:
+		return nil, nil, fmt.Errorf("unsupported type %T", value)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fieldFmt, value, nil
+}
+
+// rpcLength returns the maximum length for string and byte slice
+// parameters.
+func rpcLength(length int, output bool) int64 {
+	if output && length < rpcOutputLength {
+		return rpcOutputLength
+	}
+
+	if length == 0 {
+		return 1
+	}
+
+	return int64(length)
+}
+
+// newFieldFmt returns the format for dataType with the passed maximum
+// length and additional format bytes, e.g. precision and scale.
+//
+// The maximum length of formats returned by tds.LookupFieldFmt cannot
+// be set, hence the format is read from a buffer the same way formats
+// sent by the server are read.
+func newFieldFmt(dataType asetypes.DataType, maxLength int64, extra ...byte) (tds.FieldFmt, error) {
+	fieldFmt, err := tds.LookupFieldFmt(dataType)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up field format: %w", err)
+	}
+
+	if fieldFmt.IsFixedLength() {
+		return fieldFmt, nil
+	}
+
+	queue := tds.NewPacketQueue(func() int { return 512 })
+
+	switch fieldFmt.LengthBytes() {
+	case 4:
+		err = queue.WriteUint32(uint32(maxLength))
+	case 2:
+		err = queue.WriteUint16(uint16(maxLength))
+	default:
+		err = queue.WriteUint8(uint8(maxLength))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing maximum length: %w", err)
+	}
+
+	if err := queue.WriteBytes(extra); err != nil {
+		return nil, fmt.Errorf("error writing format: %w", err)
+	}
+
+	queue.SetPosition(0, 0)
+	if _, err := fieldFmt.ReadFrom(queue); err != nil {
+		return nil, fmt.Errorf("error reading format: %w", err)
+	}
+
+	return fieldFmt, nil
+}
+
+var (
+	procCallRe  = regexp.MustCompile(`(?is)^\s*exec(?:ute)?\s+([a-z_#][\w.#$@]*)\s*(.*?)\s*;?\s*$`)
+	procParamRe = regexp.MustCompile(`^(?:(@\w+)\s*=\s*)?\?$`)
+)
+
+// parseProcCall reports whether query is a bare procedure call in the
+// form `exec proc ?, @name = ?` and returns the procedure name and the
+// parameters with the passed arguments.
+// Calls with literals or other statements are not recognized.
+func parseProcCall(query string, args []driver.NamedValue) (string, []Param, bool) {
+	match := procCallRe.FindStringSubmatch(query)
+	if match == nil {
+		return "", nil, false
+	}
+
+	proc, rest := match[1], match[2]
+
+	if rest == "" {
+		return proc, nil, len(args) == 0
+	}
+
+	placeholders := strings.Split(rest, ",")
+	if len(placeholders) != len(args) {
+		return "", nil, false
+	}
+
+	params := make([]Param, len(args))
+	for i, placeholder := range placeholders {
+		paramMatch := procParamRe.FindStringSubmatch(strings.TrimSpace(placeholder))
+		if paramMatch == nil {
+			return "", nil, false
+		}
+
+		params[i] = Param{Name: paramMatch[1], Value: args[i].Value}
+		if args[i].Name != "" {
+			params[i].Name = args[i].Name
+		}
+	}
+
+	return proc, params, true
+}
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestRPCPackage(t *testing.T) {
+	cases := map[string]rpcPackage{
+		"no params": {Name: "sp_who", Options: rpcUnused},
+		"params":    {Name: "dbo.proc", Options: rpcParams},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			queue := tds.NewPacketQueue(func() int { return 512 })
+
+			if err := cas.WriteTo(queue); err != nil {
+				t.Errorf("Error writing package: %v", err)
+				return
+			}
+
+			queue.SetPosition(0, 0)
+
+			token, err := queue.Byte()
+			if err != nil {
+				t.Errorf("Error reading token: %v", err)
+				return
+			}
+
+			if tds.Token(token) != tds.TDS_DBRPC {
+				t.Errorf("Expected token %s, received %s", tds.TDS_DBRPC, tds.Token(token))
+				return
+			}
+
+			recv := rpcPackage{}
+			if err := recv.ReadFrom(queue); err != nil {
+				t.Errorf("Error reading package: %v", err)
+				return
+			}
+
+			if recv != cas {
+				t.Errorf("Expected %v, received %v", cas, recv)
+			}
+		})
+	}
+}
+
+func TestRPCParamFmt(t *testing.T) {
+	cases := map[string]struct {
+		value     interface{}
+		output    bool
+		dataType  asetypes.DataType
+		maxLength int64
+	}{
+		"int":            {5, false, asetypes.INTN, 8},
+		"real":           {float32(0.5), false, asetypes.FLTN, 4},
+		"float":          {0.5, false, asetypes.FLTN, 8},
+		"real override":  {Real(0.5), false, asetypes.FLTN, 4},
+		"float override": {Float(0.5), false, asetypes.FLTN, 8},
+		"string":         {"abc", false, asetypes.LONGCHAR, 3},
+		"output string":  {"", true, asetypes.LONGCHAR, rpcOutputLength},
+		"bytes":          {[]byte{1, 2}, false, asetypes.LONGBINARY, 2},
+		"null":           {nil, false, asetypes.VARCHAR, 255},
+		"duration":       {time.Hour, false, asetypes.BIGTIMEN, 8},
+		"big int":        {big.NewInt(12345), false, asetypes.DECN, 4},
+		"big rat":        {big.NewRat(5, 4), false, asetypes.DECN, 3},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, _, err := rpcParamFmt(cas.value, cas.output)
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if fieldFmt.DataType() != cas.dataType {
+				t.Errorf("Expected data type %s, received %s", cas.dataType, fieldFmt.DataType())
+			}
+
+			if fieldFmt.MaxLength() != cas.maxLength {
+				t.Errorf("Expected maximum length %d, received %d", cas.maxLength, fieldFmt.MaxLength())
+			}
+		})
+	}
+}
+
+func TestConn_CheckNamedValue_FloatOverride(t *testing.T) {
+	conn := &Conn{}
+
+	for _, value := range []interface{}{Real(0.5), Float(0.5)} {
+		nv := &driver.NamedValue{Value: value}
+		if err := conn.CheckNamedValue(nv); err != nil {
+			t.Errorf("Received unexpected error: %v", err)
+			continue
+		}
+
+		if nv.Value != value {
+			t.Errorf("Expected %v (%T) to be passed unchanged, received %v (%T)", value, value, nv.Value, nv.Value)
+		}
+	}
+}
+
+func TestParseProcCall(t *testing.T) {
+	cases := map[string]struct {
+		query  string
+		args   []driver.NamedValue
+		proc   string
+		params []Param
+		ok     bool
+	}{
+		"no params": {
+			query: "exec sp_who",
+			proc:  "sp_who",
+			ok:    true,
+		},
+		"execute": {
+			query: "  EXECUTE dbo.proc;",
+			proc:  "dbo.proc",
+			ok:    true,
+		},
+		"positional": {
+			query:  "exec proc ?, ?",
+			args:   []driver.NamedValue{{Ordinal: 1, Value: 1}, {Ordinal: 2, Value: "a"}},
+			proc:   "proc",
+			params: []Param{{Value: 1}, {Value: "a"}},
+			ok:     true,
+		},
+		"named": {
+			query:  "exec proc @a = ?, ?",
+			args:   []driver.NamedValue{{Ordinal: 1, Value: 1}, {Name: "b", Ordinal: 2, Value: 2}},
+			proc:   "proc",
+			params: []Param{{Name: "@a", Value: 1}, {Name: "b", Value: 2}},
+			ok:     true,
+		},
+		"literal": {
+			query: "exec proc 1",
+		},
+		"argument count": {
+			query: "exec proc ?",
+			args:  []driver.NamedValue{{Ordinal: 1, Value: 1}, {Ordinal: 2, Value: 2}},
+		},
+		"other statement": {
+			query: "select 1",
+		},
+		"multiple statements": {
+			query: "exec proc ?; select 1",
+			args:  []driver.NamedValue{{Ordinal: 1, Value: 1}},
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			proc, params, ok := parseProcCall(cas.query, cas.args)
+			if ok != cas.ok {
+				t.Errorf("Expected ok to be %t, received %t", cas.ok, ok)
+				return
+			}
+
+			if !ok {
+				return
+			}
+
+			if proc != cas.proc {
+				t.Errorf("Expected procedure %q, received %q", cas.proc, proc)
+			}
+
+			if !reflect.DeepEqual(params, cas.params) {
+				t.Errorf("Expected parameters %v, received %v", cas.params, params)
+			}
+		})
+	}
+}
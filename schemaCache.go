@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// ColumnInfo describes a column of a table, see TableColumns.
+type ColumnInfo struct {
+	Name string
+	// DatabaseTypeName is the type as reported by
+	// ColumnTypeDatabaseTypeName, e.g. INT4 or SYSNAME.
+	DatabaseTypeName string
+	// Length is the maximum length of the column.
+	Length   int64
+	Nullable bool
+	Identity bool
+}
+
+// schemaCache caches the columns of tables by table name.
+type schemaCache struct {
+	sync.Mutex
+	tables map[string][]ColumnInfo
+}
+
+func newSchemaCache() *schemaCache {
+	return &schemaCache{tables: map[string][]ColumnInfo{}}
+}
+
+func (cache *schemaCache) get(tableName string) ([]ColumnInfo, bool) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	columns, ok := cache.tables[tableName]
+	return columns, ok
+}
+
+func (cache *schemaCache) set(tableName string, columns []ColumnInfo) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	cache.tables[tableName] = columns
+}
+
+func (cache *schemaCache) invalidate(tableNames ...string) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if len(tableNames) == 0 {
+		cache.tables = map[string][]ColumnInfo{}
+		return
+	}
+
+	for _, tableName := range tableNames {
+		delete(cache.tables, tableName)
+	}
+}
+
+// EnableSchemaCache enables caching the columns returned by
+// TableColumns on connections opened by the connector. The columns of
+// a table are retrieved once and shared by all connections until they
+// are invalidated with InvalidateSchemaCache.
+//
+// Tables are cached by the name passed to TableColumns. Unqualified
+// names of tables in different databases share the same entry, hence
+// tables should be qualified with the database if connections switch
+// databases.
+//
+// The cache must be enabled before the connector is used.
+func (c *Connector) EnableSchemaCache() {
+	if c.schemaCache == nil {
+		c.schemaCache = newSchemaCache()
+	}
+}
+
+// InvalidateSchemaCache removes the passed tables from the schema cache,
+// e.g. after altering them. Without table names the whole cache is
+// invalidated.
+func (c *Connector) InvalidateSchemaCache(tableNames ...string) {
+	if c.schemaCache == nil {
+		return
+	}
+
+	c.schemaCache.invalidate(tableNames...)
+}
+
+// TableColumns returns the columns of the table tableName in the order
+// of their definition.
+//
+// If the connection was opened by a connector with EnableSchemaCache
+// the columns are retrieved from the server only on the first call for
+// a table.
+//
+// The table name is used as-is and must be quoted by the caller if
+// required, see QuoteIdentifier.
+func (c *Conn) TableColumns(ctx context.Context, tableName string) ([]ColumnInfo, error) {
+	if c.schemaCache != nil {
+		if columns, ok := c.schemaCache.get(tableName); ok {
+			return append([]ColumnInfo{}, columns...), nil
+		}
+	}
+
+	rows, _, err := c.language(ctx, fmt.Sprintf("select * from %s where 1 = 0", tableName))
+	if err != nil {
+		return nil, fmt.Errorf("go-ase: error retrieving columns of %s: %w", tableName, err)
+	}
+	defer rows.Close()
+
+	rowFmt := rows.(*Rows).RowFmt
+	if rowFmt == nil {
+		return nil, fmt.Errorf("go-ase: error retrieving columns of %s: no column format received", tableName)
+	}
+
+	columns := make([]ColumnInfo, len(rowFmt.Fmts))
+	for i, fieldFmt := range rowFmt.Fmts {
+		status := tds.RowFmtStatus(fieldFmt.Status())
+		columns[i] = ColumnInfo{
+			Name:             fieldFmt.Name(),
+			DatabaseTypeName: databaseTypeName(fieldFmt),
+			Length:           fieldFmt.MaxLength(),
+			Nullable:         status&tds.TDS_ROW_NULLALLOWED == tds.TDS_ROW_NULLALLOWED,
+			Identity:         status&tds.TDS_ROW_IDENTITY == tds.TDS_ROW_IDENTITY,
+		}
+	}
+
+	if c.schemaCache != nil {
+		c.schemaCache.set(tableName, columns)
+	}
+
+	return append([]ColumnInfo{}, columns...), nil
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"testing"
+)
+
+func TestSchemaCache(t *testing.T) {
+	cases := map[string]struct {
+		invalidate []string
+		expectA    bool
+		expectB    bool
+	}{
+		"single table": {[]string{"a"}, false, true},
+		"unknown":      {[]string{"c"}, true, true},
+		"all":          {nil, false, false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			cache := newSchemaCache()
+			cache.set("a", []ColumnInfo{{Name: "x"}})
+			cache.set("b", []ColumnInfo{{Name: "y"}})
+
+			cache.invalidate(cas.invalidate...)
+
+			if _, ok := cache.get("a"); ok != cas.expectA {
+				t.Errorf("Expected table a cached %t, received %t", cas.expectA, ok)
+			}
+
+			if _, ok := cache.get("b"); ok != cas.expectB {
+				t.Errorf("Expected table b cached %t, received %t", cas.expectB, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestSendRPC(t *testing.T) {
+	integration.TestForEachDB("TestSendRPC", t, testSendRPC)
+}
+
+func testSendRPC(t *testing.T, db *sql.DB, tableName string) {
+	procName := tableName + "_proc"
+
+	if _, err := db.Exec(fmt.Sprintf("create procedure %s @a int, @b varchar(10), @out int output as select @b; select @out = @a * 2", procName)); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		rows, result, err := c.SendRPC(context.Background(), procName, []Param{
+			{Name: "a", Value: 21},
+			{Name: "@b", Value: "rpc"},
+			{Name: "out", Value: 0, Output: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error sending RPC: %w", err)
+		}
+
+		values := make([]driver.Value, 1)
+		if err := rows.Next(values); err != nil {
+			return fmt.Errorf("error reading row: %w", err)
+		}
+		b, _ := values[0].(string)
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		if b != "rpc" {
+			return fmt.Errorf("expected selected value %q, received %q", "rpc", b)
+		}
+
+		if out, _ := result.OutputParam("@out"); out != int64(42) {
+			return fmt.Errorf("expected output parameter 42, received %v (%T)", out, out)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestSendLanguage(t *testing.T) {
+	integration.TestForEachDB("TestSendLanguage", t, testSendLanguage)
+}
+
+func testSendLanguage(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		rows, _, err := c.SendLanguage(context.Background(), "select 1")
+		if err != nil {
+			return fmt.Errorf("error sending language command: %w", err)
+		}
+		defer rows.Close()
+
+		values := make([]driver.Value, 1)
+		if err := rows.Next(values); err != nil {
+			return fmt.Errorf("error reading row: %w", err)
+		}
+
+		if values[0] != int32(1) {
+			return fmt.Errorf("expected 1, received %v (%T)", values[0], values[0])
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestExecRPC(t *testing.T) {
+	integration.TestForEachDB("TestExecRPC", t, testExecRPC)
+}
+
+func testExecRPC(t *testing.T, db *sql.DB, tableName string) {
+	procName := tableName + "_proc"
+
+	if _, err := db.Exec(fmt.Sprintf("create procedure %s @a int, @b varchar(10) as select @a * 2, @b", procName)); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		rpc := c.Info.RPC
+		defer func() { c.Info.RPC = rpc }()
+
+		results := map[bool][]driver.Value{}
+		for _, useRPC := range []bool{false, true} {
+			c.Info.RPC = useRPC
+
+			rows, _, err := c.DirectExec(context.Background(), fmt.Sprintf("exec %s @a = ?, @b = ?", procName), 21, "rpc")
+			if err != nil {
+				return fmt.Errorf("error executing procedure with rpc=%t: %w", useRPC, err)
+			}
+
+			values := make([]driver.Value, 2)
+			if err := rows.Next(values); err != nil {
+				rows.Close()
+				return fmt.Errorf("error reading row with rpc=%t: %w", useRPC, err)
+			}
+			rows.Close()
+
+			results[useRPC] = values
+		}
+
+		if !reflect.DeepEqual(results[false], results[true]) {
+			return fmt.Errorf("expected equal results, received %v with language and %v with RPC", results[false], results[true])
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestCallProc(t *testing.T) {
+	integration.TestForEachDB("TestCallProc", t, testCallProc)
+}
+
+func testCallProc(t *testing.T, db *sql.DB, tableName string) {
+	procName := tableName + "_proc"
+
+	if _, err := db.Exec(fmt.Sprintf("create procedure %s @a int, @b varchar(10), @out int output, @label varchar(20) output as select @out = @a * 2, @label = @b + '!'", procName)); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		var out int
+		var label string
+		rows, _, err := c.CallProc(context.Background(), procName, map[string]interface{}{
+			"@a":     21,
+			"@b":     "map",
+			"@out":   &out,
+			"@label": &label,
+		})
+		if err != nil {
+			return fmt.Errorf("error calling procedure with map: %w", err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		if out != 42 || label != "map!" {
+			return fmt.Errorf("expected output parameters 42 and %q, received %d and %q", "map!", out, label)
+		}
+
+		params := struct {
+			A     int    `ase:"@a"`
+			B     string `ase:"@b"`
+			Out   int    `ase:"@out,output"`
+			Label string `ase:"@label,output"`
+		}{A: 5, B: "struct"}
+
+		rows, _, err = c.CallProc(context.Background(), procName, &params)
+		if err != nil {
+			return fmt.Errorf("error calling procedure with struct: %w", err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		if params.Out != 10 || params.Label != "struct!" {
+			return fmt.Errorf("expected output parameters 10 and %q, received %d and %q", "struct!", params.Out, params.Label)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestRPCOutputParams(t *testing.T) {
+	integration.TestForEachDB("TestRPCOutputParams", t, testRPCOutputParams)
+}
+
+func testRPCOutputParams(t *testing.T, db *sql.DB, tableName string) {
+	procName := tableName + "_proc"
+
+	if _, err := db.Exec(fmt.Sprintf(`create procedure %s @a int output, @in int, @b varchar(10) output, @c int output as
+		select @a = @in + 1
+		select 'first'
+		select @b = 'two'
+		select 'second'
+		select @c = @in + 3`, procName)); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		rows, result, err := c.SendRPC(context.Background(), procName, []Param{
+			{Name: "@a", Value: 0, Output: true},
+			{Name: "@in", Value: 1},
+			{Name: "@b", Value: "", Output: true},
+			{Name: "@c", Value: 0, Output: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error sending RPC: %w", err)
+		}
+
+		resultSets := 0
+		values := make([]driver.Value, 1)
+		for {
+			for rows.Next(values) == nil {
+			}
+			resultSets++
+
+			if err := rows.NextResultSet(); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return fmt.Errorf("error switching result set: %w", err)
+			}
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		if resultSets != 2 {
+			return fmt.Errorf("expected 2 result sets, received %d", resultSets)
+		}
+
+		expect := []driver.NamedValue{
+			{Name: "@a", Ordinal: 1, Value: int64(2)},
+			{Name: "@b", Ordinal: 3, Value: "two"},
+			{Name: "@c", Ordinal: 4, Value: int64(4)},
+		}
+
+		if params := result.OutputParams(); !reflect.DeepEqual(params, expect) {
+			return fmt.Errorf("expected output parameters %v, received %v", expect, params)
+		}
+
+		if b, ok := result.OutputParam("b"); !ok || b != "two" {
+			return fmt.Errorf("expected output parameter @b %q, received %v", "two", b)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestOutputOnlyProc(t *testing.T) {
+	integration.TestForEachDB("TestOutputOnlyProc", t, testOutputOnlyProc)
+}
+
+func testOutputOnlyProc(t *testing.T, db *sql.DB, tableName string) {
+	procName := tableName + "_proc"
+
+	if _, err := db.Exec(fmt.Sprintf("create procedure %s @out int output as select @out = 42", procName)); err != nil {
+		t.Errorf("Error creating procedure: %v", err)
+		return
+	}
+	defer db.Exec("drop procedure " + procName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		rows, result, err := c.SendRPC(context.Background(), procName, []Param{
+			{Name: "@out", Value: 0, Output: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error sending RPC: %w", err)
+		}
+		defer rows.Close()
+
+		// Without result sets the output parameters are received
+		// before SendRPC returns.
+		if out, ok := result.OutputParam("@out"); !ok || out != int64(42) {
+			return fmt.Errorf("expected output parameter 42 before reading rows, received %v (%T)", out, out)
+		}
+
+		if err := rows.Next(nil); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected io.EOF, received %v", err)
+		}
+
+		if err := rows.NextResultSet(); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected no result set, received %v", err)
+		}
+
+		var out int
+		rows, _, err = c.CallProc(context.Background(), procName, map[string]interface{}{
+			"@out": &out,
+		})
+		if err != nil {
+			return fmt.Errorf("error calling procedure: %w", err)
+		}
+
+		if err := rows.Next(nil); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("expected io.EOF, received %v", err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		if out != 42 {
+			return fmt.Errorf("expected output parameter 42, received %d", out)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Interface satisfaction checks.
+var (
+	_ fmt.Stringer   = SensitiveValue{}
+	_ fmt.GoStringer = SensitiveValue{}
+)
+
+// SensitiveValue is a parameter value marked as sensitive by Sensitive.
+type SensitiveValue struct {
+	value interface{}
+}
+
+// Sensitive marks the parameter value as sensitive, e.g. a password:
+//
+//	db.Exec("insert into credentials values (?, ?)", user, ase.Sensitive(password))
+//
+// Sensitive values are passed to the QueryLogger as RedactedValue,
+// independent of the QueryRedactor. Formatting a SensitiveValue with
+// the fmt package yields RedactedValue as well.
+//
+// The value is transmitted like any other value - go-dblib does not
+// implement the encryption of parameters, use TLS to encrypt the
+// connection instead.
+func Sensitive(value interface{}) SensitiveValue {
+	return SensitiveValue{value: value}
+}
+
+// String implements the fmt.Stringer interface.
+func (SensitiveValue) String() string {
+	return RedactedValue
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (SensitiveValue) GoString() string {
+	return RedactedValue
+}
+
+// unwrapSensitive returns the value marked by Sensitive and whether the
+// value was marked.
+func unwrapSensitive(value interface{}) (interface{}, bool) {
+	if sensitive, ok := value.(SensitiveValue); ok {
+		return sensitive.value, true
+	}
+	return value, false
+}
+
+// checkSensitive calls check with the value marked by Sensitive and
+// marks the checked value again, so that it is redacted when logged.
+func checkSensitive(nv *driver.NamedValue, check func(*driver.NamedValue) error) (bool, error) {
+	value, ok := unwrapSensitive(nv.Value)
+	if !ok {
+		return false, nil
+	}
+
+	inner := *nv
+	inner.Value = value
+	if err := check(&inner); err != nil {
+		return true, err
+	}
+
+	nv.Value = Sensitive(inner.Value)
+	return true, nil
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestSensitive(t *testing.T) {
+	cases := map[string]struct {
+		value  interface{}
+		expect driver.Value
+	}{
+		"string": {"secret", "secret"},
+		"int":    {5, int64(5)},
+		"nil":    {nil, nil},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			nv := driver.NamedValue{Ordinal: 1, Value: Sensitive(cas.value)}
+
+			conn := &Conn{}
+			if err := conn.CheckNamedValue(&nv); err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if !isSensitive(nv.Value) {
+				t.Errorf("Expected checked value to stay marked as sensitive, received %T", nv.Value)
+				return
+			}
+
+			if value, _ := unwrapSensitive(nv.Value); value != cas.expect {
+				t.Errorf("Expected %v (%T), received %v (%T)", cas.expect, cas.expect, value, value)
+			}
+
+			for _, format := range []string{"%v", "%s", "%#v"} {
+				if s := fmt.Sprintf(format, nv.Value); s != RedactedValue {
+					t.Errorf("Expected %s to format as %q, received %q", format, RedactedValue, s)
+				}
+			}
+		})
+	}
+}
+
+func TestSensitive_RPC(t *testing.T) {
+	conn := &Conn{}
+
+	fieldFmts, fieldData, err := conn.rpcParamFields([]Param{{Name: "password", Value: Sensitive("secret")}})
+	if err != nil {
+		t.Errorf("Received unexpected error: %v", err)
+		return
+	}
+
+	if fieldFmts[0].Name() != "@password" {
+		t.Errorf("Expected parameter name @password, received %s", fieldFmts[0].Name())
+	}
+
+	if value, ok := fieldData[0].Value().([]byte); !ok || string(value) != "secret" {
+		t.Errorf("Expected unmarked value to be sent, received %v", fieldData[0].Value())
+	}
+}
@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+var (
+	noCountRe    = regexp.MustCompile(`(?i)\bset\s+nocount\s+(on|off)\b`)
+	createProcRe = regexp.MustCompile(`(?i)^\s*create\s+proc`)
+)
+
+// dateFormats are the date part orders accepted by `set dateformat`.
+var dateFormats = map[string]bool{
+	"mdy": true,
+	"dmy": true,
+	"ymd": true,
+	"ydm": true,
+	"myd": true,
+	"dym": true,
+}
+
+// checkDateFormat returns an error if format is not empty and not
+// a date part order accepted by ASE.
+func checkDateFormat(format string) error {
+	if format != "" && !dateFormats[format] {
+		return fmt.Errorf("go-ase: invalid dateformat %q, expected one of mdy, dmy, ymd, ydm, myd or dym", format)
+	}
+	return nil
+}
+
+// sessionEnvChangeHook is registered as an EnvChangeHook on the
+// connection and records the language and character set of the
+// session.
+func (c *Conn) sessionEnvChangeHook(typ tds.EnvChangeType, oldValue, newValue string) {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	switch typ {
+	case tds.TDS_ENV_LANG:
+		c.sessionLanguage = newValue
+	case tds.TDS_ENV_CHARSET:
+		c.sessionCharset = newValue
+	}
+}
+
+// setDateFormat sets the dateformat of the session.
+func (c *Conn) setDateFormat(ctx context.Context, format string) error {
+	if err := checkDateFormat(format); err != nil {
+		return err
+	}
+
+	if _, err := c.ExecContext(ctx, "set dateformat "+format, nil); err != nil {
+		return fmt.Errorf("go-ase: error setting dateformat %s: %w", format, err)
+	}
+
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	c.sessionDateFormat = format
+	return nil
+}
+
+// Language returns the language of server messages of the session as
+// reported by the server.
+func (c *Conn) Language() string {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	return c.sessionLanguage
+}
+
+// DateFormat returns the order of date parts used for string-to-date
+// conversions set through the dateformat property, or an empty string
+// if the default order of the session language is used.
+func (c *Conn) DateFormat() string {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	return c.sessionDateFormat
+}
+
+// trackNoCount records the nocount setting of the session if query
+// sets it. Statements in the body of a procedure being created do not
+// change the setting of the session.
+func (c *Conn) trackNoCount(query string) {
+	if createProcRe.MatchString(query) {
+		return
+	}
+
+	matches := noCountRe.FindAllStringSubmatch(query, -1)
+	if len(matches) == 0 {
+		return
+	}
+
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	c.sessionNoCount = strings.EqualFold(matches[len(matches)-1][1], "on")
+}
+
+// NoCount reports if `set nocount on` is active on the session, in
+// which case the server does not report the number of affected rows
+// and RowsAffected returns ErrRowsAffectedUnavailable.
+//
+// The setting is tracked from the language commands executed through
+// the driver. Changes made by other means, e.g. by a login trigger,
+// are not detected.
+func (c *Conn) NoCount() bool {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	return c.sessionNoCount
+}
+
+// SetNoCount enables or disables `set nocount` on the session.
+func (c *Conn) SetNoCount(ctx context.Context, on bool) error {
+	query := "set nocount off"
+	if on {
+		query = "set nocount on"
+	}
+
+	if err := c.execNoRows(ctx, query); err != nil {
+		return fmt.Errorf("go-ase: error executing %q: %w", query, err)
+	}
+
+	return nil
+}
+
+// serverCharsetQuery selects the names of the default character set
+// and sort order of the server. Sort orders are identified by their
+// ID and the ID of their character set.
+const serverCharsetQuery = `select
+	(select name from master..syscharsets where id = cs.value and type < 2000),
+	(select name from master..syscharsets where id = so.value and csid = cs.value and type >= 2000)
+from master..sysconfigures cs, master..sysconfigures so
+where cs.name = 'default character set id' and so.name = 'default sortorder id'`
+
+// selectServerCharset selects the default character set and sort order
+// of the server if they were not selected before.
+func (c *Conn) selectServerCharset(ctx context.Context) error {
+	c.sessionLock.Lock()
+	selected := c.serverCharsetSelected
+	c.sessionLock.Unlock()
+
+	if selected {
+		return nil
+	}
+
+	rows, _, err := c.language(ctx, serverCharsetQuery)
+	if err != nil {
+		return fmt.Errorf("go-ase: error selecting server character set: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 2)
+	if err := rows.Next(values); err != nil {
+		return fmt.Errorf("go-ase: error reading server character set: %w", err)
+	}
+
+	charset, _ := values[0].(string)
+	sortOrder, _ := values[1].(string)
+
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	c.serverCharset = strings.TrimSpace(charset)
+	c.serverSortOrder = strings.TrimSpace(sortOrder)
+	c.serverCharsetSelected = true
+	return nil
+}
+
+// Charset returns the character set of the session as reported by the
+// server, e.g. "utf8", or an empty string if the server did not report
+// it. Character data is exchanged in this character set.
+//
+// If it differs from ServerCharset the server converts character data,
+// characters that do not exist in either character set are replaced.
+func (c *Conn) Charset() string {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	return c.sessionCharset
+}
+
+// ServerCharset returns the default character set of the server, e.g.
+// "iso_1", in which character data is stored.
+//
+// The character set and sort order are selected from the server on
+// the first call of ServerCharset or ServerSortOrder.
+func (c *Conn) ServerCharset(ctx context.Context) (string, error) {
+	if err := c.selectServerCharset(ctx); err != nil {
+		return "", err
+	}
+
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	return c.serverCharset, nil
+}
+
+// ServerSortOrder returns the default sort order of the server, e.g.
+// "bin_iso_1", which determines how character data is compared and
+// sorted.
+//
+// The character set and sort order are selected from the server on
+// the first call of ServerCharset or ServerSortOrder.
+func (c *Conn) ServerSortOrder(ctx context.Context) (string, error) {
+	if err := c.selectServerCharset(ctx); err != nil {
+		return "", err
+	}
+
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	return c.serverSortOrder, nil
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedVar is returned by SetVar and GetVar for names of
+// variables that are not supported.
+var ErrUnsupportedVar = errors.New("go-ase: unsupported session variable")
+
+// sessionVar is a session setting supported by SetVar and GetVar.
+type sessionVar struct {
+	// set is the statement setting the variable, with a verb for the
+	// value.
+	set string
+	// integer is set for variables with integer values, all others
+	// have string values.
+	integer bool
+}
+
+// sessionVars are the supported variables by the name of the global
+// variable holding their value, without the leading @@.
+var sessionVars = map[string]sessionVar{
+	"textsize":             {"set textsize %s", true},
+	"lock_timeout":         {"set lock wait %s", true},
+	"isolation":            {"set transaction isolation level %s", true},
+	"datefirst":            {"set datefirst %s", true},
+	"parallel_degree":      {"set parallel_degree %s", true},
+	"scan_parallel_degree": {"set scan_parallel_degree %s", true},
+	"clientname":           {"set clientname %s", false},
+	"clienthostname":       {"set clienthostname %s", false},
+}
+
+// lookupSessionVar returns the normalized name and the definition of
+// the variable name.
+func lookupSessionVar(name string) (string, sessionVar, error) {
+	name = strings.ToLower(strings.TrimPrefix(name, "@@"))
+
+	v, ok := sessionVars[name]
+	if !ok {
+		return "", sessionVar{}, fmt.Errorf("%w: %q", ErrUnsupportedVar, name)
+	}
+
+	return name, v, nil
+}
+
+// setVarQuery returns the statement setting the variable name to
+// value.
+func setVarQuery(name string, value interface{}) (string, error) {
+	name, v, err := lookupSessionVar(name)
+	if err != nil {
+		return "", err
+	}
+
+	if !v.integer {
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("go-ase: session variable %s expects a string, received %T", name, value)
+		}
+		return fmt.Sprintf(v.set, QuoteLiteral(s)), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf(v.set, strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf(v.set, strconv.FormatUint(rv.Uint(), 10)), nil
+	default:
+		return "", fmt.Errorf("go-ase: session variable %s expects an integer, received %T", name, value)
+	}
+}
+
+// SetVar sets the session variable name, e.g. textsize or
+// lock_timeout, to value through the corresponding set statement. The
+// setting persists for the session.
+//
+// Only the variables listed in the README are supported, other names
+// return ErrUnsupportedVar. Names are accepted with or without the
+// leading @@. Integer variables expect an integer value, e.g. an int,
+// the others a string.
+func (c *Conn) SetVar(ctx context.Context, name string, value interface{}) error {
+	query, err := setVarQuery(name, value)
+	if err != nil {
+		return err
+	}
+
+	return c.execNoRows(ctx, query)
+}
+
+// GetVar returns the value of the session variable name, see SetVar.
+// Values of integer variables are returned as int64, the others as
+// string. NULL is returned as nil.
+func (c *Conn) GetVar(ctx context.Context, name string) (driver.Value, error) {
+	name, v, err := lookupSessionVar(name)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "select @@" + name
+	if v.integer {
+		query = fmt.Sprintf("select convert(bigint, @@%s)", name)
+	}
+
+	rows, _, err := c.language(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("go-ase: error selecting @@%s: %w", name, err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return nil, fmt.Errorf("go-ase: error reading @@%s: %w", name, err)
+	}
+
+	return values[0], nil
+}
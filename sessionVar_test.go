@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetVarQuery(t *testing.T) {
+	cases := map[string]struct {
+		name        string
+		value       interface{}
+		expect      string
+		expectErr   bool
+		unsupported bool
+	}{
+		"integer":        {"textsize", 1024, "set textsize 1024", false, false},
+		"global name":    {"@@lock_timeout", int64(-1), "set lock wait -1", false, false},
+		"case":           {"ISOLATION", uint8(3), "set transaction isolation level 3", false, false},
+		"string":         {"clientname", "it's", "set clientname 'it''s'", false, false},
+		"string for int": {"textsize", "1024", "", true, false},
+		"int for string": {"clientname", 1, "", true, false},
+		"unsupported":    {"rowcount", 1, "", true, true},
+		"injection":      {"textsize 1 drop table t --", 1, "", true, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			query, err := setVarQuery(cas.name, cas.value)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if errors.Is(err, ErrUnsupportedVar) != cas.unsupported {
+				t.Errorf("Expected ErrUnsupportedVar %t, received %v", cas.unsupported, err)
+			}
+
+			if query != cas.expect {
+				t.Errorf("Expected query %q, received %q", cas.expect, query)
+			}
+		})
+	}
+}
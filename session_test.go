@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestCheckDateFormat(t *testing.T) {
+	cases := map[string]struct {
+		format string
+		valid  bool
+	}{
+		"empty":     {"", true},
+		"mdy":       {"mdy", true},
+		"dmy":       {"dmy", true},
+		"ydm":       {"ydm", true},
+		"uppercase": {"DMY", false},
+		"invalid":   {"dd.mm.yyyy", false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			err := checkDateFormat(cas.format)
+			if cas.valid && err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+			}
+			if !cas.valid && err == nil {
+				t.Errorf("Expected error for dateformat %q", cas.format)
+			}
+		})
+	}
+}
+
+func TestConn_sessionEnvChangeHook(t *testing.T) {
+	c := &Conn{sessionLock: &sync.Mutex{}}
+
+	c.sessionEnvChangeHook(tds.TDS_ENV_LANG, "", "us_english")
+	c.sessionEnvChangeHook(tds.TDS_ENV_DB, "master", "test")
+
+	if lang := c.Language(); lang != "us_english" {
+		t.Errorf("Expected language %q, received %q", "us_english", lang)
+	}
+
+	c.sessionEnvChangeHook(tds.TDS_ENV_LANG, "us_english", "german")
+
+	if lang := c.Language(); lang != "german" {
+		t.Errorf("Expected language %q, received %q", "german", lang)
+	}
+
+	c.sessionEnvChangeHook(tds.TDS_ENV_CHARSET, "", "utf8")
+
+	if charset := c.Charset(); charset != "utf8" {
+		t.Errorf("Expected charset %q, received %q", "utf8", charset)
+	}
+
+	if lang := c.Language(); lang != "german" {
+		t.Errorf("Expected language %q to be unchanged, received %q", "german", lang)
+	}
+}
+
+func TestConn_trackTransaction(t *testing.T) {
+	cases := map[string]struct {
+		statuses []tds.DoneState
+		expect   bool
+	}{
+		"no transaction": {
+			[]tds.DoneState{tds.TDS_DONE_FINAL},
+			false,
+		},
+		"begin": {
+			[]tds.DoneState{tds.TDS_DONE_INXACT, tds.TDS_DONE_FINAL},
+			true,
+		},
+		"begin and select": {
+			[]tds.DoneState{tds.TDS_DONE_INXACT, tds.TDS_DONE_FINAL, tds.TDS_DONE_MORE | tds.TDS_DONE_COUNT | tds.TDS_DONE_INXACT, tds.TDS_DONE_INXACT, tds.TDS_DONE_FINAL},
+			true,
+		},
+		"commit": {
+			[]tds.DoneState{tds.TDS_DONE_INXACT, tds.TDS_DONE_FINAL, tds.TDS_DONE_FINAL},
+			false,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{sessionLock: &sync.Mutex{}}
+
+			for _, status := range cas.statuses {
+				c.trackTransaction(&tds.DonePackage{Status: status})
+			}
+
+			if c.InTransaction() != cas.expect {
+				t.Errorf("Expected InTransaction to be %t", cas.expect)
+			}
+		})
+	}
+}
+
+func TestConn_trackNoCount(t *testing.T) {
+	cases := map[string]struct {
+		initial bool
+		query   string
+		expect  bool
+	}{
+		"on":               {false, "set nocount on", true},
+		"off":              {true, "set nocount off", false},
+		"case and spacing": {false, "SET  NoCount\tON", true},
+		"last wins":        {false, "set nocount on select 1 set nocount off", false},
+		"in batch":         {false, "set nocount on\nupdate tab set a = 1", true},
+		"unrelated":        {true, "select 1", true},
+		"other option":     {false, "set nocount_x on", false},
+		"procedure body":   {false, "create procedure p as set nocount on select 1", false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{sessionLock: &sync.Mutex{}, sessionNoCount: cas.initial}
+
+			c.trackNoCount(cas.query)
+
+			if c.NoCount() != cas.expect {
+				t.Errorf("Expected NoCount to be %t", cas.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// messagesEEDHook is registered as an EEDHook on the connection and
+// records the messages of the current statement.
+//
+// go-dblib only returns messages as an error if the processing of
+// a package fails, which consumes the remaining packages of the
+// command. The recorded messages allow reporting a failed statement
+// without discarding the result sets of the following statements, see
+// Rows.Next.
+func (c *Conn) messagesEEDHook(eed tds.EEDPackage) {
+	c.messagesLock.Lock()
+	defer c.messagesLock.Unlock()
+
+	c.messages = append(c.messages, &eed)
+}
+
+// takeMessages returns and clears the recorded messages.
+func (c *Conn) takeMessages() []*tds.EEDPackage {
+	c.messagesLock.Lock()
+	defer c.messagesLock.Unlock()
+
+	messages := c.messages
+	c.messages = nil
+	return messages
+}
+
+// statementError returns the error for a statement the server reported
+// as failed in done, with the messages recorded for the statement.
+func (c *Conn) statementError(done *tds.DonePackage) error {
+	_, err := handleDonePackage(done)
+	err = fmt.Errorf("go-ase: %w", err)
+
+	messages := c.takeMessages()
+	if len(messages) == 0 {
+		return err
+	}
+
+	aseErr := newError(err, messages)
+	if aseErr.isDeadlockVictim() {
+		c.abortTransaction()
+	}
+	return aseErr
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+func TestConn_statementError(t *testing.T) {
+	cases := map[string]struct {
+		messages        []*tds.EEDPackage
+		expectMsgNumber uint32
+		expectTxAborted bool
+	}{
+		"no messages": {nil, 0, false},
+		"error": {
+			[]*tds.EEDPackage{{MsgNumber: 249, Class: 16}},
+			249, false,
+		},
+		"info before error": {
+			[]*tds.EEDPackage{{MsgNumber: 0, Class: 10}, {MsgNumber: 249, Class: 16}},
+			249, false,
+		},
+		"deadlock": {
+			[]*tds.EEDPackage{{MsgNumber: msgDeadlockVictim, Class: 13}},
+			msgDeadlockVictim, true,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{messagesLock: &sync.Mutex{}, sessionLock: &sync.Mutex{}}
+			for _, msg := range cas.messages {
+				c.messagesEEDHook(*msg)
+			}
+
+			err := c.statementError(&tds.DonePackage{Status: tds.TDS_DONE_ERROR | tds.TDS_DONE_MORE})
+			if !errors.Is(err, ErrCommandFailed) {
+				t.Errorf("Expected ErrCommandFailed, received %v", err)
+			}
+
+			var aseErr *Error
+			if errors.As(err, &aseErr) {
+				if aseErr.MsgNumber != cas.expectMsgNumber {
+					t.Errorf("Expected message %d, received %d", cas.expectMsgNumber, aseErr.MsgNumber)
+				}
+				if len(aseErr.Messages) != len(cas.messages) {
+					t.Errorf("Expected %d messages, received %d", len(cas.messages), len(aseErr.Messages))
+				}
+			} else if cas.expectMsgNumber != 0 {
+				t.Errorf("Expected *Error, received %T", err)
+			}
+
+			if recv := c.takeTxAborted(); recv != cas.expectTxAborted {
+				t.Errorf("Expected aborted transaction %t, received %t", cas.expectTxAborted, recv)
+			}
+
+			if messages := c.takeMessages(); len(messages) != 0 {
+				t.Errorf("Expected messages to be cleared, received %d", len(messages))
+			}
+		})
+	}
+}
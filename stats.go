@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/SAP/go-dblib/tds"
+)
+
+// ExecStats are the server-side execution statistics ASE reports when
+// `set statistics io on` and/or `set statistics time on` is active.
+type ExecStats struct {
+	// LogicalReads, PhysicalReads and ScanCount are summed up over all
+	// tables accessed by the command.
+	LogicalReads  int64
+	PhysicalReads int64
+	ScanCount     int64
+
+	CPUTime     time.Duration
+	ElapsedTime time.Duration
+}
+
+var (
+	statsScanCountRe     = regexp.MustCompile(`scan count (\d+)`)
+	statsLogicalReadsRe  = regexp.MustCompile(`logical reads: (?:\(regular=\d+ apf=\d+ total=)?(\d+)`)
+	statsPhysicalReadsRe = regexp.MustCompile(`physical reads: (?:\(regular=\d+ apf=\d+ total=)?(\d+)`)
+	statsCPUTimeRe       = regexp.MustCompile(`cpu time: (\d+) ms`)
+	statsElapsedTimeRe   = regexp.MustCompile(`elapsed time: (\d+) ms`)
+)
+
+// add parses a message sent by ASE and adds any statistics contained
+// in the message.
+// It reports whether the message contained statistics.
+func (stats *ExecStats) add(msg string) bool {
+	found := false
+
+	parse := func(re *regexp.Regexp) int64 {
+		match := re.FindStringSubmatch(msg)
+		if match == nil {
+			return 0
+		}
+
+		i, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return 0
+		}
+
+		found = true
+		return i
+	}
+
+	stats.ScanCount += parse(statsScanCountRe)
+	stats.LogicalReads += parse(statsLogicalReadsRe)
+	stats.PhysicalReads += parse(statsPhysicalReadsRe)
+	stats.CPUTime += time.Duration(parse(statsCPUTimeRe)) * time.Millisecond
+	stats.ElapsedTime += time.Duration(parse(statsElapsedTimeRe)) * time.Millisecond
+
+	return found
+}
+
+// resetStats starts recording the statistics of a new command.
+func (c *Conn) resetStats() {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+
+	c.stats = &ExecStats{}
+}
+
+// currentStats returns the statistics of the current command.
+func (c *Conn) currentStats() *ExecStats {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+
+	return c.stats
+}
+
+// statsEEDHook is registered as an EEDHook on the connection and
+// records the statistics for the current command.
+func (c *Conn) statsEEDHook(eed tds.EEDPackage) {
+	c.statsLock.Lock()
+	defer c.statsLock.Unlock()
+
+	c.stats.add(eed.Msg)
+}
+
+// Stats returns the execution statistics reported by the server for
+// the command that produced the rows.
+//
+// The statistics are only complete once all result sets have been
+// consumed and only contain data if `set statistics io on` or `set
+// statistics time on` is active on the connection.
+func (rows Rows) Stats() ExecStats {
+	if rows.stats == nil {
+		return ExecStats{}
+	}
+
+	rows.Conn.statsLock.Lock()
+	defer rows.Conn.statsLock.Unlock()
+
+	return *rows.stats
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExecStats_add(t *testing.T) {
+	cases := map[string]struct {
+		msgs   []string
+		expect ExecStats
+	}{
+		"io": {
+			msgs: []string{
+				"Table: sysobjects scan count 1, logical reads: (regular=6 apf=0 total=6), physical reads: (regular=2 apf=1 total=3), apf IOs used=0",
+				"Table: syscolumns scan count 4, logical reads: (regular=10 apf=0 total=10), physical reads: (regular=0 apf=0 total=0), apf IOs used=0",
+				"Total writes for this command: 0",
+			},
+			expect: ExecStats{ScanCount: 5, LogicalReads: 16, PhysicalReads: 3},
+		},
+		"io legacy format": {
+			msgs: []string{
+				"Table: sysobjects scan count 1, logical reads: 6, physical reads: 2",
+			},
+			expect: ExecStats{ScanCount: 1, LogicalReads: 6, PhysicalReads: 2},
+		},
+		"time": {
+			msgs: []string{
+				"Execution Time 0.",
+				"Adaptive Server cpu time: 12 ms.  Adaptive Server elapsed time: 30 ms.",
+			},
+			expect: ExecStats{CPUTime: 12 * time.Millisecond, ElapsedTime: 30 * time.Millisecond},
+		},
+		"unrelated": {
+			msgs:   []string{"Changed database context to 'master'."},
+			expect: ExecStats{},
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			stats := ExecStats{}
+			for _, msg := range cas.msgs {
+				stats.add(msg)
+			}
+
+			if stats != cas.expect {
+				t.Errorf("Expected %+v, received %+v", cas.expect, stats)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+)
+
+// Stream reads the rows of the current result set in a goroutine and
+// sends them to the returned row channel, e.g. for pipelines
+// transforming rows as they arrive. Each row is a new slice owned by
+// the receiver.
+//
+// The row channel is closed at the end of the result set or when an
+// error occurs. The error is sent to the error channel, which is
+// closed afterwards without a value if the result set was read
+// completely.
+//
+// The row channel is unbuffered - the next row is only read from the
+// server once the previous row was received, hence a slow consumer
+// applies backpressure to the server instead of accumulating rows in
+// memory.
+//
+// Reading the rows is bound to ctx instead of the context the command
+// was executed with. If ctx ends before the result set was read
+// completely the command is aborted with an attention and an error
+// wrapping the error of ctx is sent.
+//
+// The rows must not be used until the error channel is closed. The
+// rows are not closed by Stream - after the error channel is closed
+// the next result set can be read through NextResultSet.
+func (rows *Rows) Stream(ctx context.Context) (<-chan []driver.Value, <-chan error) {
+	rowCh := make(chan []driver.Value)
+	errCh := make(chan error, 1)
+
+	rows.ctx = ctx
+
+	go func() {
+		defer close(errCh)
+		defer close(rowCh)
+
+		if err := rows.stream(ctx, rowCh); err != nil {
+			errCh <- err
+		}
+	}()
+
+	return rowCh, errCh
+}
+
+// stream sends the rows of the current result set to rowCh until the
+// result set ends or ctx ends.
+func (rows *Rows) stream(ctx context.Context, rowCh chan<- []driver.Value) error {
+	for {
+		values := make([]driver.Value, len(rows.Columns()))
+		if err := rows.Next(values); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case rowCh <- values:
+		case <-ctx.Done():
+			rows.finished = true
+			return rows.Conn.abortOnContext(ctx, ctx.Err())
+		}
+	}
+}
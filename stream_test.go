@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestRowsStream(t *testing.T) {
+	integration.TestForEachDB("TestRowsStream", t, testRowsStream)
+}
+
+func testRowsStream(t *testing.T, db *sql.DB, tableName string) {
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a int)", tableName)); err != nil {
+		t.Errorf("Error creating table: %v", err)
+		return
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (1) insert into %s values (2) insert into %s values (3)", tableName, tableName, tableName)); err != nil {
+		t.Errorf("Error inserting values: %v", err)
+		return
+	}
+
+	query := fmt.Sprintf("select a from %s order by a", tableName)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	t.Run("complete", func(t *testing.T) {
+		err := conn.Raw(func(driverConn interface{}) error {
+			c := driverConn.(*Conn)
+
+			rows, _, err := c.DirectExec(context.Background(), query)
+			if err != nil {
+				return fmt.Errorf("error selecting rows: %w", err)
+			}
+			defer rows.Close()
+
+			rowCh, errCh := rows.(*Rows).Stream(context.Background())
+
+			received := [][]driver.Value{}
+			for row := range rowCh {
+				received = append(received, row)
+			}
+
+			if err := <-errCh; err != nil {
+				return fmt.Errorf("error streaming rows: %w", err)
+			}
+
+			expect := [][]driver.Value{{int32(1)}, {int32(2)}, {int32(3)}}
+			if !reflect.DeepEqual(received, expect) {
+				return fmt.Errorf("expected %v, received %v", expect, received)
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		err := conn.Raw(func(driverConn interface{}) error {
+			c := driverConn.(*Conn)
+
+			rows, _, err := c.DirectExec(context.Background(), query)
+			if err != nil {
+				return fmt.Errorf("error selecting rows: %w", err)
+			}
+			defer rows.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			rowCh, errCh := rows.(*Rows).Stream(ctx)
+
+			if _, ok := <-rowCh; !ok {
+				return fmt.Errorf("expected a row before cancelling")
+			}
+			// The remaining rows are not received, hence the
+			// goroutine can only observe the cancellation.
+			cancel()
+
+			if err := <-errCh; !errors.Is(err, context.Canceled) {
+				return fmt.Errorf("expected context.Canceled, received %v", err)
+			}
+
+			rows2, _, err := c.DirectExec(context.Background(), "select 1")
+			if err != nil {
+				return fmt.Errorf("error executing command after cancelling: %w", err)
+			}
+			return rows2.Close()
+		})
+		if err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+}
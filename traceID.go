@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxClientApplNameLength is the maximum length of clientapplname.
+const maxClientApplNameLength = 30
+
+// traceIDKey is the context key of the trace ID set by WithTraceID.
+type traceIDKey struct{}
+
+// WithTraceID returns a copy of ctx carrying the trace or correlation
+// ID id of the application request the commands executed with the
+// returned context belong to.
+//
+// The ID is available to the logger set with
+// Connector.SetContextQueryLogger through TraceIDFromContext. If the
+// property traceid is set it is also sent to the server as
+// clientapplname, see propagateTraceID.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceIDFromContext returns the trace ID set on ctx by WithTraceID.
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(traceIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// propagateTraceID sets the clientapplname of the session to the trace
+// ID of ctx if the property traceid is set, so that the commands of
+// a request can be correlated in monitoring tables such as
+// master..sysprocesses and monProcessLookup.
+//
+// IDs longer than the 30 bytes allowed for clientapplname are
+// truncated. Commands without a trace ID reset clientapplname to an
+// empty string. The command is only sent if the name changes.
+func (c *Conn) propagateTraceID(ctx context.Context) error {
+	if !c.Info.TraceID {
+		return nil
+	}
+
+	id, _ := TraceIDFromContext(ctx)
+	if len(id) > maxClientApplNameLength {
+		id = id[:maxClientApplNameLength]
+	}
+
+	if id == c.sessionTraceID {
+		return nil
+	}
+
+	if err := c.execNoRows(ctx, "set clientapplname "+QuoteLiteral(id)); err != nil {
+		return fmt.Errorf("go-ase: error setting trace ID as clientapplname: %w", err)
+	}
+
+	c.sessionTraceID = id
+	return nil
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTraceIDFromContext(t *testing.T) {
+	cases := map[string]struct {
+		ctx      context.Context
+		expectID string
+		expectOk bool
+	}{
+		"unset": {context.Background(), "", false},
+		"empty": {WithTraceID(context.Background(), ""), "", false},
+		"set":   {WithTraceID(context.Background(), "4bf92f3577b34da6"), "4bf92f3577b34da6", true},
+		"overridden": {
+			WithTraceID(WithTraceID(context.Background(), "a"), "b"),
+			"b", true,
+		},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			id, ok := TraceIDFromContext(cas.ctx)
+			if id != cas.expectID || ok != cas.expectOk {
+				t.Errorf("Expected %q, %t, received %q, %t", cas.expectID, cas.expectOk, id, ok)
+			}
+		})
+	}
+}
+
+func TestConn_propagateTraceID(t *testing.T) {
+	cases := map[string]struct {
+		enabled        bool
+		sessionTraceID string
+		id             string
+	}{
+		"disabled":  {false, "", "a"},
+		"unchanged": {true, "a", "a"},
+		"truncated": {true, "012345678901234567890123456789", "012345678901234567890123456789-suffix"},
+		"unset":     {true, "", ""},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{Info: &Info{TraceID: cas.enabled}, sessionTraceID: cas.sessionTraceID}
+
+			// No command must be sent, the connection has no channel.
+			if err := c.propagateTraceID(WithTraceID(context.Background(), cas.id)); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -22,6 +22,11 @@ var (
 	_ driver.Tx          = (*Transaction)(nil)
 )
 
+// ErrSnapshotIsolationUnsupported is returned when a transaction with
+// sql.LevelSnapshot is started on a server that does not support
+// snapshot isolation.
+var ErrSnapshotIsolationUnsupported = errors.New("go-ase: snapshot isolation is not supported by the server")
+
 // DefaultTxOptions returns default driver.TxOptions.
 func DefaultTxOptions() driver.TxOptions {
 	return driver.TxOptions{
@@ -41,6 +46,25 @@ func (tx Transaction) Name() string {
 	return tx.name
 }
 
+// Conn returns the connection the transaction was started on.
+//
+// database/sql does not expose the driver.Tx of a *sql.Tx. To mix
+// standard transaction control with driver-specific calls start the
+// transaction through the *Conn of a *sql.Conn instead:
+//
+//	conn, err := db.Conn(ctx)
+//	...
+//	err = conn.Raw(func(driverConn interface{}) error {
+//		tx, err := driverConn.(*ase.Conn).NewTransaction(ctx, ase.DefaultTxOptions(), "")
+//		...
+//		_, _, err = tx.Conn().DirectExec(ctx, "exec proc")
+//		...
+//		return tx.Commit()
+//	})
+func (tx Transaction) Conn() *Conn {
+	return tx.conn
+}
+
 // Begin implements the driver.Conn interface.
 func (c *Conn) Begin() (driver.Tx, error) {
 	return c.BeginTx(context.Background(), DefaultTxOptions())
@@ -53,19 +77,58 @@ func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, e
 
 // NewTransaction creates a new transaction.
 func (c *Conn) NewTransaction(ctx context.Context, opts driver.TxOptions, name string) (*Transaction, error) {
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+
 	tx := &Transaction{
 		conn: c,
 		name: name,
 	}
 
-	return tx, tx.begin(ctx, opts)
+	if err := tx.begin(ctx, opts); err != nil {
+		return tx, err
+	}
+	c.trackBegin()
+
+	return tx, nil
+}
+
+// BeginReadOnly starts a transaction for read-only work such as
+// reporting queries.
+//
+// ASE does not support read-only transactions and snapshot isolation
+// is only available on some servers, see begin. Hence the transaction
+// is started with isolation level 0 (read uncommitted) to minimize
+// locking - reads do not acquire shared locks
+// and are not blocked by other transactions, but may return
+// uncommitted changes. Modifications are not prevented.
+func (c *Conn) BeginReadOnly(ctx context.Context) (*Transaction, error) {
+	opts := driver.TxOptions{
+		Isolation: driver.IsolationLevel(sql.LevelReadUncommitted),
+	}
+
+	return c.NewTransaction(ctx, opts, "")
 }
 
+// begin starts the transaction with the isolation level of opts.
+//
+// sql.LevelSnapshot is set with `set transaction isolation level
+// snapshot`, which requires a server supporting multiversion
+// concurrency control, e.g. ASE 16.0 SP03 with in-memory row storage.
+// If the server rejects the command ErrSnapshotIsolationUnsupported is
+// returned.
 func (tx Transaction) begin(ctx context.Context, opts driver.TxOptions) error {
 	if opts.ReadOnly {
 		return errors.New("go-ase: ASE does not support read-only transactions")
 	}
 
+	tx.conn.takeTxAborted()
+
+	if sql.IsolationLevel(opts.Isolation) == sql.LevelSnapshot {
+		return tx.beginSnapshot(ctx)
+	}
+
 	isolationLvl, err := dblib.ASEIsolationLevelFromGo(sql.IsolationLevel(opts.Isolation))
 	if err != nil {
 		return fmt.Errorf("go-ase: error mapping sql.IsolationLevel to ASE isolation level: %w", err)
@@ -75,8 +138,11 @@ func (tx Transaction) begin(ctx context.Context, opts driver.TxOptions) error {
 		return fmt.Errorf("go-ase: sql.IsolationLevel %s has no equivalent ASE isolation level", sql.IsolationLevel(opts.Isolation))
 	}
 
-	if _, _, err := tx.conn.GenericExec(ctx, "begin transaction "+tx.name, nil); err != nil {
-		return fmt.Errorf("go-ase: error initializing transaction: %w", err)
+	// In chained mode the first statement begins the transaction.
+	if !tx.conn.Info.Chained || tx.name != "" {
+		if _, _, err := tx.conn.GenericExec(ctx, "begin transaction "+tx.name, nil); err != nil {
+			return fmt.Errorf("go-ase: error initializing transaction: %w", err)
+		}
 	}
 
 	optIsolationPkg := &tds.OptionCmdPackage{
@@ -84,24 +150,63 @@ func (tx Transaction) begin(ctx context.Context, opts driver.TxOptions) error {
 		Option:    tds.TDS_OPT_ISOLATION,
 		OptionArg: []byte{byte(isolationLvl)},
 	}
-	if err := tx.conn.Channel.QueuePackage(ctx, optIsolationPkg); err != nil {
+	if err := tx.conn.queuePackage(ctx, optIsolationPkg); err != nil {
 		return fmt.Errorf("go-ase: error queueing package: %w", err)
 	}
 
 	return nil
 }
 
+// beginSnapshot starts the transaction with snapshot isolation.
+//
+// The isolation level is set before the transaction begins, so that no
+// transaction is left open if the server does not support it.
+func (tx Transaction) beginSnapshot(ctx context.Context) error {
+	if err := tx.conn.execNoRows(ctx, "set transaction isolation level snapshot"); err != nil {
+		var aseErr *Error
+		if errors.As(err, &aseErr) {
+			return fmt.Errorf("%w: %s", ErrSnapshotIsolationUnsupported, aseErr.Error())
+		}
+		return err
+	}
+
+	// In chained mode the first statement begins the transaction.
+	if !tx.conn.Info.Chained || tx.name != "" {
+		if _, _, err := tx.conn.GenericExec(ctx, "begin transaction "+tx.name, nil); err != nil {
+			return fmt.Errorf("go-ase: error initializing transaction: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // NewTransaction creates a new transaction.
 func (tx Transaction) NewTransaction(ctx context.Context, opts driver.TxOptions) (*Transaction, error) {
 	newTx := &Transaction{
 		conn: tx.conn,
 	}
 
-	return newTx, newTx.begin(ctx, opts)
+	if err := newTx.begin(ctx, opts); err != nil {
+		return newTx, err
+	}
+	tx.conn.trackBegin()
+
+	return newTx, nil
 }
 
 // Commit implements the driver.Tx interface.
+//
+// If the server rolled back the transaction after choosing the
+// connection as deadlock victim an error matching ErrDeadlockVictim is
+// returned without committing. Statements executed after the rollback
+// were not part of the transaction.
 func (tx Transaction) Commit() error {
+	defer tx.conn.trackEnd()
+
+	if tx.conn.takeTxAborted() {
+		return fmt.Errorf("go-ase: error committing transaction: %w", ErrDeadlockVictim)
+	}
+
 	if _, _, err := tx.conn.GenericExec(context.Background(), "commit "+tx.name, nil); err != nil {
 		return fmt.Errorf("go-ase: error committing transaction: %w", err)
 	}
@@ -109,9 +214,51 @@ func (tx Transaction) Commit() error {
 }
 
 // Rollback implements the driver.Tx interface.
+//
+// Transactions the server already rolled back after choosing the
+// connection as deadlock victim are not rolled back again.
 func (tx Transaction) Rollback() error {
+	defer tx.conn.trackEnd()
+
+	if tx.conn.takeTxAborted() {
+		return nil
+	}
+
 	if _, _, err := tx.conn.GenericExec(context.Background(), "rollback "+tx.name, nil); err != nil {
 		return fmt.Errorf("go-ase: error rolling back transaction: %w", err)
 	}
 	return nil
 }
+
+// InTransaction reports if the connection has an open transaction,
+// including transactions started with `begin transaction` through
+// statements or within stored procedures.
+//
+// The state is taken from the status the server reports with the end
+// of every command and reflects whether @@trancount is greater than
+// zero after the last command.
+func (c *Conn) InTransaction() bool {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	return c.inTransaction
+}
+
+// trackTransaction records the transaction state reported by done.
+//
+// Every DonePackage carries TDS_DONE_INXACT while a transaction is
+// open. The channel appends a DonePackage with the status
+// TDS_DONE_FINAL if the server ended a response with a different
+// status - as it does not stem from the server it is skipped.
+func (c *Conn) trackTransaction(done *tds.DonePackage) {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	if done.Status == tds.TDS_DONE_FINAL && c.responseDone {
+		c.responseDone = false
+		return
+	}
+
+	c.inTransaction = done.Status&tds.TDS_DONE_INXACT == tds.TDS_DONE_INXACT
+	c.responseDone = done.Status != tds.TDS_DONE_FINAL && done.Status&tds.TDS_DONE_MORE == 0
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestBeginReadOnly(t *testing.T) {
+	integration.TestForEachDB("TestBeginReadOnly", t, testBeginReadOnly)
+}
+
+func testBeginReadOnly(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		tx, err := c.BeginReadOnly(context.Background())
+		if err != nil {
+			return fmt.Errorf("error starting read-only transaction: %w", err)
+		}
+
+		rows, _, err := c.DirectExec(context.Background(), "select @@isolation")
+		if err != nil {
+			return fmt.Errorf("error selecting isolation level: %w", err)
+		}
+
+		values := make([]driver.Value, 1)
+		if err := rows.Next(values); err != nil {
+			return fmt.Errorf("error reading isolation level: %w", err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("error closing rows: %w", err)
+		}
+
+		if isolation := fmt.Sprint(values[0]); isolation != "0" {
+			return fmt.Errorf("expected isolation level 0, received %s", isolation)
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestInTransaction(t *testing.T) {
+	integration.TestForEachDB("TestInTransaction", t, testInTransaction)
+}
+
+func testInTransaction(t *testing.T, db *sql.DB, tableName string) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Errorf("Error getting connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*Conn)
+
+		steps := []struct {
+			query  string
+			expect bool
+		}{
+			{"select 1", false},
+			{"begin transaction", true},
+			{"select 1", true},
+			{"commit transaction", false},
+			{"begin transaction", true},
+			{"rollback transaction", false},
+		}
+
+		for _, step := range steps {
+			rows, _, err := c.DirectExec(context.Background(), step.query)
+			if err != nil {
+				return fmt.Errorf("error executing %q: %w", step.query, err)
+			}
+
+			if rows != nil {
+				if err := rows.Close(); err != nil {
+					return fmt.Errorf("error closing rows: %w", err)
+				}
+			}
+
+			if c.InTransaction() != step.expect {
+				return fmt.Errorf("expected InTransaction to be %t after %q", step.expect, step.query)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	integration.TestForEachDB("TestSnapshotIsolation", t, testSnapshotIsolation)
+}
+
+func testSnapshotIsolation(t *testing.T, db *sql.DB, tableName string) {
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSnapshot})
+	if err != nil {
+		// Servers without multiversion concurrency control must
+		// report the missing capability.
+		if !errors.Is(err, ErrSnapshotIsolationUnsupported) {
+			t.Errorf("Expected ErrSnapshotIsolationUnsupported, received %v", err)
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Error committing transaction: %v", err)
+	}
+}
+
+func TestDeadlockVictim(t *testing.T) {
+	integration.TestForEachDB("TestDeadlockVictim", t, testDeadlockVictim)
+}
+
+func testDeadlockVictim(t *testing.T, db *sql.DB, tableName string) {
+	tableA, tableB := tableName+"_a", tableName+"_b"
+	for _, table := range []string{tableA, tableB} {
+		if _, err := db.Exec(fmt.Sprintf("create table %s (a int) lock datarows", table)); err != nil {
+			t.Errorf("Error creating table %s: %v", table, err)
+			return
+		}
+		defer db.Exec("drop table " + table)
+
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (1)", table)); err != nil {
+			t.Errorf("Error inserting into %s: %v", table, err)
+			return
+		}
+	}
+
+	tx1, err := db.Begin()
+	if err != nil {
+		t.Errorf("Error beginning first transaction: %v", err)
+		return
+	}
+	defer tx1.Rollback()
+
+	tx2, err := db.Begin()
+	if err != nil {
+		t.Errorf("Error beginning second transaction: %v", err)
+		return
+	}
+	defer tx2.Rollback()
+
+	if _, err := tx1.Exec(fmt.Sprintf("update %s set a = 2", tableA)); err != nil {
+		t.Errorf("Error locking %s: %v", tableA, err)
+		return
+	}
+
+	if _, err := tx2.Exec(fmt.Sprintf("update %s set a = 2", tableB)); err != nil {
+		t.Errorf("Error locking %s: %v", tableB, err)
+		return
+	}
+
+	// Each transaction waits for the lock held by the other, the
+	// server aborts one of them.
+	errs := make(chan error, 1)
+	go func() {
+		_, err := tx1.Exec(fmt.Sprintf("update %s set a = 3", tableB))
+		errs <- err
+	}()
+
+	_, err2 := tx2.Exec(fmt.Sprintf("update %s set a = 3", tableA))
+	err1 := <-errs
+
+	victim, err := tx1, err1
+	if errors.Is(err2, ErrDeadlockVictim) {
+		victim, err = tx2, err2
+	}
+
+	if !errors.Is(err, ErrDeadlockVictim) {
+		t.Errorf("Expected one transaction to receive ErrDeadlockVictim, received %v and %v", err1, err2)
+		return
+	}
+
+	var aseErr *Error
+	if !errors.As(err, &aseErr) {
+		t.Errorf("Expected *Error, received %T: %v", err, err)
+	}
+
+	if err := victim.Commit(); !errors.Is(err, ErrDeadlockVictim) {
+		t.Errorf("Expected commit of the victim to fail with ErrDeadlockVictim, received %v", err)
+	}
+}
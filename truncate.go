@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Truncate removes all rows of the table tableName with `truncate
+// table`.
+//
+// The table name may be qualified with the database and owner, e.g.
+// "db.dbo.orders". Each part is quoted, see QuoteIdentifier, hence
+// names containing dots are not supported.
+//
+// Unlike delete, truncate only logs the deallocation of the pages of
+// the table instead of every row, which makes it considerably faster
+// for large tables. Delete triggers are not fired and tables
+// referenced by foreign keys cannot be truncated. Truncate should be
+// treated as non-transactional - execute it outside of transactions
+// and do not rely on rolling it back.
+func (c *Conn) Truncate(ctx context.Context, tableName string) error {
+	name := quoteQualifiedName(tableName)
+	if err := c.execNoRows(ctx, "truncate table "+name); err != nil {
+		return fmt.Errorf("go-ase: error truncating %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// BulkDelete deletes the rows of the table tableName matching where,
+// e.g. "created < ?", in batches of at most batchSize rows and returns
+// the number of deleted rows. An empty where deletes all rows.
+//
+// Each batch is limited through `set rowcount` and, unless
+// a transaction is open, committed on its own, which avoids filling
+// the log and escalating to table locks when deleting large numbers
+// of rows. If an error occurs the batches before are not rolled back.
+// Within a transaction all batches are part of the transaction.
+//
+// The table name is quoted like by Truncate. The where clause is used
+// as-is, values should be passed as args.
+func (c *Conn) BulkDelete(ctx context.Context, tableName, where string, batchSize int, args ...interface{}) (int64, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("go-ase: invalid batch size %d", batchSize)
+	}
+
+	query := "delete from " + quoteQualifiedName(tableName)
+	if where != "" {
+		query += " where " + where
+	}
+
+	// The row limit is not flagged for resetRowLimit, otherwise
+	// closing the rows of the first batch would reset it.
+	if err := c.setRowCount(ctx, batchSize); err != nil {
+		return 0, err
+	}
+
+	total, err := c.deleteBatches(ctx, query, batchSize, args)
+
+	// The row limit is reset even if ctx is already done. If that
+	// fails it is reset before the connection is reused.
+	if resetErr := c.setRowCount(context.Background(), 0); resetErr != nil {
+		c.rowLimit = true
+		if err == nil {
+			err = resetErr
+		}
+	}
+
+	return total, err
+}
+
+// deleteBatches executes the delete statement query until it deletes
+// less than batchSize rows.
+func (c *Conn) deleteBatches(ctx context.Context, query string, batchSize int, args []interface{}) (int64, error) {
+	var total int64
+
+	for {
+		rows, result, err := c.DirectExec(ctx, query, args...)
+		if err != nil {
+			return total, fmt.Errorf("go-ase: error deleting batch after %d deleted rows: %w", total, err)
+		}
+
+		if err := rows.Close(); err != nil {
+			return total, fmt.Errorf("go-ase: error closing rows: %w", err)
+		}
+
+		count, err := result.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("go-ase: error retrieving the number of deleted rows: %w", err)
+		}
+		total += count
+
+		if count < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// quoteQualifiedName quotes each dot-separated part of name.
+func quoteQualifiedName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		// Omitted parts, e.g. the owner in "db..table", stay empty.
+		if part != "" {
+			parts[i] = QuoteIdentifier(part)
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import "testing"
+
+func TestQuoteQualifiedName(t *testing.T) {
+	cases := map[string]struct {
+		name   string
+		expect string
+	}{
+		"table":         {"orders", "[orders]"},
+		"owner":         {"dbo.orders", "[dbo].[orders]"},
+		"database":      {"db.dbo.orders", "[db].[dbo].[orders]"},
+		"omitted owner": {"db..orders", "[db]..[orders]"},
+		"brackets":      {"my]table", "[my]]table]"},
+		"temporary":     {"#orders", "[#orders]"},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := quoteQualifiedName(cas.name); recv != cas.expect {
+				t.Errorf("Expected %q, received %q", cas.expect, recv)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/SAP/go-dblib/asetypes"
+	"github.com/SAP/go-dblib/tds"
+)
+
+// ErrParamTruncated is returned when a character or binary argument is
+// longer than the parameter it is bound to, unless the property
+// allowtruncation is set.
+var ErrParamTruncated = errors.New("go-ase: argument exceeds the length of the parameter")
+
+// checkLength returns ErrParamTruncated if value is longer than the
+// maximum length of the character or binary format fieldFmt.
+//
+// With the property allowtruncation the value is truncated to the
+// maximum length instead, strings at a character boundary of UTF-8.
+func (c *Conn) checkLength(fieldFmt tds.FieldFmt, value driver.Value) (driver.Value, error) {
+	switch fieldFmt.DataType() {
+	case asetypes.CHAR, asetypes.VARCHAR, asetypes.LONGCHAR,
+		asetypes.BINARY, asetypes.VARBINARY, asetypes.LONGBINARY:
+	default:
+		return value, nil
+	}
+
+	maxLength := int(fieldFmt.MaxLength())
+	if maxLength <= 0 {
+		return value, nil
+	}
+
+	var length int
+	switch typed := value.(type) {
+	case string:
+		length = len(typed)
+	case []byte:
+		length = len(typed)
+	default:
+		return value, nil
+	}
+
+	if length <= maxLength {
+		return value, nil
+	}
+
+	if !c.Info.AllowTruncation {
+		return nil, fmt.Errorf("%w: %d bytes exceed the maximum length of %d bytes of %s",
+			ErrParamTruncated, length, maxLength, fieldFmt.DataType())
+	}
+
+	switch typed := value.(type) {
+	case string:
+		truncated, _ := splitUTF8([]byte(typed[:maxLength]))
+		return string(truncated), nil
+	default:
+		return typed.([]byte)[:maxLength], nil
+	}
+}
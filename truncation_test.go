@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+func TestConn_checkLength(t *testing.T) {
+	cases := map[string]struct {
+		dataType        asetypes.DataType
+		maxLength       int64
+		value           driver.Value
+		allowTruncation bool
+		expect          driver.Value
+		expectErr       bool
+	}{
+		"varchar below":          {asetypes.VARCHAR, 5, "abcd", false, "abcd", false},
+		"varchar at limit":       {asetypes.VARCHAR, 5, "abcde", false, "abcde", false},
+		"varchar exceeding":      {asetypes.VARCHAR, 5, "abcdef", false, nil, true},
+		"varchar truncated":      {asetypes.VARCHAR, 5, "abcdef", true, "abcde", false},
+		"varchar multibyte":      {asetypes.VARCHAR, 5, "abcdä", true, "abcd", false},
+		"char exceeding":         {asetypes.CHAR, 1, "YN", false, nil, true},
+		"longchar at limit":      {asetypes.LONGCHAR, 300, string(make([]byte, 300)), false, string(make([]byte, 300)), false},
+		"longchar exceeding":     {asetypes.LONGCHAR, 300, string(make([]byte, 301)), false, nil, true},
+		"varbinary at limit":     {asetypes.VARBINARY, 2, []byte{1, 2}, false, []byte{1, 2}, false},
+		"varbinary exceeding":    {asetypes.VARBINARY, 2, []byte{1, 2, 3}, false, nil, true},
+		"varbinary truncated":    {asetypes.VARBINARY, 2, []byte{1, 2, 3}, true, []byte{1, 2}, false},
+		"longbinary exceeding":   {asetypes.LONGBINARY, 300, make([]byte, 301), false, nil, true},
+		"int not checked":        {asetypes.INTN, 4, int32(123456), false, int32(123456), false},
+		"varchar null unchecked": {asetypes.VARCHAR, 5, nil, false, nil, false},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			fieldFmt, err := newFieldFmt(cas.dataType, cas.maxLength)
+			if err != nil {
+				t.Errorf("Error creating field format: %v", err)
+				return
+			}
+
+			c := &Conn{Info: &Info{AllowTruncation: cas.allowTruncation}}
+
+			recv, err := c.checkLength(fieldFmt, cas.value)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if err != nil {
+				if !errors.Is(err, ErrParamTruncated) {
+					t.Errorf("Expected ErrParamTruncated, received %v", err)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(recv, cas.expect) {
+				t.Errorf("Expected %v, received %v", cas.expect, recv)
+			}
+		})
+	}
+}
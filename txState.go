@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+)
+
+// TxMode describes how the open transaction of a connection was
+// started.
+type TxMode int
+
+// Recognized values for TxState.Mode.
+const (
+	// TxNone reports that no transaction is open.
+	TxNone TxMode = iota
+	// TxExplicit reports a transaction started through the driver,
+	// e.g. with database/sql's BeginTx, or with `begin transaction`.
+	TxExplicit
+	// TxImplicit reports a transaction a statement started in
+	// chained mode outside of a transaction started through the
+	// driver.
+	TxImplicit
+)
+
+// String implements the fmt.Stringer interface.
+func (mode TxMode) String() string {
+	switch mode {
+	case TxNone:
+		return "none"
+	case TxExplicit:
+		return "explicit"
+	case TxImplicit:
+		return "implicit"
+	default:
+		return fmt.Sprintf("TxMode(%d)", int(mode))
+	}
+}
+
+// TxState is the transaction state of a connection.
+type TxState struct {
+	Mode TxMode
+	// Level is the nesting level of the transaction, @@trancount.
+	Level int
+}
+
+// TransactionState returns the transaction state of the connection
+// after the last command.
+//
+// Whether a transaction is open is taken from the status the server
+// reports with the end of every command, see InTransaction. Only if
+// a transaction is open the nesting level and the chained mode are
+// selected from the server.
+//
+// Transactions started through the driver are reported as explicit,
+// also in chained mode, where the transaction only begins with the
+// first statement - until then the level is zero. A transaction
+// a statement started in chained mode otherwise is implicit and
+// should not be committed by code that did not start it.
+func (c *Conn) TransactionState(ctx context.Context) (TxState, error) {
+	c.sessionLock.Lock()
+	inTransaction, txDepth := c.inTransaction, c.txDepth
+	c.sessionLock.Unlock()
+
+	if !inTransaction {
+		if txDepth > 0 {
+			return TxState{Mode: TxExplicit}, nil
+		}
+		return TxState{Mode: TxNone}, nil
+	}
+
+	rows, _, err := c.language(ctx, "select @@trancount, @@tranchained")
+	if err != nil {
+		return TxState{}, fmt.Errorf("go-ase: error selecting transaction state: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 2)
+	if err := rows.Next(values); err != nil {
+		return TxState{}, fmt.Errorf("go-ase: error reading transaction state: %w", err)
+	}
+
+	level, err := txStateValue(values[0])
+	if err != nil {
+		return TxState{}, fmt.Errorf("go-ase: error reading @@trancount: %w", err)
+	}
+
+	chained, err := txStateValue(values[1])
+	if err != nil {
+		return TxState{}, fmt.Errorf("go-ase: error reading @@tranchained: %w", err)
+	}
+
+	return TxState{Mode: txMode(txDepth, chained == 1), Level: level}, nil
+}
+
+// txStateValue returns the integer value of a global variable selected
+// by TransactionState.
+func txStateValue(value driver.Value) (int, error) {
+	switch typed := value.(type) {
+	case int32:
+		return int(typed), nil
+	case int64:
+		return int(typed), nil
+	default:
+		return 0, fmt.Errorf("unexpected value %v of type %T, expected an integer", value, value)
+	}
+}
+
+// txMode returns the mode of an open transaction.
+func txMode(txDepth int, chained bool) TxMode {
+	if txDepth == 0 && chained {
+		return TxImplicit
+	}
+	return TxExplicit
+}
+
+// trackBegin records that a transaction was started through the
+// driver.
+func (c *Conn) trackBegin() {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	c.txDepth++
+}
+
+// trackEnd records that a transaction started through the driver was
+// committed or rolled back.
+func (c *Conn) trackEnd() {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	if c.txDepth > 0 {
+		c.txDepth--
+	}
+}
+
+// resetTxTracking records that all transactions of the connection were
+// rolled back.
+func (c *Conn) resetTxTracking() {
+	c.sessionLock.Lock()
+	defer c.sessionLock.Unlock()
+
+	c.txDepth = 0
+	c.txAborted = false
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+	"testing"
+)
+
+func TestTxMode(t *testing.T) {
+	cases := map[string]struct {
+		txDepth int
+		chained bool
+		expect  TxMode
+	}{
+		"unchained":        {0, false, TxExplicit},
+		"unchained driver": {1, false, TxExplicit},
+		"chained":          {0, true, TxImplicit},
+		"chained driver":   {1, true, TxExplicit},
+		"chained nested":   {2, true, TxExplicit},
+		"unchained nested": {2, false, TxExplicit},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if recv := txMode(cas.txDepth, cas.chained); recv != cas.expect {
+				t.Errorf("Expected %s, received %s", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestTxStateValue(t *testing.T) {
+	cases := map[string]struct {
+		value  driver.Value
+		expect int
+		err    bool
+	}{
+		"int32":  {int32(2), 2, false},
+		"int64":  {int64(1), 1, false},
+		"string": {"1", 0, true},
+		"nil":    {nil, 0, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := txStateValue(cas.value)
+			if (err != nil) != cas.err {
+				t.Errorf("Expected error %t, received %v", cas.err, err)
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %d, received %d", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestConn_TransactionState(t *testing.T) {
+	cases := map[string]struct {
+		txDepth int
+		expect  TxState
+	}{
+		"none":           {0, TxState{Mode: TxNone}},
+		"driver chained": {1, TxState{Mode: TxExplicit}},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			c := &Conn{sessionLock: &sync.Mutex{}, txDepth: cas.txDepth}
+
+			recv, err := c.TransactionState(context.Background())
+			if err != nil {
+				t.Errorf("Error retrieving transaction state: %v", err)
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %+v, received %+v", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestConn_trackBegin(t *testing.T) {
+	c := &Conn{sessionLock: &sync.Mutex{}}
+
+	c.trackBegin()
+	c.trackBegin()
+	c.trackEnd()
+
+	if c.txDepth != 1 {
+		t.Errorf("Expected depth 1, received %d", c.txDepth)
+	}
+
+	c.trackEnd()
+	c.trackEnd()
+
+	if c.txDepth != 0 {
+		t.Errorf("Expected depth 0, received %d", c.txDepth)
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// WarmupError is returned by Warmup if connections could not be
+// opened or pinged.
+type WarmupError struct {
+	// Errors are the errors of the failed connections.
+	Errors []error
+	// Total is the number of connections Warmup attempted to open.
+	Total int
+}
+
+// Error implements the error interface.
+func (e *WarmupError) Error() string {
+	return fmt.Sprintf("go-ase: %d of %d connections failed to warm up, first error: %v",
+		len(e.Errors), e.Total, e.Errors[0])
+}
+
+// Unwrap returns the first error.
+func (e *WarmupError) Unwrap() error {
+	return e.Errors[0]
+}
+
+// Warmup opens and pings n connections of db concurrently to fill the
+// connection pool before serving traffic.
+//
+// The connections are returned to the pool after all of them were
+// pinged. database/sql keeps only up to two idle connections by default,
+// db.SetMaxIdleConns must be set to at least n to retain all of them.
+//
+// If some connections fail a *WarmupError is returned.
+func Warmup(ctx context.Context, db *sql.DB, n int) error {
+	if n <= 0 {
+		return nil
+	}
+
+	conns := make([]*sql.Conn, n)
+	errs := make([]error, n)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			conn, err := db.Conn(ctx)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			conns[i] = conn
+
+			errs[i] = conn.PingContext(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	// The connections are held until all are pinged, otherwise
+	// database/sql would reuse the same connections.
+	for _, conn := range conns {
+		if conn != nil {
+			conn.Close()
+		}
+	}
+
+	warmupErr := &WarmupError{Total: n}
+	for _, err := range errs {
+		if err != nil {
+			warmupErr.Errors = append(warmupErr.Errors, err)
+		}
+	}
+
+	if len(warmupErr.Errors) > 0 {
+		return warmupErr
+	}
+
+	return nil
+}
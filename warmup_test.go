@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build integration
+
+package ase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/SAP/go-dblib/integration"
+)
+
+func TestWarmup(t *testing.T) {
+	integration.TestForEachDB("TestWarmup", t, testWarmup)
+}
+
+func testWarmup(t *testing.T, db *sql.DB, tableName string) {
+	db.SetMaxIdleConns(4)
+
+	if err := Warmup(context.Background(), db, 4); err != nil {
+		t.Errorf("Error warming up connections: %v", err)
+		return
+	}
+
+	if idle := db.Stats().Idle; idle < 4 {
+		t.Errorf("Expected at least 4 idle connections, received %d", idle)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Warmup(ctx, db, 2)
+
+	var warmupErr *WarmupError
+	if !errors.As(err, &warmupErr) {
+		t.Errorf("Expected *WarmupError with cancelled context, received %v", err)
+		return
+	}
+
+	if len(warmupErr.Errors) != 2 || warmupErr.Total != 2 {
+		t.Errorf("Expected 2 of 2 connections to fail, received %d of %d", len(warmupErr.Errors), warmupErr.Total)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error to wrap context.Canceled, received %v", err)
+	}
+}
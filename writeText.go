@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/SAP/go-dblib/asetypes"
+)
+
+// writeTextChunkSize is the number of bytes sent per updatetext
+// command.
+const writeTextChunkSize = 16 * 1024
+
+// TextPtr returns the text pointer of the text, unitext or image column
+// column of the row of the table tableName selected by where, e.g.
+// "id = ?", for use with WriteText and AppendText.
+//
+// The text pointer is NULL until a value other than NULL has been
+// stored in the column, e.g. an empty string.
+//
+// The table and column names are used as-is and must be quoted by the
+// caller if required, see QuoteIdentifier.
+func (c *Conn) TextPtr(ctx context.Context, tableName, column, where string, args ...interface{}) ([]byte, error) {
+	query := fmt.Sprintf("select textptr(%s) from %s where %s", column, tableName, where)
+
+	rows, _, err := c.DirectExec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("go-ase: error selecting text pointer: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("go-ase: no row selected by %q", where)
+		}
+		return nil, fmt.Errorf("go-ase: error reading text pointer: %w", err)
+	}
+
+	ptr, ok := values[0].([]byte)
+	if !ok || len(ptr) == 0 {
+		return nil, fmt.Errorf("go-ase: text pointer of %s is NULL, the column must hold a value", column)
+	}
+
+	return ptr, nil
+}
+
+// WriteText replaces the value of the text, unitext or image column
+// column identified by textptr with the data read from r, see TextPtr.
+//
+// The data is streamed in chunks through updatetext commands instead
+// of loading the whole value into memory. The changes are logged.
+// Values of text and unitext columns are split at character
+// boundaries of UTF-8.
+//
+// If an error occurs after the first chunk was written the column
+// holds a partial value, hence WriteText should be called within
+// a transaction.
+//
+// The table and column names are used as-is and must be quoted by the
+// caller if required, see QuoteIdentifier.
+func (c *Conn) WriteText(ctx context.Context, tableName, column string, textptr []byte, r io.Reader) error {
+	return c.updateText(ctx, tableName, column, textptr, r, false)
+}
+
+// AppendText appends the data read from r to the value of the text,
+// unitext or image column column identified by textptr, see WriteText.
+func (c *Conn) AppendText(ctx context.Context, tableName, column string, textptr []byte, r io.Reader) error {
+	return c.updateText(ctx, tableName, column, textptr, r, true)
+}
+
+// updateText streams the data of r through updatetext commands.
+func (c *Conn) updateText(ctx context.Context, tableName, column string, textptr []byte, r io.Reader, appendData bool) error {
+	binary, err := c.isBinaryLOB(ctx, tableName, column)
+	if err != nil {
+		return err
+	}
+
+	target := fmt.Sprintf("%s.%s 0x%s", tableName, column, hex.EncodeToString(textptr))
+
+	buf := make([]byte, writeTextChunkSize)
+	var pending []byte
+
+	for first := true; ; first = false {
+		n, readErr := io.ReadFull(r, buf[len(pending):])
+		if readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("go-ase: error reading data: %w", readErr)
+		}
+		last := readErr != nil
+
+		copy(buf, pending)
+		chunk := buf[:len(pending)+n]
+
+		pending = nil
+		if !binary && !last {
+			chunk, pending = splitUTF8(chunk)
+			pending = append([]byte(nil), pending...)
+		}
+
+		// The first command replaces the value from offset 0 to the
+		// end, the following append to it.
+		offsets := "null 0"
+		if first && !appendData {
+			offsets = "0 null"
+		}
+
+		if len(chunk) > 0 || (first && !appendData) {
+			query := fmt.Sprintf("updatetext %s %s with log", target, offsets)
+			if len(chunk) > 0 {
+				query += " " + lobLiteral(chunk, binary)
+			}
+
+			// execNoRows is not used as it would include the data in
+			// the error.
+			rows, _, err := c.language(ctx, query)
+			if err != nil {
+				return fmt.Errorf("go-ase: error executing updatetext: %w", err)
+			}
+			if err := rows.Close(); err != nil {
+				return fmt.Errorf("go-ase: error closing rows of updatetext: %w", err)
+			}
+		}
+
+		if last {
+			return nil
+		}
+	}
+}
+
+// isBinaryLOB reports if the column is an image column.
+func (c *Conn) isBinaryLOB(ctx context.Context, tableName, column string) (bool, error) {
+	rows, _, err := c.language(ctx, fmt.Sprintf("select %s from %s where 1 = 0", column, tableName))
+	if err != nil {
+		return false, fmt.Errorf("go-ase: error retrieving type of %s.%s: %w", tableName, column, err)
+	}
+	defer rows.Close()
+
+	fieldFmts := rows.(*Rows).RowFmt
+	if fieldFmts == nil || len(fieldFmts.Fmts) != 1 {
+		return false, fmt.Errorf("go-ase: error retrieving type of %s.%s: no column format received", tableName, column)
+	}
+
+	switch dataType := fieldFmts.Fmts[0].DataType(); dataType {
+	case asetypes.IMAGE:
+		return true, nil
+	case asetypes.TEXT, asetypes.UNITEXT:
+		return false, nil
+	default:
+		return false, fmt.Errorf("go-ase: %s.%s is of type %s, expected text, unitext or image", tableName, column, dataType)
+	}
+}
+
+// splitUTF8 splits b before a trailing incomplete UTF-8 sequence.
+func splitUTF8(b []byte) ([]byte, []byte) {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+
+		if !utf8.FullRune(b[i:]) {
+			return b[:i], b[i:]
+		}
+		break
+	}
+
+	return b, nil
+}
+
+// lobLiteral returns data as a literal for image or text columns.
+func lobLiteral(data []byte, binary bool) string {
+	if binary {
+		return "0x" + hex.EncodeToString(data)
+	}
+	return QuoteLiteral(string(data))
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitUTF8(t *testing.T) {
+	cases := map[string]struct {
+		data         []byte
+		expectChunk  []byte
+		expectRemain []byte
+	}{
+		"ascii":           {[]byte("abc"), []byte("abc"), nil},
+		"complete":        {[]byte("abé"), []byte("abé"), nil},
+		"incomplete two":  {[]byte("ab\xc3"), []byte("ab"), []byte("\xc3")},
+		"incomplete four": {[]byte("a\xf0\x9f\x98"), []byte("a"), []byte("\xf0\x9f\x98")},
+		"empty":           {[]byte{}, []byte{}, nil},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			chunk, remain := splitUTF8(cas.data)
+			if !bytes.Equal(chunk, cas.expectChunk) {
+				t.Errorf("Expected chunk %q, received %q", cas.expectChunk, chunk)
+			}
+			if !bytes.Equal(remain, cas.expectRemain) {
+				t.Errorf("Expected remainder %q, received %q", cas.expectRemain, remain)
+			}
+		})
+	}
+}
+
+func TestLOBLiteral(t *testing.T) {
+	cases := map[string]struct {
+		data   []byte
+		binary bool
+		expect string
+	}{
+		"image": {[]byte{0x01, 0xff}, true, "0x01ff"},
+		"text":  {[]byte("it's"), false, "'it''s'"},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			if literal := lobLiteral(cas.data, cas.binary); literal != cas.expect {
+				t.Errorf("Expected %q, received %q", cas.expect, literal)
+			}
+		})
+	}
+}
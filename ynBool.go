@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Interface satisfaction checks.
+var (
+	_ driver.Valuer = YNBool(false)
+	_ sql.Scanner   = (*YNBool)(nil)
+)
+
+// YNBool is a boolean stored in a character column as 'Y' or 'N', as
+// is common in legacy schemas:
+//
+//	var active ase.YNBool
+//	db.QueryRow("select active from users where id = ?", id).Scan(&active)
+//	...
+//	db.Exec("update users set active = ? where id = ?", ase.YNBool(false), id)
+//
+// Values are bound as 'Y' or 'N'. Scanning accepts 'Y', 'N', 'T', 'F',
+// '1' and '0' regardless of case and surrounding blanks and returns an
+// error for all other values, including NULL.
+type YNBool bool
+
+// Value implements the driver.Valuer interface.
+func (b YNBool) Value() (driver.Value, error) {
+	if b {
+		return "Y", nil
+	}
+	return "N", nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (b *YNBool) Scan(src interface{}) error {
+	var s string
+
+	switch typed := src.(type) {
+	case string:
+		s = typed
+	case []byte:
+		s = string(typed)
+	case nil:
+		return fmt.Errorf("go-ase: cannot scan NULL into YNBool")
+	default:
+		return fmt.Errorf("go-ase: cannot scan %T into YNBool", src)
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "Y", "T", "1":
+		*b = true
+	case "N", "F", "0":
+		*b = false
+	default:
+		return fmt.Errorf("go-ase: cannot scan %q into YNBool, expected one of Y, N, T, F, 1 or 0", s)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2021 SAP SE
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ase
+
+import (
+	"testing"
+)
+
+func TestYNBool_Value(t *testing.T) {
+	cases := map[string]struct {
+		b      YNBool
+		expect string
+	}{
+		"true":  {true, "Y"},
+		"false": {false, "N"},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			recv, err := cas.b.Value()
+			if err != nil {
+				t.Errorf("Received unexpected error: %v", err)
+				return
+			}
+
+			if recv != cas.expect {
+				t.Errorf("Expected %q, received %v", cas.expect, recv)
+			}
+		})
+	}
+}
+
+func TestYNBool_Scan(t *testing.T) {
+	cases := map[string]struct {
+		src       interface{}
+		expect    YNBool
+		expectErr bool
+	}{
+		"Y":          {"Y", true, false},
+		"y":          {"y", true, false},
+		"N":          {"N", false, false},
+		"n":          {"n", false, false},
+		"T":          {"T", true, false},
+		"f":          {"f", false, false},
+		"1":          {"1", true, false},
+		"0":          {"0", false, false},
+		"padded":     {"Y ", true, false},
+		"bytes":      {[]byte("N"), false, false},
+		"null":       {nil, false, true},
+		"empty":      {"", false, true},
+		"unexpected": {"X", false, true},
+		"word":       {"yes", false, true},
+		"int":        {int64(1), false, true},
+	}
+
+	for title, cas := range cases {
+		t.Run(title, func(t *testing.T) {
+			b := !cas.expect
+
+			err := b.Scan(cas.src)
+			if (err != nil) != cas.expectErr {
+				t.Errorf("Expected error %t, received %v", cas.expectErr, err)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if b != cas.expect {
+				t.Errorf("Expected %t, received %t", cas.expect, b)
+			}
+		})
+	}
+}